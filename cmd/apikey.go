@@ -0,0 +1,153 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// apikeyAuthFile csv file which new api keys will be appended to
+var apikeyAuthFile string
+
+// apikeyAction action on an existing api key, with auth context of
+// the account which owns the key, nil result means nothing to output.
+type apikeyAction func(client *ngerest.APIClient, authCtx context.Context,
+	key *models.APIKeyInfo) (*models.APIKeyInfo, error)
+
+// findAPIKeys find api keys by key ID in all accounts of auth info
+func findAPIKeys(keyIDs []string) []*models.APIKeyInfo {
+	targets := make(map[string]bool)
+	for _, id := range keyIDs {
+		targets[id] = true
+	}
+
+	ids, err := auths.AuthIDs()
+	if err != nil {
+		common.PrintError("Load auth failed", err)
+		return nil
+	}
+
+	var found []*models.APIKeyInfo
+
+	for _, id := range ids {
+		keys, err := models.GetAPIKeys(auths.GetAuthContext(nil, id),
+			common.GetFullPath(), id, false)
+		if err != nil {
+			common.PrintError(fmt.Sprintf("Get api keys failed[%s]", id), err)
+			continue
+		}
+
+		for _, key := range keys {
+			if targets[key.ID] {
+				delete(targets, key.ID)
+				found = append(found, key)
+			}
+		}
+	}
+
+	for id := range targets {
+		logger.Warn("API key not found.", zap.String("id", id))
+	}
+
+	return found
+}
+
+// runAPIKeyAction run action on each api key specified by key IDs
+func runAPIKeyAction(action string, keyIDs []string, run apikeyAction) {
+	checkLoginInfo()
+
+	client, err := clientHub.GetClient(common.GetBaseHost())
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	var results []*models.APIKeyInfo
+
+	for _, key := range findAPIKeys(keyIDs) {
+		result, err := run(client, auths.GetAuthContext(nil, key.Identity), key)
+		if err != nil {
+			common.PrintError(fmt.Sprintf("%s failed[%s]", action, key.ID), err)
+			continue
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	outputAPIKeys(results)
+}
+
+// outputAPIKeys print api keys, and append new key pairs to auth file
+// if --write specified, so they can be used by "--auth" directly.
+func outputAPIKeys(keys []*models.APIKeyInfo) {
+	formatter := newFormatter()
+
+	var newAuths []*models.Authentication
+
+	for _, key := range keys {
+		if err := formatter.Format(key); err != nil {
+			logger.Warn(err.Error())
+		}
+
+		if key.Secret != "" {
+			newAuths = append(newAuths, key.Authentication())
+		}
+	}
+
+	flushFormatter(formatter)
+
+	if apikeyAuthFile == "" || len(newAuths) < 1 {
+		return
+	}
+
+	if err := models.WriteAuthFile(apikeyAuthFile, newAuths); err != nil {
+		logger.Error("Write auth file failed.", zap.Error(err))
+		return
+	}
+
+	logger.Info("API keys written.",
+		zap.String("file", apikeyAuthFile), zap.Int("count", len(newAuths)))
+}
+
+// apikeyCmd represents the apikey command
+var apikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "apikey functions",
+	Long: `All functions for APIKey table.
+Keys are managed in all accounts of auth info, key's secret
+is only shown once when it's created.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("apikey called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apikeyCmd)
+}
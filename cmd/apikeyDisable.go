@@ -0,0 +1,53 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// apikeyDisableCmd represents the apikeyDisable command
+var apikeyDisableCmd = &cobra.Command{
+	Use:   "disable keyID [keyID...]",
+	Short: "Disable api keys.",
+	Long:  `Disable api keys by key ID, keys are looked up in all accounts.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAPIKeyAction("Disable api key", args, func(client *ngerest.APIClient,
+			authCtx context.Context, key *models.APIKeyInfo) (*models.APIKeyInfo, error) {
+			result, rsp, err := client.APIKey.APIKeyDisable(authCtx, key.ID)
+			if err != nil {
+				return nil, common.NewAPIError("", err, rsp)
+			}
+
+			converted := models.ConvertAPIKey(key.Identity, &result)
+			converted.Permissions = key.Permissions
+
+			return converted, nil
+		})
+	},
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeyDisableCmd)
+}
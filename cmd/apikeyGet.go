@@ -0,0 +1,60 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+var apikeyGetReverse bool
+
+// apikeyGetCmd represents the apikeyGet command
+var apikeyGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get api keys.",
+	Long:  `Get api keys of all accounts, secrets are not shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		queryAccounts("Get api keys", func(client *ngerest.APIClient,
+			id string, authCtx context.Context) ([]interface{}, error) {
+			keys, err := models.GetAPIKeys(
+				authCtx, common.GetFullPath(), id, apikeyGetReverse)
+			if err != nil {
+				return nil, err
+			}
+
+			rows := make([]interface{}, len(keys))
+			for idx, key := range keys {
+				rows[idx] = key
+			}
+
+			return rows, nil
+		})
+	},
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeyGetCmd)
+
+	apikeyGetCmd.Flags().BoolVar(
+		&apikeyGetReverse, "reverse", false, "Get keys newest first.")
+}
@@ -0,0 +1,122 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+type apikeyNewArgs struct {
+	name        string
+	cidr        string
+	permissions models.FlagStrings
+	disabled    bool
+	count       int
+}
+
+var apikeyNewVariables apikeyNewArgs
+
+// apikeyNewCmd represents the apikeyNew command
+var apikeyNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Create new api keys.",
+	Long: `Create new api keys for all accounts in auth info.
+Use --write to append new key pairs to csv file in "--auth" format,
+so keys can be provisioned for lots of accounts.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if apikeyNewVariables.count < 1 {
+			return common.ErrCount
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		permissions := models.Permissions(apikeyNewVariables.permissions.Values())
+
+		ids, err := auths.AuthIDs()
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		var keys []*models.APIKeyInfo
+
+		for _, id := range ids {
+			for idx := 0; idx < apikeyNewVariables.count; idx++ {
+				name := apikeyNewVariables.name
+				if name != "" && apikeyNewVariables.count > 1 {
+					name = fmt.Sprintf("%s-%d", name, idx+1)
+				}
+
+				key, rsp, err := client.APIKey.APIKeyNew(
+					auths.GetAuthContext(nil, id), models.NewAPIKeyOpts(
+						name, apikeyNewVariables.cidr, permissions,
+						!apikeyNewVariables.disabled))
+				if err != nil {
+					common.PrintError(
+						fmt.Sprintf("Create api key failed[%s]", id),
+						common.NewAPIError("", err, rsp))
+					break
+				}
+
+				converted := models.ConvertAPIKey(id, &key)
+				converted.Permissions = permissions
+
+				keys = append(keys, converted)
+			}
+		}
+
+		outputAPIKeys(keys)
+	},
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeyNewCmd)
+
+	apikeyNewCmd.Flags().StringVar(
+		&apikeyNewVariables.name, "name", "",
+		"Key name, suffixed with sequence if --count > 1.")
+	apikeyNewCmd.Flags().StringVar(
+		&apikeyNewVariables.cidr, "cidr", "",
+		"CIDR block to restrict key's source address.")
+	apikeyNewCmd.Flags().Var(
+		&apikeyNewVariables.permissions, "permissions",
+		"Key permissions, comma separated, e.g. order,orderCancel.")
+	apikeyNewCmd.Flags().BoolVar(
+		&apikeyNewVariables.disabled, "disabled", false,
+		"Create keys in disabled state.")
+	apikeyNewCmd.Flags().IntVar(
+		&apikeyNewVariables.count, "count", 1,
+		"Keys count to be created for each account.")
+	apikeyNewCmd.Flags().StringVar(
+		&apikeyAuthFile, "write", "",
+		"Append new key pairs to csv file in \"--auth\" format.")
+}
@@ -0,0 +1,58 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// apikeyRemoveCmd represents the apikeyRemove command
+var apikeyRemoveCmd = &cobra.Command{
+	Use:   "remove keyID [keyID...]",
+	Short: "Revoke api keys.",
+	Long: `Revoke api keys by key ID, keys are looked up in all accounts.
+Revoked keys can not be restored.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAPIKeyAction("Remove api key", args, func(client *ngerest.APIClient,
+			authCtx context.Context, key *models.APIKeyInfo) (*models.APIKeyInfo, error) {
+			result, rsp, err := client.APIKey.APIKeyRemove(authCtx, key.ID)
+			if err != nil {
+				return nil, common.NewAPIError("", err, rsp)
+			}
+
+			logger.Info("API key removed.", zap.String("identity", key.Identity),
+				zap.String("id", key.ID), zap.Bool("success", result.Success))
+
+			return nil, nil
+		})
+	},
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeyRemoveCmd)
+}
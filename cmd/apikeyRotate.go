@@ -0,0 +1,72 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// apikeyRotateCmd represents the apikeyRotate command
+var apikeyRotateCmd = &cobra.Command{
+	Use:   "rotate keyID [keyID...]",
+	Short: "Rotate api keys.",
+	Long: `Rotate api keys by key ID, a new key with the same name, cidr
+& permissions is created, then the old key is revoked.
+Use --write to append new key pairs to csv file in "--auth" format.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runAPIKeyAction("Rotate api key", args, func(client *ngerest.APIClient,
+			authCtx context.Context, key *models.APIKeyInfo) (*models.APIKeyInfo, error) {
+			result, rsp, err := client.APIKey.APIKeyNew(authCtx, models.NewAPIKeyOpts(
+				key.Name, key.Cidr, key.Permissions, key.Enabled))
+			if err != nil {
+				return nil, common.NewAPIError("", err, rsp)
+			}
+
+			converted := models.ConvertAPIKey(key.Identity, &result)
+			converted.Permissions = key.Permissions
+
+			if _, rsp, err := client.APIKey.APIKeyRemove(authCtx, key.ID); err != nil {
+				common.PrintError("Revoke old api key failed",
+					common.NewAPIError("", err, rsp))
+			} else {
+				logger.Info("API key rotated.", zap.String("identity", key.Identity),
+					zap.String("old", key.ID), zap.String("new", converted.ID))
+			}
+
+			return converted, nil
+		})
+	},
+}
+
+func init() {
+	apikeyCmd.AddCommand(apikeyRotateCmd)
+
+	apikeyRotateCmd.Flags().StringVar(
+		&apikeyAuthFile, "write", "",
+		"Append new key pairs to csv file in \"--auth\" format.")
+}
@@ -0,0 +1,41 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "auth functions",
+	Long: `All functions for saved auth info in "auths.yaml".
+Accounts are saved in named profiles, each profile can have many accounts
+in many hosts, accounts of profile in current host are used in round robin.
+Passwords & api secrets in "auths.yaml" are sealed by "keystore" in
+config dir, keystore is generated on first run and can be protected by
+a master passphrase, which is read from env ` + keystorePassphraseEnv + `
+or stdin.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("auth called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
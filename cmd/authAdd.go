@@ -0,0 +1,117 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+type authAddArgs struct {
+	key    string
+	secret string
+}
+
+var authAddVariables authAddArgs
+
+// authAddCmd represents the authAdd command
+var authAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add account to profile.",
+	Long: `Add account in current host to profile specified by --profile or default,
+account is either identity & password specified by --id & --pass, or api key
+specified by --key. Password & api secret will be read from stdin if missing.
+Account with the same host & identity in profile will be replaced.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if auths.DefaultID == "" && authAddVariables.key == "" {
+			return common.ErrAccount
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		authInfo := models.Authentication{
+			Identity: auths.DefaultID,
+			Password: auths.DefaultPass,
+			APIKey: models.APIKey{
+				Key:    authAddVariables.key,
+				Secret: authAddVariables.secret,
+			},
+		}
+
+		if authInfo.Key == "" && !authInfo.Password.IsSet() {
+			value, err := common.ReadPassword("Password: ")
+			if err != nil {
+				logger.Error(err.Error())
+				return
+			}
+
+			if err = authInfo.Password.Set(value); err != nil {
+				logger.Error("Shadow password failed.", zap.Error(err))
+				return
+			}
+		}
+
+		if authInfo.Key != "" && authInfo.Secret == "" {
+			value, err := common.ReadPassword("API secret: ")
+			if err != nil {
+				logger.Error(err.Error())
+				return
+			}
+
+			authInfo.Secret = value
+		}
+
+		host := common.GetBaseHost()
+
+		account, err := models.NewAccount(host, &authInfo)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		profile := auths.ProfileName()
+
+		if err = auths.AddAccount(profile, account); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		if err = auths.WriteConfig(); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		logger.Info("Account added.", zap.String("profile", profile),
+			zap.String("host", host), zap.String("id", account.ID()))
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authAddCmd)
+
+	authAddCmd.Flags().StringVar(
+		&authAddVariables.key, "key", "", "API key of account.")
+	authAddCmd.Flags().StringVar(
+		&authAddVariables.secret, "secret", "",
+		"API secret of account, read from stdin if not specified.")
+}
@@ -0,0 +1,56 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// authDefaultCmd represents the authDefault command
+var authDefaultCmd = &cobra.Command{
+	Use:   "default [profile]",
+	Short: "Show or set default profile.",
+	Long: `Show default profile, or set default profile which is used
+if --profile not specified.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println(auths.DefaultProfile())
+			return
+		}
+
+		if err := auths.SetDefaultProfile(args[0]); err != nil {
+			logger.Error(err.Error(), zap.String("profile", args[0]))
+			return
+		}
+
+		if err := auths.WriteConfig(); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		logger.Info("Default profile changed.", zap.String("profile", args[0]))
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authDefaultCmd)
+}
@@ -0,0 +1,53 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/spf13/cobra"
+)
+
+// authListCmd represents the authList command
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved profile accounts.",
+	Long:  `List accounts of all profiles saved in "auths.yaml", secrets are not shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		rows, err := auths.ProfileAccounts()
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		formatter := newFormatter()
+
+		for _, row := range rows {
+			if err := formatter.Format(row); err != nil {
+				logger.Warn(err.Error())
+			}
+		}
+
+		flushFormatter(formatter)
+
+		if len(rows) < 1 {
+			logger.Warn("No profile saved.")
+		}
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authListCmd)
+}
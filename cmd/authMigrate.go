@@ -0,0 +1,72 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+var authMigratePEM string
+
+// authMigrateCmd represents the authMigrate command
+var authMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Re-encrypt saved auth info with keystore.",
+	Long: `Re-encrypt passwords shadowed by legacy RSA key and plain api secrets
+in "auths.yaml" with keystore. Legacy key embedded in former versions is
+used by default, use --pem if passwords are shadowed by your own key.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		template, err := models.NewPasswordByPEM(authMigratePEM)
+		if err != nil {
+			logger.Error("Load legacy pem key failed.", zap.Error(err))
+			common.SetExitError(err)
+			return
+		}
+
+		count, err := auths.MigrateSavedAuths(template)
+		if err != nil {
+			logger.Error("Migrate auth info failed.", zap.Error(err))
+			return
+		}
+
+		if count < 1 {
+			logger.Info("Nothing to be migrated.")
+			return
+		}
+
+		if err = auths.WriteConfig(); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		logger.Info("Auth info migrated.", zap.Int("count", count))
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authMigrateCmd)
+
+	authMigrateCmd.Flags().StringVar(
+		&authMigratePEM, "pem", "",
+		"Legacy RSA private key file which passwords are shadowed with.")
+}
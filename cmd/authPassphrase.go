@@ -0,0 +1,67 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// authPassphraseCmd represents the authPassphrase command
+var authPassphraseCmd = &cobra.Command{
+	Use:   "passphrase",
+	Short: "Change keystore master passphrase.",
+	Long: `Change master passphrase which protects keystore,
+empty passphrase will remove the protection.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := models.DefaultKeystore()
+		if err != nil {
+			logger.Error("Open keystore failed.", zap.Error(err))
+			return
+		}
+
+		passphrase, err := common.ReadPassword("New passphrase: ")
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		if confirm, err := common.ReadPassword(
+			"Confirm passphrase: "); err != nil || confirm != passphrase {
+			logger.Error(common.ErrPassphraseConfirm.Error())
+			return
+		}
+
+		if err = store.SetPassphrase(passphrase); err != nil {
+			logger.Error("Save keystore failed.", zap.Error(err))
+			return
+		}
+
+		logger.Info("Keystore passphrase changed.",
+			zap.String("keystore", store.Path()),
+			zap.Bool("protected", passphrase != ""))
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authPassphraseCmd)
+}
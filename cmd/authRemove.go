@@ -0,0 +1,79 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var authRemoveProfile bool
+
+// authRemoveCmd represents the authRemove command
+var authRemoveCmd = &cobra.Command{
+	Use:   "remove [id...]",
+	Short: "Remove accounts from profile.",
+	Long: `Remove accounts in current host from profile specified by --profile
+or default, accounts are specified by identity or api key.
+Use --all to remove the whole profile with accounts in all hosts.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 && !authRemoveProfile {
+			return common.ErrArgs
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := auths.ProfileName()
+
+		if authRemoveProfile {
+			if err := auths.RemoveProfile(profile); err != nil {
+				logger.Error(err.Error(), zap.String("profile", profile))
+				return
+			}
+		} else {
+			count, err := auths.RemoveAccounts(
+				profile, common.GetBaseHost(), args)
+			if err != nil {
+				logger.Error(err.Error(), zap.String("profile", profile))
+				return
+			}
+
+			if count < 1 {
+				logger.Warn("No account removed.", zap.String("profile", profile))
+				return
+			}
+		}
+
+		if err := auths.WriteConfig(); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		logger.Info("Auth info removed.", zap.String("profile", profile))
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authRemoveCmd)
+
+	authRemoveCmd.Flags().BoolVar(
+		&authRemoveProfile, "all", false, "Remove the whole profile.")
+}
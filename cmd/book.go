@@ -0,0 +1,374 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/ngecli/shell"
+
+	"github.com/frozenpine/ngecli/ws"
+
+	"github.com/spf13/cobra"
+)
+
+type bookArgs struct {
+	depth    int
+	interval time.Duration
+	verify   time.Duration
+	mine     bool
+	once     bool
+	limit    int
+}
+
+var bookVariables bookArgs
+
+// bookMismatchLimit consecutive mismatched checks before book re-synced,
+// as snapshot may be taken before or after deltas in flight.
+const bookMismatchLimit = 2
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// myOrderLevels sum leaves qty of own active orders by side & price
+func myOrderLevels(client *ws.Client, symbol string) map[string]float64 {
+	levels := make(map[string]float64)
+
+	if client == nil {
+		return levels
+	}
+
+	for _, row := range client.Table(ws.TableOrder).Rows() {
+		if row["symbol"] != symbol {
+			continue
+		}
+
+		if status := row["ordStatus"]; status != "New" &&
+			status != "PartiallyFilled" {
+			continue
+		}
+
+		side, _ := row["side"].(string)
+		price, _ := row["price"].(float64)
+		leaves, _ := row["leavesQty"].(float64)
+
+		levels[side+":"+formatPrice(price)] += leaves
+	}
+
+	return levels
+}
+
+func renderBook(out io.Writer, book *models.OrderBook,
+	depth int, mine map[string]float64) {
+	bids, asks := book.Depth(depth)
+
+	summary := book.Symbol
+
+	if bid, ok := book.BestBid(); ok {
+		summary += fmt.Sprintf("  bid: %s x %s",
+			formatPrice(bid.Price), formatPrice(bid.Size))
+	}
+
+	if ask, ok := book.BestAsk(); ok {
+		summary += fmt.Sprintf("  ask: %s x %s",
+			formatPrice(ask.Price), formatPrice(ask.Size))
+	}
+
+	if len(bids) > 0 && len(asks) > 0 {
+		summary += "  spread: " + formatPrice(asks[0].Price-bids[0].Price)
+	}
+
+	fmt.Fprintf(out, "%s  levels: %d\n\n", summary, book.Len())
+
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(writer, "mine\tbid\tprice\task\tmine\t")
+
+	showMine := func(side models.OrderSide, price float64) string {
+		if qty := mine[string(side)+":"+formatPrice(price)]; qty > 0 {
+			return formatPrice(qty)
+		}
+
+		return ""
+	}
+
+	for idx := len(asks) - 1; idx >= 0; idx-- {
+		fmt.Fprintf(writer, "\t\t%s\t%s\t%s\t\n",
+			formatPrice(asks[idx].Price), formatPrice(asks[idx].Size),
+			showMine(models.Sell, asks[idx].Price))
+	}
+
+	for _, bid := range bids {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t\t\t\n",
+			showMine(models.Buy, bid.Price), formatPrice(bid.Size),
+			formatPrice(bid.Price))
+	}
+
+	writer.Flush()
+}
+
+func getBookSnapshot(symbol string) ([]ngerest.OrderBookL2, error) {
+	client, err := clientHub.GetClient(common.GetBaseHost())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, rsp, err := client.OrderBook.OrderBookGetL2(rootCtx, symbol, nil)
+
+	return snapshot, common.NewAPIError("", err, rsp)
+}
+
+// applyBookMessage apply websocket L2 message to book
+func applyBookMessage(book *models.OrderBook, msg *ws.Message) error {
+	levels := make([]*models.BookLevel, 0, len(msg.Rows))
+
+	for _, row := range msg.Rows {
+		level, err := models.NewBookLevel(row)
+		if err != nil {
+			return err
+		}
+
+		levels = append(levels, level)
+	}
+
+	switch msg.Action {
+	case ws.ActionPartial:
+		book.Partial(levels)
+	case ws.ActionInsert:
+		book.Insert(levels)
+	case ws.ActionUpdate:
+		return book.Update(levels)
+	case ws.ActionDelete:
+		return book.Delete(levels)
+	}
+
+	return nil
+}
+
+func resyncBook(client *ws.Client, topic string) {
+	logger.Warn("Re-sync order book.", zap.String("topic", topic))
+
+	if err := client.Unsubscribe(topic); err != nil {
+		logger.Warn(err.Error())
+	}
+
+	if err := client.Subscribe(topic); err != nil {
+		logger.Warn(err.Error())
+	}
+}
+
+func runBook(client *ws.Client, book *models.OrderBook, topic string) {
+	var (
+		rendered   int
+		mismatched int
+		dirty      = true
+		verify     <-chan time.Time
+	)
+
+	terminal := shell.IsTerminal(int(os.Stdout.Fd()))
+
+	render := func() {
+		if terminal {
+			fmt.Print("\x1b[H\x1b[2J")
+		} else if rendered > 0 {
+			fmt.Println()
+		}
+
+		renderBook(os.Stdout, book, bookVariables.depth,
+			myOrderLevels(client, book.Symbol))
+
+		rendered++
+		dirty = false
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(bookVariables.interval)
+	defer ticker.Stop()
+
+	if bookVariables.verify > 0 {
+		verifyTicker := time.NewTicker(bookVariables.verify)
+		defer verifyTicker.Stop()
+
+		verify = verifyTicker.C
+	}
+
+	for {
+		select {
+		case <-interrupt:
+			return
+		case <-rootCtx.Done():
+			return
+		case msg, ok := <-client.Messages():
+			if !ok {
+				return
+			}
+
+			if msg.Table == ws.TableOrder {
+				dirty = true
+				continue
+			}
+
+			if err := applyBookMessage(book, msg); err != nil {
+				logger.Warn(err.Error())
+				resyncBook(client, topic)
+
+				mismatched = 0
+			}
+
+			dirty = true
+		case <-verify:
+			snapshot, err := getBookSnapshot(book.Symbol)
+			if err != nil {
+				logger.Warn("Get order book snapshot failed.", zap.Error(err))
+				continue
+			}
+
+			if ok, _ := book.Verify(snapshot, bookVariables.depth); ok {
+				mismatched = 0
+				continue
+			}
+
+			mismatched++
+
+			logger.Warn(common.ErrBookInconsistent.Error(),
+				zap.Uint32("checksum", book.Checksum(bookVariables.depth)),
+				zap.Int("mismatched", mismatched))
+
+			if mismatched >= bookMismatchLimit {
+				resyncBook(client, topic)
+
+				mismatched = 0
+			}
+		case <-ticker.C:
+			if !dirty {
+				continue
+			}
+
+			render()
+
+			if bookVariables.limit > 0 && rendered >= bookVariables.limit {
+				return
+			}
+		}
+	}
+}
+
+// bookCmd represents the book command
+var bookCmd = &cobra.Command{
+	Use:   "book",
+	Short: "Show live order book ladder.",
+	Long: `Show live L2 order book ladder for symbol.
+
+Order book is initialized with REST snapshot and maintained by websocket
+orderBookL2 deltas. With --verify, it will be checked with fresh snapshot
+periodically and re-synced if inconsistent in consecutive checks.
+Use --mine to show resting quantity of your own orders in the ladder.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if bookVariables.depth < 1 || bookVariables.interval <= 0 ||
+			bookVariables.limit < 0 {
+			return common.ErrArgs
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		book := models.NewOrderBook(symbol)
+
+		snapshot, err := getBookSnapshot(symbol)
+		if err != nil {
+			common.PrintError("Get order book failed", err)
+			return
+		}
+
+		if err = book.Snapshot(snapshot); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		if bookVariables.once {
+			renderBook(os.Stdout, book, bookVariables.depth, nil)
+			return
+		}
+
+		topics := []string{ws.TableOrderBookL2 + ":" + symbol}
+		if bookVariables.mine {
+			topics = append(topics, ws.TableOrder+":"+symbol)
+		}
+
+		cfg, err := newWsConfig(topics)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		client := ws.NewClient(*cfg)
+		defer client.Close()
+
+		if err = client.Connect(); err != nil {
+			common.PrintError("Connect websocket failed", err)
+			return
+		}
+
+		if err = client.Subscribe(topics...); err != nil {
+			common.PrintError("Subscribe failed", err)
+			return
+		}
+
+		runBook(client, book, topics[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bookCmd)
+
+	bookCmd.Flags().IntVar(
+		&bookVariables.depth, "depth", models.DefaultBookDepth,
+		"Levels to be shown for each side.")
+	bookCmd.Flags().DurationVar(
+		&bookVariables.interval, "interval", 200*time.Millisecond,
+		"Minimum interval between ladder refresh.")
+	bookCmd.Flags().DurationVar(
+		&bookVariables.verify, "verify", 0,
+		"Interval to check book with fresh snapshot, 0 to disable.")
+	bookCmd.Flags().BoolVar(
+		&bookVariables.mine, "mine", false,
+		"Show resting quantity of own orders, api key required.")
+	bookCmd.Flags().BoolVar(
+		&bookVariables.once, "once", false,
+		"Print snapshot ladder and exit.")
+	bookCmd.Flags().IntVar(
+		&bookVariables.limit, "limit", 0,
+		"Exit after number of ladder refresh, 0 means unlimited.")
+}
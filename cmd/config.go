@@ -18,16 +18,58 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/models"
+
 	"github.com/frozenpine/viper"
 
 	"github.com/spf13/cobra"
 )
 
-// tradeCmd represents the trade command
+// loadConfigFile load config file in use for modification
+func loadConfigFile() *models.ConfigFile {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		path = cfgFile
+	}
+
+	cfg, err := models.LoadConfigFile(path)
+	if err != nil {
+		exitWithError(common.NewError(
+			common.KindValidation, "load config "+path, err))
+	}
+
+	return cfg
+}
+
+// saveConfigFile validate & save modified config file
+func saveConfigFile(cfg *models.ConfigFile) error {
+	if err := validateConfigFile(cfg); err != nil {
+		return err
+	}
+
+	return cfg.Save()
+}
+
+// configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
-	Short: "ngecli default config",
-	Long:  `show ngecli default configs & save to config.yaml.`,
+	Short: "ngecli config",
+	Long: `Show settings in use, or get, set, unset, edit & validate settings
+in config file.
+Settings checked by schema:
+	scheme, host, port, base-uri, ws-uri, symbol, output, tz, verbose,
+	connect-timeout, read-timeout, retry-backoff, max-retries,
+	ratelimit-reserve, default-env, log.level, log.format, log.color,
+	log.file, log.file-format, log.max-size, log.max-age, log.max-backups,
+	log.compress
+Levels of command loggers are saved as "log.levels.<command>", with sub
+commands joined by "/", such as "log.levels.order/new", sub commands
+inherit level of parent command.
+Named environments are saved as "envs.<name>.<key>", with keys:
+	scheme, host, port, base-uri, ws-uri, symbol, profile
+Environment is selected by --env, env ` + environmentEnv + ` or "default-env",
+its settings are used unless specified by flags.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		jsonBytes, _ := json.MarshalIndent(viper.AllSettings(), "", "  ")
 
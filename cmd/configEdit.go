@@ -0,0 +1,103 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const defaultEditor = "vi"
+
+// editConfigCopy edit copy of config file in editor, path of copy
+// will be returned, which should be removed after use.
+func editConfigCopy(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	// extension is kept, so config type can be detected
+	tmpFile, err := ioutil.TempFile(
+		filepath.Dir(path), "*"+filepath.Ext(path))
+	if err != nil {
+		return "", err
+	}
+	defer tmpFile.Close()
+
+	if _, err = tmpFile.Write(content); err != nil {
+		return tmpFile.Name(), err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	cmd := exec.Command(editor, tmpFile.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	return tmpFile.Name(), cmd.Run()
+}
+
+// configEditCmd represents the configEdit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit config file in editor.",
+	Long: `Edit config file in editor specified by env EDITOR, default is "vi".
+Config file is only replaced if edited settings are valid.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := loadConfigFile().Path()
+
+		tmpPath, err := editConfigCopy(path)
+		if tmpPath != "" {
+			defer os.Remove(tmpPath)
+		}
+		if err != nil {
+			exitWithError(err)
+		}
+
+		edited, err := models.LoadConfigFile(tmpPath)
+		if err != nil {
+			exitWithError(common.NewError(
+				common.KindValidation, "load edited config", err))
+		}
+
+		if err = validateConfigFile(edited); err != nil {
+			logger.Warn("Config file not changed.", zap.String("file", path))
+			common.SetExitError(err)
+			return
+		}
+
+		if err = os.Rename(tmpPath, path); err != nil {
+			exitWithError(err)
+		}
+
+		logger.Info("Config file saved.", zap.String("file", path))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configEditCmd)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/viper"
+	"github.com/spf13/cobra"
+)
+
+// configEnvCmd represents the configEnv command
+var configEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List named environments.",
+	Long: `List named environments in config file, each environment has
+its own host & auth profile, settings not set are inherited.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		envs, err := models.Environments(viper.GetViper())
+		if err != nil {
+			exitWithError(err)
+		}
+
+		formatter := newFormatter()
+
+		for _, env := range envs {
+			if err := formatter.Format(env); err != nil {
+				logger.Warn(err.Error())
+			}
+		}
+
+		flushFormatter(formatter)
+
+		if len(envs) < 1 {
+			logger.Warn("No environment saved.")
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configEnvCmd)
+}
@@ -0,0 +1,72 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/viper"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var configGetVariables struct {
+	file bool
+}
+
+// configGetCmd represents the configGet command
+var configGetCmd = &cobra.Command{
+	Use:   "get key",
+	Short: "Get setting value.",
+	Long: `Get setting value in use, which is merged from flags, env,
+config file & defaults, or value saved in config file if --file specified.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			value interface{}
+			exist bool
+		)
+
+		if configGetVariables.file {
+			value, exist = loadConfigFile().Get(args[0])
+		} else {
+			value, exist = viper.Get(args[0]), viper.IsSet(args[0])
+		}
+
+		if !exist {
+			logger.Warn("Setting not found.", zap.String("key", args[0]))
+			return
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			jsonBytes, _ := json.MarshalIndent(value, "", "  ")
+			fmt.Println(string(jsonBytes))
+		default:
+			fmt.Println(value)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+
+	configGetCmd.Flags().BoolVar(
+		&configGetVariables.file, "file", false,
+		"Get value saved in config file.")
+}
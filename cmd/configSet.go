@@ -0,0 +1,50 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// configSetCmd represents the configSet command
+var configSetCmd = &cobra.Command{
+	Use:   "set key value",
+	Short: "Set setting value in config file.",
+	Long: `Set setting value in config file, value is validated by schema.
+Environment settings are set by key "envs.<name>.<key>", environment
+is created if not exist.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigFile()
+
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			exitWithError(err)
+		}
+
+		if err := saveConfigFile(cfg); err != nil {
+			exitWithError(err)
+		}
+
+		logger.Info("Setting saved.", zap.String("key", args[0]),
+			zap.String("value", args[1]), zap.String("file", cfg.Path()))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+}
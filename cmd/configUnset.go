@@ -0,0 +1,60 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// configUnsetCmd represents the configUnset command
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset key...",
+	Short: "Remove settings from config file.",
+	Long: `Remove settings from config file, so defaults are used.
+Whole environment can be removed by key "envs.<name>".`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigFile()
+
+		var removed int
+
+		for _, key := range args {
+			if !cfg.Unset(key) {
+				logger.Warn("Setting not found.", zap.String("key", key))
+				continue
+			}
+
+			removed++
+		}
+
+		if removed < 1 {
+			return
+		}
+
+		if err := saveConfigFile(cfg); err != nil {
+			exitWithError(err)
+		}
+
+		logger.Info("Settings removed.", zap.Int("count", removed),
+			zap.String("file", cfg.Path()))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configUnsetCmd)
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/frozenpine/ngecli/logger"
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/viper"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// configUseCmd represents the configUse command
+var configUseCmd = &cobra.Command{
+	Use:   "use [env]",
+	Short: "Show or switch default environment.",
+	Long: `Show default environment, or switch default environment which is
+used if neither --env nor env ` + environmentEnv + ` specified.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println(viper.GetString(models.DefaultEnvKey))
+			return
+		}
+
+		if _, err := models.GetEnvironment(viper.GetViper(), args[0]); err != nil {
+			exitWithError(err)
+		}
+
+		cfg := loadConfigFile()
+
+		if err := cfg.Set(models.DefaultEnvKey, args[0]); err != nil {
+			exitWithError(err)
+		}
+
+		if err := saveConfigFile(cfg); err != nil {
+			exitWithError(err)
+		}
+
+		logger.Info("Default environment changed.", zap.String("env", args[0]))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configUseCmd)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// validateConfigFile validate settings in config file,
+// all invalid settings are logged & the first error is returned.
+func validateConfigFile(cfg *models.ConfigFile) error {
+	errs := models.ValidateConfig(cfg.Settings())
+
+	for _, err := range errs {
+		logger.Error(err.Error(), zap.String("file", cfg.Path()))
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// configValidateCmd represents the configValidate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate settings in config file.",
+	Long: `Validate all settings & environments in config file by schema,
+exit with validation code if any setting is invalid.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigFile()
+
+		if err := validateConfigFile(cfg); err != nil {
+			common.SetExitError(err)
+			return
+		}
+
+		logger.Info("Config file is valid.", zap.String("file", cfg.Path()))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
@@ -0,0 +1,261 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+type executionArgs struct {
+	queryArgs
+
+	reconcile bool
+	tolerance float64
+}
+
+// executionQuery query a page of executions with options
+type executionQuery func(
+	client *ngerest.APIClient, authCtx context.Context,
+	options *ngerest.ExecutionGetOpts) ([]ngerest.Execution, error)
+
+func getExecutionOpts(symbol string, args *executionArgs) *ngerest.ExecutionGetOpts {
+	options := ngerest.ExecutionGetOpts{}
+
+	if symbol != "" {
+		options.Symbol = optional.NewString(symbol)
+	}
+
+	if args.filter != "" {
+		options.Filter = optional.NewString(args.filter)
+	}
+
+	if args.columns != "" {
+		options.Columns = optional.NewString(args.columns)
+	}
+
+	if args.reverse {
+		options.Reverse = optional.NewBool(args.reverse)
+	}
+
+	if args.start.IsSet() {
+		options.StartTime = optional.NewTime(args.start.GetTime())
+	}
+
+	if args.end.IsSet() {
+		options.EndTime = optional.NewTime(args.end.GetTime())
+	}
+
+	if args.count > 0 {
+		options.Count = optional.NewFloat32(float32(args.count))
+	}
+
+	return &options
+}
+
+// cacheOrders fetch orders not in order cache by OrderID
+func cacheOrders(
+	client *ngerest.APIClient, authCtx context.Context, orderIDs []string) error {
+	var missing []string
+
+	for _, orderID := range orderIDs {
+		if _, exist := orderCache.GetOrder(orderID); !exist {
+			missing = append(missing, orderID)
+		}
+	}
+
+	for start := 0; start < len(missing); start += models.DefaultPageSize {
+		end := start + models.DefaultPageSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		filter, _ := json.Marshal(map[string][]string{
+			"orderID": missing[start:end]})
+
+		orders, rsp, err := client.Order.OrderGetOrders(
+			authCtx, &ngerest.OrderGetOrdersOpts{
+				Filter: optional.NewString(string(filter)),
+				Count:  optional.NewFloat32(float32(end - start)),
+			})
+		if err != nil {
+			return common.NewAPIError("", err, rsp)
+		}
+
+		for idx := range orders {
+			ord, err := models.ConvertOrder(&orders[idx])
+			if err != nil {
+				return err
+			}
+
+			orderCache.CacheOrder(ord)
+		}
+	}
+
+	return nil
+}
+
+func reconcileExecutions(client *ngerest.APIClient, authCtx context.Context,
+	execs []*models.Execution, tolerance float64) {
+	var orderIDs []string
+
+	seen := make(map[string]bool)
+
+	for _, exec := range execs {
+		if exec.IsFill() && !seen[exec.OrderID] {
+			seen[exec.OrderID] = true
+			orderIDs = append(orderIDs, exec.OrderID)
+		}
+	}
+
+	if err := cacheOrders(client, authCtx, orderIDs); err != nil {
+		common.PrintError("Get orders for reconciliation failed", err)
+		return
+	}
+
+	var mismatched int
+
+	formatter := newFormatter()
+
+	for _, r := range orderCache.Reconcile(execs, tolerance) {
+		if r.Status != models.ReconcileOK {
+			mismatched++
+
+			logger.Warn("Order miss-matched with fills.",
+				zap.String("orderID", r.OrderID), zap.String("status", r.Status),
+				zap.Float64("cumQty", r.CumQty), zap.Float64("fillQty", r.FillQty),
+				zap.Float64("avgPx", r.AvgPx), zap.Float64("fillAvgPx", r.FillAvgPx))
+		}
+
+		if err := formatter.Format(r); err != nil {
+			logger.Warn(err.Error())
+		}
+	}
+
+	flushFormatter(formatter)
+
+	logger.Info("Reconciliation finished.", zap.Int("mismatched", mismatched))
+}
+
+func runExecutionQuery(args *executionArgs, query executionQuery) {
+	checkLoginInfo()
+
+	client, err := clientHub.GetClient(common.GetBaseHost())
+	if err != nil {
+		logger.Warn(err.Error())
+		return
+	}
+
+	authCtx, err := auths.NextAuth(nil)
+	if err != nil {
+		common.PrintError("Load auth failed", err)
+		return
+	}
+
+	pager := args.newPager(func(start, count int) ([]interface{}, error) {
+		options := getExecutionOpts(symbol, args)
+		options.Start = optional.NewFloat32(float32(start))
+		options.Count = optional.NewFloat32(float32(count))
+
+		execs, err := query(client, authCtx, options)
+
+		rows := make([]interface{}, len(execs))
+		for idx := range execs {
+			rows[idx] = &execs[idx]
+		}
+
+		return rows, err
+	}, func(row interface{}) string {
+		return row.(*ngerest.Execution).ExecID
+	})
+
+	var (
+		execs     []*models.Execution
+		formatter models.Formatter
+	)
+
+	if !args.reconcile {
+		formatter = newFormatter()
+	}
+
+	total, err := pager.Fetch(func(row interface{}) {
+		exec, err := models.ConvertExecution(row.(*ngerest.Execution))
+		if err != nil {
+			logger.Warn(err.Error())
+			return
+		}
+
+		if args.reconcile {
+			execs = append(execs, exec)
+		} else if err := formatter.Format(exec); err != nil {
+			logger.Warn(err.Error())
+		}
+	})
+
+	if formatter != nil {
+		flushFormatter(formatter)
+	}
+
+	if err != nil {
+		common.PrintError("Get execution failed", err)
+		return
+	} else if total < 1 {
+		logger.Warn("No executions found.")
+		return
+	}
+
+	if args.reconcile {
+		reconcileExecutions(client, authCtx, execs, args.tolerance)
+	}
+}
+
+func bindExecutionFlags(cmd *cobra.Command, args *executionArgs) {
+	bindQueryFlags(cmd, &args.queryArgs)
+
+	cmd.Flags().BoolVar(
+		&args.reconcile, "reconcile", false,
+		"Reconcile fills with orders' cumQty & avgPx instead of output executions.")
+	cmd.Flags().Float64Var(
+		&args.tolerance, "tolerance", models.DefaultPxTolerance,
+		"Relative tolerance for avgPx comparison in reconciliation.")
+}
+
+// executionCmd represents the execution command
+var executionCmd = &cobra.Command{
+	Use:   "execution",
+	Short: "execution functions",
+	Long:  `All functions for Execution table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("execution called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(executionCmd)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var executionGetVariables executionArgs
+
+// executionGetCmd represents the executionGet command
+var executionGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get user's raw executions.",
+	Long: `Get all raw executions of user, including new, cancel & trade.
+Use --reconcile to check orders' cumQty & avgPx with their fills.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runExecutionQuery(&executionGetVariables, func(
+			client *ngerest.APIClient, authCtx context.Context,
+			options *ngerest.ExecutionGetOpts) ([]ngerest.Execution, error) {
+			execs, rsp, err := client.Execution.ExecutionGet(authCtx, options)
+
+			return execs, common.NewAPIError("", err, rsp)
+		})
+	},
+}
+
+func init() {
+	executionCmd.AddCommand(executionGetCmd)
+
+	bindExecutionFlags(executionGetCmd, &executionGetVariables)
+}
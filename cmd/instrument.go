@@ -0,0 +1,170 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	instrumentLock       sync.Mutex
+	instrumentRegistries = make(map[string]*models.InstrumentRegistry)
+	instrumentWarned     bool
+)
+
+// instrumentKey unique key of instrument row in history
+func instrumentKey(row interface{}) string {
+	ins := row.(*ngerest.Instrument)
+
+	return ins.Symbol + "@" + ins.Timestamp.String()
+}
+
+// loadInstruments load all instruments of host page by page, including
+// closed & settled contracts, so their state can be checked.
+func loadInstruments(host string) ([]ngerest.Instrument, error) {
+	client, err := clientHub.GetClient(host)
+	if err != nil {
+		return nil, err
+	}
+
+	var instruments []ngerest.Instrument
+
+	pager := models.Pager{
+		Query: func(start, count int) ([]interface{}, error) {
+			results, rsp, err := client.Instrument.InstrumentGet(
+				rootCtx, &ngerest.InstrumentGetOpts{
+					Start: optional.NewFloat32(float32(start)),
+					Count: optional.NewFloat32(float32(count)),
+				})
+
+			rows := make([]interface{}, len(results))
+			for idx := range results {
+				rows[idx] = &results[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		},
+		Key: instrumentKey,
+	}
+
+	_, err = pager.Fetch(func(row interface{}) {
+		instruments = append(instruments, *row.(*ngerest.Instrument))
+	})
+
+	return instruments, err
+}
+
+// instrumentRegistry get instrument registry of current host,
+// all instruments are loaded & cached by registry.
+func instrumentRegistry() *models.InstrumentRegistry {
+	host := common.GetBaseHost()
+
+	instrumentLock.Lock()
+	defer instrumentLock.Unlock()
+
+	if reg, exist := instrumentRegistries[host]; exist {
+		return reg
+	}
+
+	reg := models.NewInstrumentRegistry(
+		func() ([]ngerest.Instrument, error) {
+			return loadInstruments(host)
+		}, models.DefaultInstrumentTTL)
+
+	instrumentRegistries[host] = reg
+
+	return reg
+}
+
+// lookupInstrument get instrument of symbol for order validation,
+// nil will be returned without error if instruments can not be loaded,
+// so orders are only checked by engine.
+func lookupInstrument(symbol string) (*models.Instrument, error) {
+	ins, err := instrumentRegistry().Get(symbol)
+	if err == nil || common.KindOf(err) == common.KindValidation {
+		return ins, err
+	}
+
+	instrumentLock.Lock()
+	defer instrumentLock.Unlock()
+
+	if !instrumentWarned {
+		instrumentWarned = true
+
+		logger.Warn("Load instruments failed, instrument checks skipped.",
+			zap.Error(err))
+	}
+
+	return nil, nil
+}
+
+// checkOrderInstrument check order by instrument of order's symbol
+func checkOrderInstrument(ord *models.Order) error {
+	ins, err := lookupInstrument(ord.Symbol)
+	if err != nil || ins == nil {
+		return err
+	}
+
+	return ins.CheckOrder(ord)
+}
+
+// printInstruments print instruments with output formatter
+func printInstruments(instruments ...ngerest.Instrument) {
+	formatter := newFormatter()
+
+	for idx := range instruments {
+		converted, err := models.ConvertInstrument(&instruments[idx])
+		if err != nil {
+			logger.Warn(err.Error())
+			continue
+		}
+
+		if err := formatter.Format(converted); err != nil {
+			logger.Warn(err.Error())
+		}
+	}
+
+	flushFormatter(formatter)
+}
+
+// instrumentCmd represents the instrument command
+var instrumentCmd = &cobra.Command{
+	Use:   "instrument",
+	Short: "instrument functions",
+	Long: `All functions for Instrument table.
+Instruments are also cached to check tick size, lot size,
+max price, max order quantity & state of orders before sent.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("instrument called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(instrumentCmd)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+type instrumentActiveArgs struct {
+	indices bool
+}
+
+var instrumentActiveVariables instrumentActiveArgs
+
+// instrumentActiveCmd represents the instrumentActive command
+var instrumentActiveCmd = &cobra.Command{
+	Use:   "active",
+	Short: "Get active instruments.",
+	Long: `Get all active instruments, which are open for trading,
+indices are included if --indices specified.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		query := client.Instrument.InstrumentGetActive
+		if instrumentActiveVariables.indices {
+			query = client.Instrument.InstrumentGetActiveAndIndices
+		}
+
+		instruments, rsp, err := query(rootCtx)
+		if err != nil {
+			common.PrintError("Get active instrument failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		if len(instruments) < 1 {
+			logger.Warn("No active instruments found.")
+			return
+		}
+
+		printInstruments(instruments...)
+	},
+}
+
+func init() {
+	instrumentCmd.AddCommand(instrumentActiveCmd)
+
+	instrumentActiveCmd.Flags().BoolVar(
+		&instrumentActiveVariables.indices, "indices", false,
+		"Include indices in active instruments.")
+}
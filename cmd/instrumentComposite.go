@@ -0,0 +1,120 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+type instrumentCompositeArgs struct {
+	queryArgs
+
+	index string
+}
+
+var instrumentCompositeVariables instrumentCompositeArgs
+
+func getCompositeIndexOpts(
+	args *instrumentCompositeArgs) *ngerest.InstrumentGetCompositeIndexOpts {
+	insOpts := getInstrumentOpts(args.index, &args.queryArgs)
+
+	options := ngerest.InstrumentGetCompositeIndexOpts{
+		Symbol:    insOpts.Symbol,
+		Filter:    insOpts.Filter,
+		Columns:   insOpts.Columns,
+		Count:     insOpts.Count,
+		Reverse:   insOpts.Reverse,
+		StartTime: insOpts.StartTime,
+		EndTime:   insOpts.EndTime,
+	}
+
+	return &options
+}
+
+// instrumentCompositeCmd represents the instrumentComposite command
+var instrumentCompositeCmd = &cobra.Command{
+	Use:   "composite",
+	Short: "Get composite index.",
+	Long: `Get composite index history with reference prices & weights,
+index symbol is specified by --index, server's default index is used
+if not specified.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		formatter := newFormatter()
+
+		query := func(start, count int) ([]interface{}, error) {
+			options := getCompositeIndexOpts(&instrumentCompositeVariables)
+			options.Start = optional.NewFloat32(float32(start))
+			options.Count = optional.NewFloat32(float32(count))
+
+			results, rsp, err := client.Instrument.InstrumentGetCompositeIndex(
+				rootCtx, options)
+
+			rows := make([]interface{}, len(results))
+			for idx := range results {
+				rows[idx] = &results[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		}
+
+		pager := instrumentCompositeVariables.newPager(query, nil)
+
+		total, err := pager.Fetch(func(row interface{}) {
+			converted, err := models.ConvertIndexComposite(
+				row.(*ngerest.IndexComposite))
+			if err != nil {
+				logger.Warn(err.Error())
+				return
+			}
+
+			if err := formatter.Format(converted); err != nil {
+				logger.Warn(err.Error())
+			}
+		})
+
+		flushFormatter(formatter)
+
+		if err != nil {
+			common.PrintError("Get composite index failed", err)
+		} else if total < 1 {
+			logger.Warn("No composite index found.")
+		}
+	},
+}
+
+func init() {
+	instrumentCmd.AddCommand(instrumentCompositeCmd)
+
+	bindQueryFlags(instrumentCompositeCmd, &instrumentCompositeVariables.queryArgs)
+
+	instrumentCompositeCmd.Flags().StringVar(
+		&instrumentCompositeVariables.index, "index", "",
+		"Index symbol, such as \".XBT\".")
+}
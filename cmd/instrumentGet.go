@@ -0,0 +1,129 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+var instrumentGetVariables queryArgs
+
+func getInstrumentOpts(symbol string, args *queryArgs) *ngerest.InstrumentGetOpts {
+	options := ngerest.InstrumentGetOpts{}
+
+	if symbol != "" {
+		options.Symbol = optional.NewString(symbol)
+	}
+
+	if args.filter != "" {
+		options.Filter = optional.NewString(args.filter)
+	}
+
+	if args.columns != "" {
+		options.Columns = optional.NewString(args.columns)
+	}
+
+	if args.reverse {
+		options.Reverse = optional.NewBool(args.reverse)
+	}
+
+	if args.start.IsSet() {
+		options.StartTime = optional.NewTime(args.start.GetTime())
+	}
+
+	if args.end.IsSet() {
+		options.EndTime = optional.NewTime(args.end.GetTime())
+	}
+
+	if args.count > 0 {
+		options.Count = optional.NewFloat32(float32(args.count))
+	}
+
+	return &options
+}
+
+// instrumentGetCmd represents the instrumentGet command
+var instrumentGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get instruments.",
+	Long: `Get instruments including history & expired contracts,
+all instruments will be returned unless --symbol specified.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		var filterSymbol string
+		if cmd.Flags().Changed("symbol") {
+			filterSymbol = symbol
+		}
+
+		formatter := newFormatter()
+
+		query := func(start, count int) ([]interface{}, error) {
+			options := getInstrumentOpts(filterSymbol, &instrumentGetVariables)
+			options.Start = optional.NewFloat32(float32(start))
+			options.Count = optional.NewFloat32(float32(count))
+
+			results, rsp, err := client.Instrument.InstrumentGet(rootCtx, options)
+
+			rows := make([]interface{}, len(results))
+			for idx := range results {
+				rows[idx] = &results[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		}
+
+		pager := instrumentGetVariables.newPager(query, instrumentKey)
+
+		total, err := pager.Fetch(func(row interface{}) {
+			converted, err := models.ConvertInstrument(row.(*ngerest.Instrument))
+			if err != nil {
+				logger.Warn(err.Error())
+				return
+			}
+
+			if err := formatter.Format(converted); err != nil {
+				logger.Warn(err.Error())
+			}
+		})
+
+		flushFormatter(formatter)
+
+		if err != nil {
+			common.PrintError("Get instrument failed", err)
+		} else if total < 1 {
+			logger.Warn("No instruments found.")
+		}
+	},
+}
+
+func init() {
+	instrumentCmd.AddCommand(instrumentGetCmd)
+
+	bindQueryFlags(instrumentGetCmd, &instrumentGetVariables)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+// instrumentIndicesCmd represents the instrumentIndices command
+var instrumentIndicesCmd = &cobra.Command{
+	Use:   "indices",
+	Short: "Get indices.",
+	Long:  `Get all price indices.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		indices, rsp, err := client.Instrument.InstrumentGetIndices(rootCtx)
+		if err != nil {
+			common.PrintError("Get indices failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		if len(indices) < 1 {
+			logger.Warn("No indices found.")
+			return
+		}
+
+		printInstruments(indices...)
+	},
+}
+
+func init() {
+	instrumentCmd.AddCommand(instrumentIndicesCmd)
+}
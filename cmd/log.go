@@ -0,0 +1,135 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/viper"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultLogFile log file in config dir
+func defaultLogFile() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".ngecli", "logs", "ngecli.log")
+}
+
+func bindLogFlags(cmd *cobra.Command) {
+	defaults := logger.DefaultConfig()
+
+	viper.SetDefault("log.level", defaults.Level.String())
+	viper.SetDefault("log.format", defaults.Format)
+	viper.SetDefault("log.color", defaults.Color)
+	viper.SetDefault("log.file-format", defaults.FileFormat)
+	viper.SetDefault("log.max-size", defaults.MaxSize)
+	viper.SetDefault("log.max-age", defaults.MaxAge)
+	viper.SetDefault("log.max-backups", defaults.MaxBackups)
+	viper.SetDefault("log.compress", defaults.Compress)
+
+	viper.SetDefault("log.file", defaultLogFile())
+	cmd.PersistentFlags().String(
+		"log-file", defaultLogFile(), "Log file path, empty to disable.")
+	viper.BindPFlag("log.file", cmd.PersistentFlags().Lookup("log-file"))
+}
+
+// loadLogConfig load logging settings from viper, level is lowered
+// by each --verbose flag.
+func loadLogConfig() (logger.Config, error) {
+	cfg := logger.Config{
+		Format:     viper.GetString("log.format"),
+		Color:      viper.GetBool("log.color"),
+		File:       viper.GetString("log.file"),
+		FileFormat: viper.GetString("log.file-format"),
+		MaxSize:    viper.GetInt("log.max-size"),
+		MaxAge:     viper.GetInt("log.max-age"),
+		MaxBackups: viper.GetInt("log.max-backups"),
+		Compress:   viper.GetBool("log.compress"),
+		Levels:     make(map[string]zapcore.Level),
+	}
+
+	level, err := logger.ParseLevel(viper.GetString("log.level"))
+	if err != nil {
+		return cfg, common.NewError(common.KindValidation, "log.level", err)
+	}
+
+	if level -= zapcore.Level(viper.GetInt("verbose")); level < zapcore.DebugLevel {
+		level = zapcore.DebugLevel
+	}
+
+	cfg.Level = level
+
+	if err = parseLogLevels(
+		cfg.Levels, viper.GetStringMap(models.LogLevelsKey)); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// parseLogLevels parse levels of command loggers, names of sub commands
+// are joined by models.LogLevelSep in config file, such as "order/new".
+func parseLogLevels(levels map[string]zapcore.Level,
+	settings map[string]interface{}) error {
+	for name, value := range settings {
+		key := models.LogLevelsKey + "." + name
+
+		if _, ok := value.(map[string]interface{}); ok {
+			return common.NewError(
+				common.KindValidation, key, common.ErrConfigKey)
+		}
+
+		level, err := logger.ParseLevel(fmt.Sprint(value))
+		if err != nil {
+			return common.NewError(common.KindValidation, key, err)
+		}
+
+		levels[strings.Replace(name, models.LogLevelSep, ".", -1)] = level
+	}
+
+	return nil
+}
+
+// initLogger rebuild logger by settings loaded from config
+func initLogger() {
+	cfg, err := loadLogConfig()
+	if err == nil {
+		err = logger.Init(cfg)
+	}
+
+	if err != nil {
+		exitWithError(err)
+	}
+}
+
+// commandLoggerName logger name of command, which is command path
+// without root joined by periods, such as "order.new"
+func commandLoggerName(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+
+	return strings.Join(path[1:], ".")
+}
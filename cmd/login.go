@@ -35,6 +35,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const keystorePassphraseEnv = "NGECLI_PASSPHRASE"
+
 var (
 	auths *models.AuthCache
 )
@@ -65,48 +67,115 @@ func parseArgHost(host string) bool {
 	return true
 }
 
+// setHost set scheme & host from string: [scheme://]host[:port],
+// host string w/o scheme will be returned.
+func setHost(host string) (string, bool) {
+	parts := strings.Split(host, "://")
+
+	if strings.Contains(parts[0], "http") {
+		viper.Set("scheme", parts[0])
+	}
+
+	hostString := parts[len(parts)-1]
+
+	return hostString, parseArgHost(hostString)
+}
+
 // CollectLoginInfo from stdin
 func CollectLoginInfo() (identity string, password *models.Password) {
 	password = models.NewPassword()
 
 	identity = common.ReadLine("Identity: ", nil)
-	password.Set(common.ReadLine("Password: ", nil))
+
+	value, err := common.ReadPassword("Password: ")
+	if err != nil {
+		exitWithError(common.NewError(
+			common.KindUnknown, "read password", err))
+	}
+
+	if err = password.Set(value); err != nil {
+		exitWithError(common.NewError(
+			common.KindUnknown, "shadow password", err))
+	}
 
 	return
 }
 
+// checkLoginInfo collect login info from stdin if auth info is not loaded
+// and can not be found in cli args, auth file or "auths.yaml"
+func checkLoginInfo() {
+	if auths.IsLoaded() ||
+		auths.CmdAuthFile != "" ||
+		auths.Profile != "" ||
+		auths.HasSavedAuth(common.GetBaseHost()) ||
+		auths.HasDefaultAuth() {
+		return
+	}
+
+	identity, password := CollectLoginInfo()
+
+	auths.DefaultID, auths.DefaultPass = identity, *password
+}
+
 // host string: host:port w/o scheme(http | https)
 func loginAndSave(host string) {
+	if host == "" {
+		host = common.GetBaseHost()
+	}
+
 	identity, password := CollectLoginInfo()
 
 	logger.Info("Try to login into:", zap.String("url", common.GetBaseURL()))
 
-	if auth := auths.Login(identity, password); auth == nil {
-		logger.Fatal("Login failed.", zap.String("id", identity))
+	auth, err := auths.Login(identity, password)
+	if err != nil {
+		common.PrintError("Login failed", err)
+		return
+	}
+
+	logger.Info("Login succeed.", zap.String("id", identity))
+
+	authInfo := models.Authentication{
+		Identity: identity,
+		Password: *password,
+	}
+
+	if key, err := auths.GetUserDefaultKey(auth); err != nil {
+		logger.Warn("Get default api key failed.", zap.Error(err))
 	} else {
-		logger.Info("Login succeed.", zap.String("id", identity))
+		authInfo.APIKey = *key
+
+		if err = auths.CacheSession(host, identity, auth, key); err != nil {
+			logger.Warn("Cache login session failed.", zap.Error(err))
+		}
+	}
+
+	account, err := models.NewAccount(host, &authInfo)
+	if err != nil {
+		exitWithError(common.NewError(
+			common.KindUnknown, "seal account", err))
 	}
 
-	auths.SetLoginInfo(host, identity, password)
+	if err = auths.AddAccount(auths.ProfileName(), account); err != nil {
+		exitWithError(common.NewError(
+			common.KindUnknown, "save account", err))
+	}
+
+	logger.Info("Account saved.", zap.String("profile", auths.ProfileName()),
+		zap.String("host", host), zap.String("id", identity))
 }
 
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login NGE trade engine with user identity.",
-	Long:  `Login NGE trade engine and save identity info to auths.yaml`,
+	Long: `Login NGE trade engine and save identity info with default api key
+to profile in auths.yaml, profile is specified by --profile or default.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			for _, host := range args {
-				parts := strings.Split(host, "://")
-
-				if strings.Contains(parts[0], "http") {
-					viper.Set("scheme", parts[0])
-				}
-
-				hostString := parts[len(parts)-1]
-
-				if !parseArgHost(hostString) {
+				hostString, ok := setHost(host)
+				if !ok {
 					continue
 				}
 
@@ -118,7 +187,8 @@ var loginCmd = &cobra.Command{
 
 		err := auths.WriteConfig()
 		if err != nil {
-			logger.Fatal(err.Error())
+			exitWithError(common.NewError(
+				common.KindUnknown, "write auths", err))
 		}
 	},
 }
@@ -130,20 +200,42 @@ func init() {
 }
 
 func initAuthConfig() {
+	if auths == nil {
+		auths = newAuthCache()
+	}
+}
+
+// keystorePassphrase get keystore passphrase from env or stdin
+func keystorePassphrase() (string, error) {
+	if passphrase := os.Getenv(keystorePassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	return common.ReadPassword("Keystore passphrase: ")
+}
+
+// newAuthCache create auth cache with "auths.yaml" in config dir,
+// secrets in "auths.yaml" are sealed by "keystore" in config dir,
+// login sessions are cached in "sessions" in config dir.
+func newAuthCache() *models.AuthCache {
 	// Find home directory.
 	home, err := homedir.Dir()
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
 
-	if auths == nil {
-		auths = models.NewAuthCache(rootCtx, clientHub)
-	}
+	cache := models.NewAuthCache(rootCtx, clientHub)
 
 	confDIR := filepath.Join(home, ".ngecli")
 	if _, err := os.Stat(confDIR); os.IsNotExist(err) {
 		os.Mkdir(confDIR, os.ModePerm)
 	}
 
-	auths.SetConfigFile(filepath.Join(confDIR, "auths.yaml"))
+	models.SetDefaultKeystore(
+		filepath.Join(confDIR, "keystore"), keystorePassphrase)
+
+	cache.SetConfigFile(filepath.Join(confDIR, "auths.yaml"))
+	cache.SetSessionFile(filepath.Join(confDIR, "sessions"))
+
+	return cache
 }
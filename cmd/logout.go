@@ -0,0 +1,56 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/common"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/spf13/cobra"
+)
+
+var logoutAll bool
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Logout cached login sessions.",
+	Long: `Logout login sessions cached in host and clear them from cache,
+only session of identity specified by --id will be logged out if specified.
+All sessions of user including those not cached will be logged out with --all.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		host := common.GetBaseHost()
+
+		ids, err := auths.Logout(host, auths.DefaultID, logoutAll)
+		if err != nil {
+			logger.Error(err.Error(), zap.String("host", host))
+			return
+		}
+
+		for _, id := range ids {
+			logger.Info("Logout succeed.", zap.String("id", id))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+
+	logoutCmd.Flags().BoolVar(
+		&logoutAll, "all", false, "Logout all sessions of user.")
+}
@@ -15,8 +15,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
 	"github.com/frozenpine/ngecli/models"
 
 	"github.com/spf13/cobra"
@@ -24,6 +30,138 @@ import (
 
 var orderCache *models.OrderCache
 
+// orderOwner account owning order, ord is nil if order is not cached
+type orderOwner struct {
+	id  string
+	ord *models.Order
+}
+
+// orderOwners owners of orders found by OrderID & ClOrdID
+type orderOwners struct {
+	byOrderID map[string]*orderOwner
+	byClOrdID map[string]*orderOwner
+}
+
+// Find find owner by OrderID first, then by ClOrdID
+func (o *orderOwners) Find(orderID, clOrdID string) (*orderOwner, bool) {
+	if owner, exist := o.byOrderID[orderID]; exist && orderID != "" {
+		return owner, true
+	}
+
+	owner, exist := o.byClOrdID[clOrdID]
+
+	return owner, exist && clOrdID != ""
+}
+
+// missingKeys get keys whose owner not found
+func missingKeys(keys []string, owners map[string]*orderOwner) []string {
+	var missing []string
+
+	for _, key := range keys {
+		if _, exist := owners[key]; !exist && key != "" {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// queryOrders query account's orders by OrderIDs or ClOrdIDs in field
+func queryOrders(client *ngerest.APIClient, id, field string,
+	keys []string) ([]ngerest.Order, error) {
+	var orders []ngerest.Order
+
+	for start := 0; start < len(keys); start += models.DefaultPageSize {
+		end := start + models.DefaultPageSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		filter, _ := json.Marshal(map[string][]string{
+			field: keys[start:end]})
+
+		results, rsp, err := client.Order.OrderGetOrders(
+			auths.GetAuthContext(nil, id), &ngerest.OrderGetOrdersOpts{
+				Filter: optional.NewString(string(filter)),
+				Count:  optional.NewFloat32(float32(end - start)),
+			})
+		if err != nil {
+			return nil, common.NewAPIError("", err, rsp)
+		}
+
+		orders = append(orders, results...)
+	}
+
+	return orders, nil
+}
+
+// findOrderOwners find accounts owning orders by OrderIDs & ClOrdIDs,
+// owners bound in order cache are used first, the others are found by
+// querying orders in each account, keys not found are left out.
+func findOrderOwners(client *ngerest.APIClient,
+	orderIDs, clOrdIDs []string) (*orderOwners, error) {
+	owners := orderOwners{
+		byOrderID: make(map[string]*orderOwner),
+		byClOrdID: make(map[string]*orderOwner),
+	}
+
+	for _, key := range orderIDs {
+		if id, ord, exist := orderCache.GetOwner(key); exist {
+			owners.byOrderID[key] = &orderOwner{id: id, ord: ord}
+		}
+	}
+
+	for _, key := range clOrdIDs {
+		if id, ord, exist := orderCache.GetOwner(key); exist {
+			owners.byClOrdID[key] = &orderOwner{id: id, ord: ord}
+		}
+	}
+
+	ids, err := auths.AuthIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	lookups := []struct {
+		field string
+		keys  []string
+		found map[string]*orderOwner
+	}{
+		{"orderID", orderIDs, owners.byOrderID},
+		{"clOrdID", clOrdIDs, owners.byClOrdID},
+	}
+
+	for _, id := range ids {
+		for _, lookup := range lookups {
+			missing := missingKeys(lookup.keys, lookup.found)
+			if len(missing) < 1 {
+				continue
+			}
+
+			orders, err := queryOrders(client, id, lookup.field, missing)
+			if err != nil {
+				return nil, err
+			}
+
+			for idx := range orders {
+				ord, err := models.ConvertOrder(&orders[idx])
+				if err != nil {
+					return nil, err
+				}
+
+				key := ord.OrderID
+				if lookup.field == "clOrdID" {
+					key = ord.ClOrdID
+				}
+
+				lookup.found[key] = &orderOwner{id: id, ord: ord}
+			}
+		}
+	}
+
+	return &owners, nil
+}
+
 // orderCmd represents the order command
 var orderCmd = &cobra.Command{
 	Use:   "order",
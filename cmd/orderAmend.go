@@ -0,0 +1,392 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultAmendBatch = 100
+
+type orderAmendArgs struct {
+	amend models.Amendment
+
+	sourceFile string
+	batch      int
+}
+
+var orderAmendVariables orderAmendArgs
+
+type amendBatch struct {
+	lines      []int
+	amendments []*models.Amendment
+}
+
+func (b *amendBatch) Len() int {
+	return len(b.amendments)
+}
+
+func (b *amendBatch) Add(line int, amend *models.Amendment) {
+	b.lines = append(b.lines, line)
+	b.amendments = append(b.amendments, amend)
+}
+
+// checkAmendInstrument check amendment by instrument of amended order's
+// symbol, check is skipped if order's symbol is unknown.
+func checkAmendInstrument(amend *models.Amendment, owner *orderOwner) error {
+	if owner.ord == nil || owner.ord.Symbol == "" {
+		return nil
+	}
+
+	ins, err := lookupInstrument(owner.ord.Symbol)
+	if err != nil || ins == nil {
+		return err
+	}
+
+	return ins.CheckAmendment(amend)
+}
+
+// amendTarget describe amendment's target order
+func amendTarget(amend *models.Amendment) string {
+	if amend.OrderID != "" {
+		return "orderID " + amend.OrderID
+	}
+
+	return "origClOrdID " + amend.OrigClOrdID
+}
+
+// findAmendOwners find accounts owning orders to be amended
+func findAmendOwners(client *ngerest.APIClient,
+	amendments []*models.Amendment) (*orderOwners, error) {
+	var orderIDs, clOrdIDs []string
+
+	for _, amend := range amendments {
+		if amend.OrderID != "" {
+			orderIDs = append(orderIDs, amend.OrderID)
+		} else {
+			clOrdIDs = append(clOrdIDs, amend.OrigClOrdID)
+		}
+	}
+
+	return findOrderOwners(client, orderIDs, clOrdIDs)
+}
+
+// amendOwner get owner of amendment's order
+func amendOwner(owners *orderOwners, amend *models.Amendment) (
+	*orderOwner, error) {
+	owner, exist := owners.Find(amend.OrderID, amend.OrigClOrdID)
+	if !exist {
+		return nil, common.NewError(common.KindValidation,
+			amendTarget(amend), common.ErrOrderOwner)
+	}
+
+	return owner, nil
+}
+
+// checkAmendResult find amendment's result by OrderID of amended order,
+// or by ClOrdID if OrderID is unknown, rejected result is an error.
+func checkAmendResult(amend *models.Amendment, owner *orderOwner,
+	results []ngerest.Order) error {
+	orderID := amend.OrderID
+	if orderID == "" && owner.ord != nil {
+		orderID = owner.ord.OrderID
+	}
+
+	for idx := range results {
+		result := &results[idx]
+
+		switch {
+		case orderID != "":
+			if result.OrderID != orderID {
+				continue
+			}
+		case result.ClOrdID != amend.OrigClOrdID &&
+			(amend.ClOrdID == "" || result.ClOrdID != amend.ClOrdID):
+			continue
+		}
+
+		if result.OrdStatus == "Rejected" {
+			return common.NewError(common.KindRejected,
+				amendTarget(amend)+" "+result.OrdRejReason,
+				common.ErrOrderRejected)
+		}
+
+		return nil
+	}
+
+	return common.NewError(
+		common.KindRejected, amendTarget(amend), common.ErrAmendResult)
+}
+
+func amendOrder(client *ngerest.APIClient, amend *models.Amendment) {
+	if err := amend.Validate(); err != nil {
+		exitWithError(err)
+	}
+
+	owners, err := findAmendOwners(client, []*models.Amendment{amend})
+	if err != nil {
+		common.PrintError("Find order owner failed", err)
+		return
+	}
+
+	owner, err := amendOwner(owners, amend)
+	if err == nil {
+		err = checkAmendInstrument(amend, owner)
+	}
+
+	if err != nil {
+		common.PrintError("Amend order failed", err)
+		return
+	}
+
+	result, rsp, err := client.Order.OrderAmend(
+		auths.GetAuthContext(nil, owner.id), amend.AmendOpts())
+	if err != nil {
+		common.PrintError("Amend order failed",
+			common.NewAPIError("", err, rsp))
+		return
+	}
+
+	orderCache.PutResult(&result)
+}
+
+// amendOrderBulk amend orders in batch, amendments are grouped by
+// owner account, each line is accepted only if its result is matched.
+func amendOrderBulk(
+	client *ngerest.APIClient, batch *amendBatch, report *sourceReport) {
+	if batch.Len() < 1 {
+		return
+	}
+
+	owners, err := findAmendOwners(client, batch.amendments)
+	if err != nil {
+		for _, line := range batch.lines {
+			report.Reject(line, err)
+		}
+
+		return
+	}
+
+	var ids []string
+
+	groups := make(map[string]*amendBatch)
+	lineOwners := make(map[int]*orderOwner)
+
+	for idx, amend := range batch.amendments {
+		line := batch.lines[idx]
+
+		owner, err := amendOwner(owners, amend)
+		if err != nil {
+			report.Reject(line, err)
+			continue
+		}
+
+		if err = checkAmendInstrument(amend, owner); err != nil {
+			report.Skip(line, err)
+			continue
+		}
+
+		group, exist := groups[owner.id]
+		if !exist {
+			group = &amendBatch{}
+			groups[owner.id] = group
+			ids = append(ids, owner.id)
+		}
+
+		group.Add(line, amend)
+		lineOwners[line] = owner
+	}
+
+	for _, id := range ids {
+		group := groups[id]
+
+		orders, err := json.Marshal(group.amendments)
+		if err != nil {
+			for _, line := range group.lines {
+				report.Skip(line, err)
+			}
+
+			continue
+		}
+
+		results, rsp, err := client.Order.OrderAmendBulk(
+			auths.GetAuthContext(nil, id), &ngerest.OrderAmendBulkOpts{
+				Orders: optional.NewString(string(orders)),
+			})
+		if err != nil {
+			err = common.NewAPIError("", err, rsp)
+
+			for _, line := range group.lines {
+				report.Reject(line, err)
+			}
+
+			continue
+		}
+
+		for idx, line := range group.lines {
+			if err := checkAmendResult(group.amendments[idx],
+				lineOwners[line], results); err != nil {
+				report.Reject(line, err)
+			} else {
+				report.Accept(line)
+			}
+		}
+
+		for _, ord := range results {
+			orderCache.PutResult(&ord)
+		}
+	}
+}
+
+func readSourceAmendments(
+	client *ngerest.APIClient, path string, size int) *sourceReport {
+	records, err := models.ReadAmendFile(path)
+	if err != nil {
+		exitWithError(err, zap.String("file", path))
+	}
+
+	if size < 1 {
+		size = defaultAmendBatch
+	}
+
+	report := newSourceReport()
+	batch := &amendBatch{}
+
+	for record := range records {
+		if record.Err != nil {
+			report.Skip(record.Line, record.Err)
+			continue
+		}
+
+		if err := record.Amendment.Validate(); err != nil {
+			report.Skip(record.Line, err)
+			continue
+		}
+
+		batch.Add(record.Line, record.Amendment)
+
+		if batch.Len() >= size {
+			amendOrderBulk(client, batch, report)
+
+			batch = &amendBatch{}
+		}
+	}
+
+	amendOrderBulk(client, batch, report)
+
+	return report
+}
+
+// orderAmendCmd represents the orderAmend command
+var orderAmendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Amend orders for user.",
+	Long: `Amend order by orderID or origClOrdID in args input,
+or amend orders in bulk by a amendment source file.
+New prices & quantities are checked by instrument of amended order's
+symbol, check is skipped if order's symbol is unknown.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if orderAmendVariables.sourceFile != "" {
+			return nil
+		}
+
+		return orderAmendVariables.amend.Validate()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printOrderResults(&waitOutput, orderCache.GetResults())
+
+		var report *sourceReport
+
+		if orderAmendVariables.sourceFile != "" {
+			report = readSourceAmendments(
+				client, orderAmendVariables.sourceFile,
+				orderAmendVariables.batch)
+		} else {
+			amendOrder(client, &orderAmendVariables.amend)
+		}
+
+		orderCache.CloseResults()
+
+		waitOutput.Wait()
+
+		if report != nil {
+			report.Print()
+		}
+	},
+}
+
+func init() {
+	orderCmd.AddCommand(orderAmendCmd)
+
+	orderAmendCmd.Flags().StringVar(
+		&orderAmendVariables.amend.OrderID, "orderid", "",
+		"OrderID to be amended.")
+	orderAmendCmd.Flags().StringVar(
+		&orderAmendVariables.amend.OrigClOrdID, "origclordid", "",
+		"Original ClOrdID to be amended.")
+	orderAmendCmd.Flags().StringVar(
+		&orderAmendVariables.amend.ClOrdID, "clordid", "",
+		"New ClOrdID for amended order.")
+	orderAmendCmd.Flags().Float64Var(
+		&orderAmendVariables.amend.Price, "price", 0,
+		"New price for order.")
+	orderAmendCmd.Flags().Float32Var(
+		&orderAmendVariables.amend.OrderQty, "qty", 0,
+		"New order quantity for order.")
+	orderAmendCmd.Flags().Float32Var(
+		&orderAmendVariables.amend.LeavesQty, "leaves-qty", 0,
+		"New leaves quantity for order.")
+	orderAmendCmd.Flags().Float64Var(
+		&orderAmendVariables.amend.StopPx, "stop-px", 0,
+		"New stop price for order.")
+	orderAmendCmd.Flags().Float64Var(
+		&orderAmendVariables.amend.PegOffsetValue, "peg-offset", 0,
+		"New peg offset value for order.")
+	orderAmendCmd.Flags().StringVar(
+		&orderAmendVariables.amend.Text, "text", "",
+		"Amend annotation.")
+
+	orderAmendCmd.Flags().StringVarP(
+		&orderAmendVariables.sourceFile, "file", "f", "",
+		"Amendment source file in csv or json format.")
+	orderAmendCmd.Flags().IntVar(
+		&orderAmendVariables.batch, "batch", defaultAmendBatch,
+		"Amendment count in each bulk request.")
+}
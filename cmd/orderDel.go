@@ -15,7 +15,20 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
 
 	"github.com/frozenpine/ngecli/models"
 
@@ -23,22 +36,259 @@ import (
 )
 
 type orderDelArgs struct {
-	volume int64
-	side   models.OrderSide
+	orderIDs models.FlagStrings
+	clOrdIDs models.FlagStrings
+	side     models.OrderSide
+	all      bool
+	after    time.Duration
+	text     string
 }
 
 var orderDelVariables orderDelArgs
 
+func checkDelArgs(vars *orderDelArgs) bool {
+	if len(vars.orderIDs.Values()) < 1 && len(vars.clOrdIDs.Values()) < 1 &&
+		!vars.all && vars.after <= 0 {
+		logger.Error(common.ErrCancelTarget.Error())
+		return false
+	}
+
+	if vars.all {
+		if err := common.CheckSymbol(symbol); err != nil {
+			logger.Error(err.Error())
+			return false
+		}
+	}
+
+	if vars.side != "" && !vars.all {
+		logger.Warn("side filter only works with cancel all.")
+	}
+
+	return true
+}
+
+func makeOrderCancelOpts(
+	orderIDs, clOrdIDs []string, text string) *ngerest.OrderCancelOpts {
+	opts := ngerest.OrderCancelOpts{}
+
+	if len(orderIDs) > 0 {
+		opts.OrderID = optional.NewString(strings.Join(orderIDs, ","))
+	}
+
+	if len(clOrdIDs) > 0 {
+		opts.ClOrdID = optional.NewString(strings.Join(clOrdIDs, ","))
+	}
+
+	if text != "" {
+		opts.Text = optional.NewString(text)
+	}
+
+	return &opts
+}
+
+func makeOrderCancelAllOpts(vars *orderDelArgs) *ngerest.OrderCancelAllOpts {
+	opts := ngerest.OrderCancelAllOpts{}
+
+	if symbol != "" {
+		opts.Symbol = optional.NewString(symbol)
+	}
+
+	if vars.side != "" {
+		filter, _ := json.Marshal(map[string]string{
+			"side": vars.side.String()})
+
+		opts.Filter = optional.NewString(string(filter))
+	}
+
+	if vars.text != "" {
+		opts.Text = optional.NewString(vars.text)
+	}
+
+	return &opts
+}
+
+// cancelTargets orders to be canceled in one account
+type cancelTargets struct {
+	orderIDs []string
+	clOrdIDs []string
+}
+
+func cancelOrders(client *ngerest.APIClient, vars *orderDelArgs) {
+	if len(vars.orderIDs.Values()) < 1 && len(vars.clOrdIDs.Values()) < 1 {
+		return
+	}
+
+	owners, err := findOrderOwners(
+		client, vars.orderIDs.Values(), vars.clOrdIDs.Values())
+	if err != nil {
+		common.PrintError("Find order owners failed", err)
+		return
+	}
+
+	var ids []string
+
+	targets := make(map[string]*cancelTargets)
+
+	target := func(owner *orderOwner) *cancelTargets {
+		t, exist := targets[owner.id]
+		if !exist {
+			t = &cancelTargets{}
+			targets[owner.id] = t
+			ids = append(ids, owner.id)
+		}
+
+		return t
+	}
+
+	for _, orderID := range vars.orderIDs.Values() {
+		owner, exist := owners.Find(orderID, "")
+		if !exist {
+			common.PrintError("Cancel order failed", common.NewError(
+				common.KindValidation, "orderID "+orderID,
+				common.ErrOrderOwner))
+			continue
+		}
+
+		t := target(owner)
+		t.orderIDs = append(t.orderIDs, orderID)
+	}
+
+	for _, clOrdID := range vars.clOrdIDs.Values() {
+		owner, exist := owners.Find("", clOrdID)
+		if !exist {
+			common.PrintError("Cancel order failed", common.NewError(
+				common.KindValidation, "clOrdID "+clOrdID,
+				common.ErrOrderOwner))
+			continue
+		}
+
+		t := target(owner)
+		t.clOrdIDs = append(t.clOrdIDs, clOrdID)
+	}
+
+	for _, id := range ids {
+		canceled, rsp, err := client.Order.OrderCancel(
+			auths.GetAuthContext(nil, id), makeOrderCancelOpts(
+				targets[id].orderIDs, targets[id].clOrdIDs, vars.text))
+		if err != nil {
+			common.PrintError("Cancel order failed",
+				common.NewAPIError("", err, rsp))
+			continue
+		}
+
+		for _, ord := range canceled {
+			orderCache.PutResult(&ord)
+		}
+	}
+}
+
+func cancelAllOrders(client *ngerest.APIClient, id string, vars *orderDelArgs) {
+	if !vars.all {
+		return
+	}
+
+	canceled, rsp, err := client.Order.OrderCancelAll(
+		auths.GetAuthContext(nil, id), makeOrderCancelAllOpts(vars))
+	if err != nil {
+		common.PrintError("Cancel all orders failed",
+			common.NewAPIError("", err, rsp))
+		return
+	}
+
+	for _, ord := range canceled {
+		orderCache.PutResult(&ord)
+	}
+}
+
+func cancelAllAfter(client *ngerest.APIClient, id string, vars *orderDelArgs) {
+	if vars.after <= 0 {
+		return
+	}
+
+	result, rsp, err := client.Order.OrderCancelAllAfter(
+		auths.GetAuthContext(nil, id),
+		float64(vars.after/time.Millisecond))
+	if err != nil {
+		common.PrintError("Cancel all after failed",
+			common.NewAPIError("", err, rsp))
+		return
+	}
+
+	jsonBytes, _ := json.Marshal(result)
+
+	logger.Info("Cancel all after set.",
+		zap.String("id", id), zap.Duration("after", vars.after))
+	fmt.Println(string(jsonBytes))
+}
+
 // orderDelCmd represents the orderDel command
 var orderDelCmd = &cobra.Command{
-	Use:   "del",
+	Use:   "del [orderID...]",
 	Short: "Cancel orders for user",
-	Long:  `Cancel orders in NGE`,
+	Long: `Cancel orders in NGE by orderID or clOrdID,
+or cancel all orders in symbol with optional side filter,
+or cancel all orders after specified timeout(dead man's switch).`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			orderDelVariables.orderIDs.Set(strings.Join(args, ","))
+		}
+
+		if !checkDelArgs(&orderDelVariables) {
+			return common.ErrArgs
+		}
+
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("orderDel called")
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printOrderResults(&waitOutput, orderCache.GetResults())
+
+		cancelOrders(client, &orderDelVariables)
+
+		ids, err := auths.AuthIDs()
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+		}
+
+		// cancel all & cancel all after will be applied to all accounts
+		for _, id := range ids {
+			cancelAllOrders(client, id, &orderDelVariables)
+			cancelAllAfter(client, id, &orderDelVariables)
+		}
+
+		orderCache.CloseResults()
+
+		waitOutput.Wait()
 	},
 }
 
 func init() {
 	orderCmd.AddCommand(orderDelCmd)
+
+	orderDelCmd.Flags().Var(
+		&orderDelVariables.orderIDs, "orderid",
+		"OrderIDs to be canceled, also can be specified in args.")
+	orderDelCmd.Flags().Var(
+		&orderDelVariables.clOrdIDs, "clordid",
+		"ClOrdIDs to be canceled.")
+	orderDelCmd.Flags().BoolVar(
+		&orderDelVariables.all, "all", false,
+		"Cancel all orders in symbol.")
+	orderDelCmd.Flags().Var(
+		&orderDelVariables.side, "side", "Side filter for cancel all.")
+	orderDelCmd.Flags().DurationVar(
+		&orderDelVariables.after, "after", 0,
+		"Cancel all orders after timeout, 0 means disabled.")
+	orderDelCmd.Flags().StringVar(
+		&orderDelVariables.text, "text", "", "Cancel annotation.")
 }
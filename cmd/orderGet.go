@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
 	"sync"
 
 	"github.com/frozenpine/ngecli/logger"
@@ -32,20 +30,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const defaultGetOrderCount = 200
+var orderGetVariables queryArgs
 
-type orderGetArgs struct {
-	filter  string
-	columns string
-	start   models.FlagTime
-	end     models.FlagTime
-	count   int
-	reverse bool
-}
-
-var orderGetVariables orderGetArgs
-
-func getOrderOpts(symbol string, args *orderGetArgs) *ngerest.OrderGetOrdersOpts {
+func getOrderOpts(symbol string, args *queryArgs) *ngerest.OrderGetOrdersOpts {
 	options := ngerest.OrderGetOrdersOpts{}
 
 	if symbol != "" {
@@ -64,11 +51,11 @@ func getOrderOpts(symbol string, args *orderGetArgs) *ngerest.OrderGetOrdersOpts
 		options.Reverse = optional.NewBool(args.reverse)
 	}
 
-	if args.start != models.EmptyTime {
+	if args.start.IsSet() {
 		options.StartTime = optional.NewTime(args.start.GetTime())
 	}
 
-	if args.end != models.EmptyTime {
+	if args.end.IsSet() {
 		options.EndTime = optional.NewTime(args.end.GetTime())
 	}
 
@@ -82,16 +69,18 @@ func getOrderOpts(symbol string, args *orderGetArgs) *ngerest.OrderGetOrdersOpts
 func printOrderResults(wait *sync.WaitGroup, results <-chan *models.Order) {
 	var count int
 
+	formatter := newFormatter()
+
 	for ord := range results {
-		jsonBytes, err := json.Marshal(ord)
-		if err != nil {
+		if err := formatter.Format(ord); err != nil {
 			logger.Warn(err.Error())
 		} else {
-			fmt.Println(string(jsonBytes))
 			count++
 		}
 	}
 
+	flushFormatter(formatter)
+
 	logger.Info("All order results printed.", zap.Int("count", count))
 	wait.Done()
 }
@@ -102,12 +91,7 @@ var orderGetCmd = &cobra.Command{
 	Short: "Get user's history orders.",
 	Long:  `Get user's history orders.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if !auths.HasSavedAuth(common.GetBaseHost()) &&
-			!auths.HasDefaultAuth() {
-			identity, password := CollectLoginInfo()
-
-			auths.DefaultID, auths.DefaultPass = identity, *password
-		}
+		checkLoginInfo()
 
 		client, err := clientHub.GetClient(common.GetBaseHost())
 		if err != nil {
@@ -115,45 +99,54 @@ var orderGetCmd = &cobra.Command{
 			return
 		}
 
-		hisOrders, _, err := client.Order.OrderGetOrders(
-			auths.NextAuth(nil), getOrderOpts(symbol, &orderGetVariables))
-
+		authCtx, err := auths.NextAuth(nil)
 		if err != nil {
-			common.PrintError("Get order failed", err)
-			return
-		} else if len(hisOrders) < 1 {
-			logger.Warn("No history orders found.")
+			common.PrintError("Load auth failed", err)
 			return
 		}
 
+		query := func(start, count int) ([]interface{}, error) {
+			options := getOrderOpts(symbol, &orderGetVariables)
+			options.Start = optional.NewFloat32(float32(start))
+			options.Count = optional.NewFloat32(float32(count))
+
+			hisOrders, rsp, err := client.Order.OrderGetOrders(authCtx, options)
+
+			rows := make([]interface{}, len(hisOrders))
+			for idx := range hisOrders {
+				rows[idx] = &hisOrders[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		}
+
+		pager := orderGetVariables.newPager(query, func(row interface{}) string {
+			return row.(*ngerest.Order).OrderID
+		})
+
 		waitOutput := sync.WaitGroup{}
 		waitOutput.Add(1)
 
 		go printOrderResults(&waitOutput, orderCache.GetResults())
 
-		for _, order := range hisOrders {
-			orderCache.PutResult(&order)
-		}
+		total, err := pager.Fetch(func(row interface{}) {
+			orderCache.PutResult(row.(*ngerest.Order))
+		})
 
 		orderCache.CloseResults()
 
 		waitOutput.Wait()
+
+		if err != nil {
+			common.PrintError("Get order failed", err)
+		} else if total < 1 {
+			logger.Warn("No history orders found.")
+		}
 	},
 }
 
 func init() {
 	orderCmd.AddCommand(orderGetCmd)
 
-	orderGetCmd.Flags().StringVar(
-		&orderGetVariables.filter, "filter", "", "Filter string applied in query result")
-	orderGetCmd.Flags().StringVar(
-		&orderGetVariables.columns, "columns", "", "Column names for query result.")
-
-	orderGetCmd.Flags().BoolVarP(
-		&orderGetVariables.reverse, "reverse", "r", false, "Getting query results in reversed order.")
-
-	orderGetCmd.Flags().VarP(&orderGetVariables.start, "start", "s", "Start")
-	orderGetCmd.Flags().VarP(&orderGetVariables.end, "end", "e", "End")
-
-	orderGetCmd.Flags().IntVarP(&orderGetVariables.count, "count", "c", defaultGetOrderCount, "Order count in query result.")
+	bindQueryFlags(orderGetCmd, &orderGetVariables)
 }
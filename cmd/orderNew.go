@@ -15,6 +15,15 @@
 package cmd
 
 import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/frozenpine/ngecli/logger"
@@ -30,11 +39,13 @@ import (
 )
 
 const (
-	defaultPrice     = float64(5050)
-	defaultTick      = float64(0.01)
-	defaultVolume    = int64(1)
-	defaultMaxVolume = int64(10)
-	defaultCount     = 1
+	defaultPrice      = float64(5050)
+	defaultTick       = float64(0.01)
+	defaultPriceRange = 10
+	defaultVolume     = int64(1)
+	defaultMaxVolume  = int64(10)
+	defaultCount      = 1
+	defaultWorkers    = 10
 )
 
 type orderNewArgs struct {
@@ -44,11 +55,20 @@ type orderNewArgs struct {
 
 	basePrice  float64
 	priceTick  float64
+	priceRange int
 	baseVolume int64
 	maxVolume  int64
 	random     bool
 	bothSide   bool
 	count      int
+
+	sourceFile string
+
+	timeout   time.Duration
+	rate      float64
+	totalRate float64
+	inflight  int
+	workers   int
 }
 
 var orderNewVariables orderNewArgs
@@ -59,24 +79,91 @@ func checkArgs(vars *orderNewArgs) bool {
 		return false
 	}
 
-	if err := common.CheckPrice(vars.price); err != nil {
-		logger.Error(err.Error())
+	if vars.count < 1 {
+		logger.Error(common.ErrCount.Error())
 		return false
 	}
 
-	if err := common.CheckQuantity(vars.volume); err != nil {
+	if vars.random && vars.price == 0 {
+		if err := common.CheckPrice(vars.basePrice); err != nil {
+			logger.Error(err.Error())
+			return false
+		}
+
+		if vars.priceTick <= 0 {
+			logger.Error(common.ErrTick.Error())
+			return false
+		}
+	} else if err := common.CheckPrice(vars.price); err != nil {
 		logger.Error(err.Error())
 		return false
 	}
 
-	if err := vars.side.MatchSide(vars.volume); err != nil {
+	if vars.random && vars.volume == 0 {
+		if vars.baseVolume <= 0 {
+			logger.Error(common.ErrQuantity.Error())
+			return false
+		}
+
+		if vars.maxVolume < vars.baseVolume {
+			logger.Error(common.ErrVolumeRange.Error())
+			return false
+		}
+	} else if err := common.CheckQuantity(vars.volume); err != nil {
 		logger.Error(err.Error())
 		return false
 	}
 
+	if vars.volume != 0 {
+		if err := vars.side.MatchSide(vars.volume); err != nil {
+			logger.Error(err.Error())
+			return false
+		}
+	}
+
 	return true
 }
 
+// applyInstrument check fixed price & volume by instrument of symbol,
+// instrument's tick size & price are used for random orders if --tick &
+// --base-price not specified, flags are set so they will be reset in shell.
+func applyInstrument(cmd *cobra.Command, vars *orderNewArgs) error {
+	ins, err := lookupInstrument(symbol)
+	if err != nil || ins == nil {
+		return err
+	}
+
+	if err = ins.CheckState(); err != nil {
+		return err
+	}
+
+	if vars.price != 0 {
+		if err = ins.CheckPrice(vars.price); err != nil {
+			return err
+		}
+	}
+
+	if vars.volume != 0 {
+		if err = ins.CheckQuantity(float64(vars.volume)); err != nil {
+			return err
+		}
+	}
+
+	flags := cmd.Flags()
+
+	if !flags.Changed("tick") && ins.TickSize > 0 {
+		flags.Set("tick", strconv.FormatFloat(ins.TickSize, 'f', -1, 64))
+	}
+
+	if price := ins.BasePrice(); !flags.Changed("base-price") && price > 0 {
+		price = math.Round(price/vars.priceTick) * vars.priceTick
+
+		flags.Set("base-price", strconv.FormatFloat(price, 'f', -1, 64))
+	}
+
+	return nil
+}
+
 func makeOrderNewOpts(ord *models.Order) *ngerest.OrderNewOpts {
 	opt := ngerest.OrderNewOpts{}
 
@@ -92,45 +179,441 @@ func makeOrderNewOpts(ord *models.Order) *ngerest.OrderNewOpts {
 	}
 	opt.OrderQty = optional.NewFloat32(ord.OrderQty)
 
-	if err := common.CheckPrice(ord.Price); err != nil {
-		logger.Error("invalid order price:",
-			zap.Float64("price", ord.Price))
-		return nil
+	if ord.Price != 0 || isLimitOrder(ord) {
+		if err := common.CheckPrice(ord.Price); err != nil {
+			logger.Error("invalid order price:",
+				zap.Float64("price", ord.Price))
+			return nil
+		}
+		opt.Price = optional.NewFloat64(ord.Price)
+	}
+
+	if ord.ClOrdID != "" {
+		opt.ClOrdID = optional.NewString(ord.ClOrdID)
+	}
+	if ord.ClOrdLinkID != "" {
+		opt.ClOrdLinkID = optional.NewString(ord.ClOrdLinkID)
+	}
+	if ord.DisplayQty != 0 {
+		opt.DisplayQty = optional.NewFloat32(ord.DisplayQty)
+	}
+	if ord.StopPx != 0 {
+		opt.StopPx = optional.NewFloat64(ord.StopPx)
+	}
+	if ord.PegOffsetValue != 0 {
+		opt.PegOffsetValue = optional.NewFloat64(ord.PegOffsetValue)
+	}
+	if ord.PegPriceType != "" {
+		opt.PegPriceType = optional.NewString(ord.PegPriceType)
+	}
+	if ord.OrdType != "" {
+		opt.OrdType = optional.NewString(ord.OrdType)
+	}
+	if ord.TimeInForce != "" {
+		opt.TimeInForce = optional.NewString(ord.TimeInForce)
+	}
+	if ord.ExecInst != "" {
+		opt.ExecInst = optional.NewString(ord.ExecInst)
+	}
+	if ord.ContingencyType != "" {
+		opt.ContingencyType = optional.NewString(ord.ContingencyType)
+	}
+	if ord.Text != "" {
+		opt.Text = optional.NewString(ord.Text)
 	}
-	opt.Price = optional.NewFloat64(ord.Price)
 
 	return &opt
 }
 
+// isLimitOrder check if order type requires a price,
+// order type is Limit if not specified
+func isLimitOrder(ord *models.Order) bool {
+	return ord.OrdType == "" || strings.Contains(ord.OrdType, "Limit")
+}
+
+// checkOrder validate order read from source file,
+// symbol will be override by --symbol if not specified in order
+func checkOrder(ord *models.Order) error {
+	if ord.Symbol == "" {
+		ord.Symbol = symbol
+	}
+
+	if err := common.CheckSymbol(ord.Symbol); err != nil {
+		return err
+	}
+
+	qty := int64(ord.OrderQty)
+
+	if err := common.CheckQuantity(qty); err != nil {
+		return err
+	}
+
+	if err := ord.Side.MatchSide(qty); err != nil {
+		return err
+	}
+
+	ord.OrderQty = float32(math.Abs(float64(ord.OrderQty)))
+
+	if isLimitOrder(ord) {
+		if err := common.CheckPrice(ord.Price); err != nil {
+			return err
+		}
+	}
+
+	return checkOrderInstrument(ord)
+}
+
+// randPrice get a random price in [base - range * tick, base + range * tick]
+func randPrice(base, tick float64, priceRange int) float64 {
+	offset := rand.Intn(2*priceRange+1) - priceRange
+
+	price := base + float64(offset)*tick
+
+	return math.Round(price/tick) * tick
+}
+
+// randVolume get a random volume in [base, max]
+func randVolume(base, max int64) int64 {
+	return base + rand.Int63n(max-base+1)
+}
+
+func randSide() models.OrderSide {
+	if rand.Intn(2) == 0 {
+		return models.Buy
+	}
+
+	return models.Sell
+}
+
+func makeOrders(vars *orderNewArgs) []*models.Order {
+	var orders []*models.Order
+
+	rand.Seed(time.Now().UnixNano())
+
+	for i := 0; i < vars.count; i++ {
+		price, volume, side := vars.price, vars.volume, vars.side
+
+		if vars.random {
+			if price == 0 {
+				price = randPrice(vars.basePrice, vars.priceTick, vars.priceRange)
+			}
+
+			if volume == 0 {
+				volume = randVolume(vars.baseVolume, vars.maxVolume)
+			}
+
+			if side == "" {
+				side = randSide()
+			}
+		}
+
+		qty := float32(math.Abs(float64(volume)))
+
+		if vars.bothSide {
+			orders = append(orders,
+				&models.Order{
+					Symbol: symbol, Side: models.Buy,
+					Price: price, OrderQty: qty},
+				&models.Order{
+					Symbol: symbol, Side: models.Sell,
+					Price: price, OrderQty: qty})
+
+			continue
+		}
+
+		orders = append(orders, &models.Order{
+			Symbol: symbol, Side: side, Price: price, OrderQty: qty})
+	}
+
+	return orders
+}
+
+func newOrderSender() (models.OrderSender, error) {
+	client, err := clientHub.GetClient(common.GetBaseHost())
+	if err != nil {
+		return nil, err
+	}
+
+	sender := func(id string, ord *models.Order) (*ngerest.Order, error) {
+		opts := makeOrderNewOpts(ord)
+		if opts == nil {
+			return nil, common.ErrOrder
+		}
+
+		result, rsp, err := client.Order.OrderNew(
+			auths.GetAuthContext(nil, id), ord.Symbol, opts)
+		if err != nil {
+			return nil, common.NewAPIError("", err, rsp)
+		}
+
+		return &result, nil
+	}
+
+	return sender, nil
+}
+
+// checkOrderRate check order rates can be limited by token bucket
+func checkOrderRate(vars *orderNewArgs) error {
+	for name, rate := range map[string]float64{
+		"rate": vars.rate, "total-rate": vars.totalRate} {
+		if rate > models.MaxOrderRate {
+			return common.NewError(
+				common.KindValidation, name, common.ErrOrderRate)
+		}
+	}
+
+	return nil
+}
+
+func setOrderLimits(vars *orderNewArgs) {
+	orderCache.SetOrderRate(vars.rate, vars.totalRate)
+	orderCache.SetMaxInflight(vars.inflight)
+}
+
+func sendOrders(orders []*models.Order) {
+	sender, err := newOrderSender()
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	waitSend := orderCache.Dispatch(
+		orderNewVariables.workers, sender,
+		func(ord *models.Order, err error) {
+			if err != nil {
+				common.PrintError("New order failed", err)
+			}
+		})
+
+	for _, ord := range orders {
+		if err := checkOrderInstrument(ord); err != nil {
+			common.PrintError("New order failed", err)
+			continue
+		}
+
+		id, err := auths.NextAuthID()
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			break
+		}
+
+		if err = orderCache.PutOrder(
+			id, ord, orderNewVariables.timeout); err != nil {
+			common.PrintError("New order failed", err)
+		}
+	}
+
+	orderCache.CloseInputs()
+
+	waitSend.Wait()
+}
+
+type sourceReport struct {
+	accepted []int
+	rejected map[int]error
+	skipped  map[int]error
+
+	lock sync.Mutex
+}
+
+func (r *sourceReport) Accept(line int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.accepted = append(r.accepted, line)
+}
+
+func (r *sourceReport) Reject(line int, err error) {
+	common.SetExitError(err)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rejected[line] = err
+}
+
+func (r *sourceReport) Skip(line int, err error) {
+	common.SetExitError(err)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.skipped[line] = err
+}
+
+func printLineErrors(title string, lineErrors map[int]error) {
+	lines := make([]int, 0, len(lineErrors))
+	for line := range lineErrors {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	fmt.Printf("%s: %d\n", title, len(lines))
+
+	for _, line := range lines {
+		fmt.Printf("\tline[%d]: %s\n", line, lineErrors[line].Error())
+	}
+}
+
+func (r *sourceReport) Print() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sort.Ints(r.accepted)
+
+	lines := make([]string, len(r.accepted))
+	for idx, line := range r.accepted {
+		lines[idx] = strconv.Itoa(line)
+	}
+
+	fmt.Printf("Accepted: %d\n", len(r.accepted))
+	if len(lines) > 0 {
+		fmt.Printf("\tline[%s]\n", strings.Join(lines, ","))
+	}
+
+	printLineErrors("Rejected", r.rejected)
+	printLineErrors("Skipped", r.skipped)
+}
+
+func newSourceReport() *sourceReport {
+	report := sourceReport{
+		rejected: make(map[int]error),
+		skipped:  make(map[int]error),
+	}
+
+	return &report
+}
+
+func readSourceOrders(path string) *sourceReport {
+	records, err := models.ReadOrderFile(path)
+	if err != nil {
+		exitWithError(err, zap.String("file", path))
+	}
+
+	sender, err := newOrderSender()
+	if err != nil {
+		exitWithError(err)
+	}
+
+	report := newSourceReport()
+	lines := sync.Map{}
+
+	waitSend := orderCache.Dispatch(
+		orderNewVariables.workers, sender,
+		func(ord *models.Order, err error) {
+			value, _ := lines.Load(ord)
+			line := value.(int)
+
+			if err != nil {
+				report.Reject(line, err)
+			} else {
+				report.Accept(line)
+			}
+		})
+
+	for record := range records {
+		if record.Err != nil {
+			report.Skip(record.Line, record.Err)
+			continue
+		}
+
+		if err := checkOrder(record.Order); err != nil {
+			report.Skip(record.Line, err)
+			continue
+		}
+
+		id, err := auths.NextAuthID()
+		if err != nil {
+			report.Skip(record.Line, err)
+			continue
+		}
+
+		lines.Store(record.Order, record.Line)
+
+		if err = orderCache.PutOrder(
+			id, record.Order, orderNewVariables.timeout); err != nil {
+			report.Skip(record.Line, err)
+		}
+	}
+
+	orderCache.CloseInputs()
+
+	waitSend.Wait()
+
+	return report
+}
+
 // orderNewCmd represents the orderGet command
 var orderNewCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Make new order for user.",
-	Long:  `Make new orders either by args input or a order source file.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Make new orders either by args input or a order source file.
+Orders are checked by tick size, lot size, max price, max order quantity
+& state of instrument before sent, if instruments can be loaded.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkOrderRate(&orderNewVariables); err != nil {
+			return err
+		}
+
+		if orderNewVariables.sourceFile != "" {
+			return nil
+		}
+
 		if !checkArgs(&orderNewVariables) {
-			logger.Fatal("variables check failed.")
+			return common.ErrArgs
+		}
+
+		return applyInstrument(cmd, &orderNewVariables)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		setOrderLimits(&orderNewVariables)
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printOrderResults(&waitOutput, orderCache.GetResults())
+
+		var report *sourceReport
+
+		if orderNewVariables.sourceFile != "" {
+			report = readSourceOrders(orderNewVariables.sourceFile)
+		} else {
+			sendOrders(makeOrders(&orderNewVariables))
 		}
 
+		orderCache.CloseResults()
+
+		waitOutput.Wait()
+
+		if report != nil {
+			report.Print()
+		}
 	},
 }
 
 func init() {
 	orderCmd.AddCommand(orderNewCmd)
 
-	orderNewCmd.Flags().Float64VarP(
-		&orderNewVariables.price, "price", "p", 0, "Price for new order.")
-	orderNewCmd.Flags().Int64VarP(
-		&orderNewVariables.volume, "volume", "v", 0, "Volume for new order.")
+	orderNewCmd.Flags().Float64Var(
+		&orderNewVariables.price, "price", 0, "Price for new order.")
+	orderNewCmd.Flags().Int64Var(
+		&orderNewVariables.volume, "volume", 0, "Volume for new order.")
 	orderNewCmd.Flags().Var(
 		&orderNewVariables.side, "side", "Side for new order.")
 
 	orderNewCmd.Flags().Float64Var(
 		&orderNewVariables.basePrice, "base-price", defaultPrice,
-		"Base price for random order.")
+		"Base price for random order, instrument's last price is used "+
+			"if not specified.")
 	orderNewCmd.Flags().Float64Var(
 		&orderNewVariables.priceTick, "tick", defaultTick,
-		"Price tick for new order.")
+		"Price tick for random order, instrument's tick size is used "+
+			"if not specified.")
+	orderNewCmd.Flags().IntVar(
+		&orderNewVariables.priceRange, "tick-range", defaultPriceRange,
+		"Random price range in ticks around base price.")
 
 	orderNewCmd.Flags().Int64Var(
 		&orderNewVariables.baseVolume, "base-volume", defaultVolume,
@@ -148,4 +631,23 @@ func init() {
 	orderNewCmd.Flags().IntVarP(
 		&orderNewVariables.count, "count", "c", defaultCount,
 		"Count of new orders.")
+
+	orderNewCmd.Flags().StringVarP(
+		&orderNewVariables.sourceFile, "file", "f", "",
+		"Order source file in csv or json format.")
+	orderNewCmd.Flags().DurationVar(
+		&orderNewVariables.timeout, "timeout", 0,
+		"Timeout for waiting inflight & rate limit, 0 means no timeout.")
+	orderNewCmd.Flags().Float64Var(
+		&orderNewVariables.rate, "rate", models.DefaultOrderRatePerUser,
+		"Order rate per second for each account, 0 means unlimited.")
+	orderNewCmd.Flags().Float64Var(
+		&orderNewVariables.totalRate, "total-rate", models.DefaultOrderRateTotal,
+		"Order rate per second for all accounts, 0 means unlimited.")
+	orderNewCmd.Flags().IntVar(
+		&orderNewVariables.inflight, "inflight", models.DefaultInflightOrders,
+		"Max inflight orders for each account.")
+	orderNewCmd.Flags().IntVar(
+		&orderNewVariables.workers, "workers", defaultWorkers,
+		"Worker count for sending orders.")
 }
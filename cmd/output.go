@@ -0,0 +1,67 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/viper"
+	"github.com/spf13/cobra"
+)
+
+var outputFields models.FlagStrings
+
+func bindOutputFlags(cmd *cobra.Command) {
+	viper.SetDefault("output", models.DefaultOutputFormat)
+	cmd.PersistentFlags().String(
+		"output", models.DefaultOutputFormat,
+		"Output format, available: table, csv, json, jsonl, yaml.")
+	viper.BindPFlag("output", cmd.PersistentFlags().Lookup("output"))
+
+	cmd.PersistentFlags().Var(
+		&outputFields, "fields",
+		"Fields to be output, separated by comma, default all fields.")
+}
+
+func checkOutputFormat() error {
+	if !models.CheckOutputFormat(viper.GetString("output")) {
+		return common.ErrOutputFormat
+	}
+
+	return nil
+}
+
+func newFormatter() models.Formatter {
+	formatter, err := models.NewFormatter(
+		viper.GetString("output"), os.Stdout, outputFields.Values())
+
+	if err != nil {
+		exitWithError(err)
+	}
+
+	return formatter
+}
+
+func flushFormatter(formatter models.Formatter) {
+	if err := formatter.Flush(); err != nil {
+		logger.Warn(err.Error())
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// printPositions print positions with output formatter
+func printPositions(positions ...ngerest.Position) {
+	formatter := newFormatter()
+
+	for idx := range positions {
+		converted, err := models.ConvertPosition(&positions[idx])
+		if err != nil {
+			logger.Warn(err.Error())
+			continue
+		}
+
+		if err := formatter.Format(converted); err != nil {
+			logger.Warn(err.Error())
+		}
+	}
+
+	flushFormatter(formatter)
+}
+
+// positionCmd represents the position command
+var positionCmd = &cobra.Command{
+	Use:   "position",
+	Short: "position functions",
+	Long:  `All functions for Position table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("position called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(positionCmd)
+}
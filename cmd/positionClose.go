@@ -0,0 +1,84 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"sync"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var positionClosePrice float64
+
+// positionCloseCmd represents the positionClose command
+var positionCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "Close position.",
+	Long: `Close position in --symbol with a market order,
+or with a limit order if --price specified.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		options := ngerest.OrderClosePositionOpts{}
+		if positionClosePrice > 0 {
+			options.Price = optional.NewFloat64(positionClosePrice)
+		}
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printOrderResults(&waitOutput, orderCache.GetResults())
+
+		ord, rsp, err := client.Order.OrderClosePosition(
+			authCtx, symbol, &options)
+		if err != nil {
+			common.PrintError("Close position failed",
+				common.NewAPIError("", err, rsp))
+		} else {
+			orderCache.PutResult(&ord)
+		}
+
+		orderCache.CloseResults()
+
+		waitOutput.Wait()
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionCloseCmd)
+
+	positionCloseCmd.Flags().Float64Var(
+		&positionClosePrice, "price", 0,
+		"Limit price for close order, market order if not specified.")
+}
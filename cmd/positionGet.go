@@ -0,0 +1,135 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+type positionGetArgs struct {
+	filter  string
+	columns string
+	count   int
+}
+
+var positionGetVariables positionGetArgs
+
+// getPositionOpts make position query options, symbol will be merged
+// into filter if specified.
+func getPositionOpts(symbol string, args *positionGetArgs) (
+	*ngerest.PositionGetOpts, error) {
+	options := ngerest.PositionGetOpts{}
+
+	filter := make(map[string]interface{})
+
+	if args.filter != "" {
+		if err := json.Unmarshal([]byte(args.filter), &filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if symbol != "" {
+		filter["symbol"] = symbol
+	}
+
+	if len(filter) > 0 {
+		content, _ := json.Marshal(filter)
+		options.Filter = optional.NewString(string(content))
+	}
+
+	if args.columns != "" {
+		options.Columns = optional.NewString(args.columns)
+	}
+
+	if args.count > 0 {
+		options.Count = optional.NewFloat32(float32(args.count))
+	}
+
+	return &options, nil
+}
+
+// positionGetCmd represents the positionGet command
+var positionGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get positions.",
+	Long: `Get positions of user, all symbols' positions will be returned
+unless --symbol specified.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		var filterSymbol string
+		if cmd.Flags().Changed("symbol") {
+			filterSymbol = symbol
+		}
+
+		options, err := getPositionOpts(filterSymbol, &positionGetVariables)
+		if err != nil {
+			logger.Error("Invalid filter.", zap.Error(err))
+			return
+		}
+
+		positions, rsp, err := client.Position.PositionGet(
+			authCtx, options)
+		if err != nil {
+			common.PrintError("Get position failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		if len(positions) < 1 {
+			logger.Warn("No positions found.")
+			return
+		}
+
+		printPositions(positions...)
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionGetCmd)
+
+	positionGetCmd.Flags().StringVar(
+		&positionGetVariables.filter, "filter", "",
+		"Filter string applied in query result")
+	positionGetCmd.Flags().StringVar(
+		&positionGetVariables.columns, "columns", "",
+		"Column names for query result.")
+	positionGetCmd.Flags().IntVarP(
+		&positionGetVariables.count, "count", "c", 0,
+		"Result count limit, 0 means all positions.")
+}
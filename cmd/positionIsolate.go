@@ -0,0 +1,70 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var positionCross bool
+
+// positionIsolateCmd represents the positionIsolate command
+var positionIsolateCmd = &cobra.Command{
+	Use:   "isolate",
+	Short: "Enable isolated margin for position.",
+	Long: `Enable isolated margin for position in --symbol,
+or switch back to cross margin with --cross.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		pos, rsp, err := client.Position.PositionIsolateMargin(
+			authCtx, symbol, &ngerest.PositionIsolateMarginOpts{
+				Enabled: optional.NewBool(!positionCross),
+			})
+		if err != nil {
+			common.PrintError("Isolate margin failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		printPositions(pos)
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionIsolateCmd)
+
+	positionIsolateCmd.Flags().BoolVar(
+		&positionCross, "cross", false, "Switch to cross margin.")
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+var positionLeverage float64
+
+// positionLeverageCmd represents the positionLeverage command
+var positionLeverageCmd = &cobra.Command{
+	Use:   "leverage leverage",
+	Short: "Update position leverage.",
+	Long: `Choose leverage for position in --symbol,
+leverage 0 means cross margin, otherwise position will be isolated.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		leverage, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return common.ErrLeverage
+		}
+
+		positionLeverage = leverage
+
+		return models.CheckLeverage(leverage)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		pos, rsp, err := client.Position.PositionUpdateLeverage(
+			authCtx, symbol, positionLeverage)
+		if err != nil {
+			common.PrintError("Update leverage failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		printPositions(pos)
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionLeverageCmd)
+}
@@ -0,0 +1,78 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var positionTransfer int64
+
+// positionMarginCmd represents the positionMargin command
+var positionMarginCmd = &cobra.Command{
+	Use:   "margin",
+	Short: "Transfer isolated margin.",
+	Long: `Transfer equity in or out of isolated margin position in --symbol,
+amount is in Satoshis, negative amount will remove margin from position.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if positionTransfer == 0 {
+			return common.ErrTransferAmount
+		}
+
+		// amount is sent in float32, which is exact only in 24 bits
+		if int64(float32(positionTransfer)) != positionTransfer {
+			return common.ErrTransferPrecision
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		pos, rsp, err := client.Position.PositionTransferIsolatedMargin(
+			authCtx, symbol, float32(positionTransfer))
+		if err != nil {
+			common.PrintError("Transfer margin failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		printPositions(pos)
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionMarginCmd)
+
+	positionMarginCmd.Flags().Int64Var(
+		&positionTransfer, "transfer", 0,
+		"Amount to transfer in Satoshis, negative to remove margin.")
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/spf13/cobra"
+)
+
+var positionRiskLimit int64
+
+// positionRiskLimitCmd represents the positionRiskLimit command
+var positionRiskLimitCmd = &cobra.Command{
+	Use:   "risk-limit limit",
+	Short: "Update position risk limit.",
+	Long:  `Update risk limit of position in --symbol, limit is in Satoshis.`,
+	Args:  cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		limit, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || limit <= 0 {
+			return common.ErrRiskLimit
+		}
+
+		positionRiskLimit = limit
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkLoginInfo()
+
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			common.PrintError("Load auth failed", err)
+			return
+		}
+
+		pos, rsp, err := client.Position.PositionUpdateRiskLimit(
+			authCtx, symbol, float32(positionRiskLimit))
+		if err != nil {
+			common.PrintError("Update risk limit failed",
+				common.NewAPIError("", err, rsp))
+			return
+		}
+
+		printPositions(pos)
+	},
+}
+
+func init() {
+	positionCmd.AddCommand(positionRiskLimitCmd)
+}
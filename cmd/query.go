@@ -0,0 +1,67 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultQueryCount = models.DefaultPageSize
+
+// timeFlagUsage usage of time flags, times without zone are in --tz
+const timeFlagUsage = `such as "2006-01-02T15:04:05+08:00", ` +
+	`"2006-01-02 15:04:05", "2006-01-02", epoch ms, "-2h", "-1d", ` +
+	`"today", "yesterday 09:00", times without zone are in --tz.`
+
+// queryArgs common args for table query
+type queryArgs struct {
+	filter  string
+	columns string
+	start   models.FlagTime
+	end     models.FlagTime
+	count   int
+	limit   int
+	reverse bool
+}
+
+func (args *queryArgs) newPager(
+	query models.PageQuery, key func(row interface{}) string) *models.Pager {
+	pager := models.Pager{
+		PageSize: args.count,
+		Limit:    args.limit,
+		Query:    query,
+		Key:      key,
+	}
+
+	return &pager
+}
+
+func bindQueryFlags(cmd *cobra.Command, args *queryArgs) {
+	cmd.Flags().StringVar(
+		&args.filter, "filter", "", "Filter string applied in query result")
+	cmd.Flags().StringVar(
+		&args.columns, "columns", "", "Column names for query result.")
+
+	cmd.Flags().BoolVarP(
+		&args.reverse, "reverse", "r", false, "Getting query results in reversed order.")
+
+	cmd.Flags().VarP(&args.start, "start", "s", "Start time, "+timeFlagUsage)
+	cmd.Flags().VarP(&args.end, "end", "e", "End time, "+timeFlagUsage)
+
+	cmd.Flags().IntVarP(&args.count, "count", "c", defaultQueryCount, "Result count in each page query.")
+	cmd.Flags().IntVar(&args.limit, "limit", 0, "Total result count limit, 0 means fetch all in time window.")
+}
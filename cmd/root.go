@@ -19,9 +19,12 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/frozenpine/ngecli/logger"
 
+	"go.uber.org/zap"
+
 	"github.com/frozenpine/ngecli/common"
 
 	"github.com/frozenpine/ngecli/models"
@@ -36,12 +39,16 @@ const (
 	defaultHost    = "trade"
 	defaultPort    = 80
 	defaultBaseURI = "/api/v1"
+	defaultWsURI   = "/realtime"
 
 	defaultSymbol = "XBTUSD"
+
+	environmentEnv = "NGECLI_ENV"
 )
 
 var (
 	cfgFile string
+	envName string
 
 	clientHub = &models.ClientHub{}
 
@@ -62,23 +69,72 @@ Supported:
 	2. trade
 	3. execution
 	4. position
-	5. all websocket interface`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
+	5. all websocket interface
+Run without command to enter interactive shell.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		logger.Use(commandLoggerName(cmd))
+
+		if err := models.SetTimeZone(viper.GetString("tz")); err != nil {
+			return common.NewError(common.KindValidation, "tz", err)
+		}
+
+		return checkOutputFormat()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("main run.")
+		if inShell {
+			cmd.Help()
+			return
+		}
+
+		runShell(cmd)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Exit code is mapped from the first error reported by commands,
+// so scripts can tell auth, validation, rate limit & rejection failures apart.
 func Execute() {
+	defer logger.Flush()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		logger.Flush()
+
+		// errors not classified are usage errors from cobra
+		if common.KindOf(err) == common.KindUnknown {
+			os.Exit(common.ExitUsage)
+		}
+
+		os.Exit(common.ExitCodeOf(err))
+	}
+
+	if code := common.ExitCode(); code != common.ExitOK {
+		logger.Flush()
+		os.Exit(code)
 	}
 }
 
+// commandAbort error aborting current command in shell
+type commandAbort struct {
+	err error
+}
+
+// exitWithError log error and exit with code mapped from error's kind,
+// in shell only current command is aborted and recovered by shell,
+// so an input mistake will not quit the whole shell.
+func exitWithError(err error, fields ...zap.Field) {
+	logger.Error(err.Error(), fields...)
+
+	if inShell {
+		common.SetExitError(err)
+		panic(commandAbort{err: err})
+	}
+
+	logger.Flush()
+	os.Exit(common.ExitCodeOf(err))
+}
+
 func init() {
 	cobra.OnInitialize(initConfig, printBanner)
 
@@ -106,6 +162,18 @@ func init() {
 		"uri", defaultBaseURI, "Base URI for NGE.")
 	viper.BindPFlag("base-uri", rootCmd.PersistentFlags().Lookup("uri"))
 
+	viper.SetDefault("ws-uri", defaultWsURI)
+	rootCmd.PersistentFlags().String(
+		"ws-uri", defaultWsURI, "Websocket URI for NGE.")
+	viper.BindPFlag("ws-uri", rootCmd.PersistentFlags().Lookup("ws-uri"))
+
+	// http transport settings, only configurable in config file
+	viper.SetDefault("connect-timeout", models.DefaultConnectTimeout)
+	viper.SetDefault("read-timeout", models.DefaultReadTimeout)
+	viper.SetDefault("max-retries", models.DefaultMaxRetries)
+	viper.SetDefault("retry-backoff", models.DefaultRetryBackoff)
+	viper.SetDefault("ratelimit-reserve", models.DefaultLimitReserve)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&auths.DefaultID, "id", "u", "", "Identity used for login.")
 	rootCmd.PersistentFlags().VarP(
@@ -113,9 +181,29 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(
 		&auths.CmdAuthFile, "auth", "", "Auth info for NGE.")
+	rootCmd.PersistentFlags().StringVar(
+		&auths.Profile, "profile", "",
+		"Profile in \"auths.yaml\" whose accounts are used in round robin.")
+
+	rootCmd.PersistentFlags().StringVar(
+		&envName, "env", "", "Named environment in config file, "+
+			"whose host & profile are used unless specified by flags, "+
+			"env "+environmentEnv+" is used if not specified.")
 
+	viper.SetDefault("symbol", defaultSymbol)
 	rootCmd.PersistentFlags().StringVar(
 		&symbol, "symbol", defaultSymbol, "Symbol name.")
+	viper.BindPFlag("symbol", rootCmd.PersistentFlags().Lookup("symbol"))
+
+	viper.SetDefault("tz", models.DefaultTimeZone)
+	rootCmd.PersistentFlags().String(
+		"tz", models.DefaultTimeZone, "Time zone used in parsing & "+
+			"showing times, IANA name like Asia/Shanghai, Local, "+
+			"or offset like +08:00.")
+	viper.BindPFlag("tz", rootCmd.PersistentFlags().Lookup("tz"))
+
+	bindOutputFlags(rootCmd)
+	bindLogFlags(rootCmd)
 
 	viper.SetDefault("verbose", 0)
 	rootCmd.PersistentFlags().CountVarP(
@@ -125,6 +213,11 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	// config is already loaded before shell started
+	if inShell {
+		return
+	}
+
 	// Find home directory.
 	home, err := homedir.Dir()
 	if err != nil {
@@ -145,26 +238,115 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-READ_CONFIG:
-	if err := viper.ReadInConfig(); err == nil {
+	switch err := viper.ReadInConfig(); {
+	case err == nil:
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
-	} else {
-		fmt.Println("No config file found, creating one...")
+	case isConfigMissing(err):
+		createConfig(home)
+	default:
+		// broken config file is kept, so it can be fixed by "config edit"
+		logger.Error("Read config file failed, using defaults.",
+			zap.Error(err))
+	}
 
-		confDir := path.Join(home, ".ngecli")
-		if _, err := os.Stat(confDir); os.IsNotExist(err) {
-			os.Mkdir(confDir, os.ModePerm)
+	if err := applyEnvironment(); err != nil {
+		exitWithError(err)
+	}
+
+	applySymbol()
+
+	initLogger()
+}
+
+// isConfigMissing check if config file read failed for not found
+func isConfigMissing(err error) bool {
+	_, notFound := err.(viper.ConfigFileNotFoundError)
+
+	return notFound || os.IsNotExist(err)
+}
+
+// createConfig write current settings to config file specified by flag,
+// or "config.yaml" in config dir if not specified.
+func createConfig(home string) {
+	fmt.Println("No config file found, creating one...")
+
+	confFile := cfgFile
+	if confFile == "" {
+		confFile = filepath.Join(home, ".ngecli", "config.yaml")
+	}
+
+	confDir := filepath.Dir(confFile)
+	if _, err := os.Stat(confDir); os.IsNotExist(err) {
+		os.MkdirAll(confDir, os.ModePerm)
+	}
+
+	if err := viper.WriteConfigAs(confFile); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	viper.SetConfigFile(confFile)
+}
+
+// applyEnvironment override host & profile settings by environment
+// selected by --env, NGECLI_ENV or "default-env" in config file,
+// settings specified by flags are kept.
+func applyEnvironment() error {
+	name := envName
+	if name == "" {
+		name = os.Getenv(environmentEnv)
+	}
+
+	env, err := models.GetEnvironment(viper.GetViper(), name)
+	if err != nil || env == nil {
+		return err
+	}
+
+	flags := rootCmd.PersistentFlags()
+
+	// settings are applied as flag values instead of viper overrides,
+	// so flags in command & "use host" in shell still take effect.
+	for key, value := range env.Settings() {
+		flagName := key
+		if key == "base-uri" {
+			flagName = "uri"
 		}
 
-		err = viper.WriteConfigAs(path.Join(confDir, "config.yaml"))
-		if err != nil {
-			logger.Fatal(err.Error())
+		flag := flags.Lookup(flagName)
+		if flag.Changed {
+			continue
 		}
 
-		goto READ_CONFIG
+		if err := flag.Value.Set(fmt.Sprint(value)); err != nil {
+			return common.NewError(common.KindValidation, key, err)
+		}
+
+		flag.Changed = true
+	}
+
+	if env.Profile != "" && auths.Profile == "" {
+		auths.Profile = env.Profile
+	}
+
+	fmt.Println("Using environment:", env.Name)
+
+	return nil
+}
+
+// applySymbol use symbol in config as default symbol,
+// so that symbol is kept after flags reset in shell
+func applySymbol() {
+	value := viper.GetString("symbol")
+
+	if flag := rootCmd.PersistentFlags().Lookup("symbol"); !flag.Changed {
+		flag.DefValue = value
+		flag.Value.Set(value)
 	}
 }
 
 func printBanner() {
+	if inShell {
+		return
+	}
+
 	fmt.Println("Default host:", common.GetBaseURL())
 }
@@ -0,0 +1,445 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/ngecli/shell"
+
+	"github.com/frozenpine/viper"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const shellUsage = `Shell commands:
+	use host [scheme://]host[:port]	Switch to host, login if not logged in.
+	use account [identity]		Use specified account in all commands,
+					empty identity means all accounts in round robin.
+	exit | quit			Exit shell.`
+
+var (
+	inShell bool
+
+	// shellRoot root command executed in shell
+	shellRoot *cobra.Command
+
+	// shellAuths logged in auth cache with host as key
+	shellAuths = make(map[string]*models.AuthCache)
+
+	// shellFlags root flags specified when shell started,
+	// which will be kept in all commands.
+	shellFlags map[*pflag.Flag]string
+
+	shellBuiltins = []string{"use", "exit", "quit"}
+)
+
+// resettable flag value which can be reset to empty
+type resettable interface {
+	Reset()
+}
+
+func snapshotFlags() map[*pflag.Flag]string {
+	snapshot := make(map[*pflag.Flag]string)
+
+	shellRoot.PersistentFlags().Visit(func(flag *pflag.Flag) {
+		// password is shadowed after set, and auth is already logged in
+		if flag.Value.Type() == "Password" {
+			return
+		}
+
+		snapshot[flag] = flag.Value.String()
+	})
+
+	return snapshot
+}
+
+// resetFlags reset all flags in command tree to default value,
+// so flag values will not be kept between commands.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if value, ok := flag.Value.(resettable); ok {
+			value.Reset()
+		} else if flag.Changed {
+			flag.Value.Set(flag.DefValue)
+		}
+
+		flag.Changed = false
+
+		if value, exist := shellFlags[flag]; exist {
+			flag.Value.Set(value)
+			flag.Changed = true
+		}
+	}
+
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+func resetCaches() {
+	orderCache.Close()
+
+	orderCache = models.NewOrderCache()
+	tradeCache = models.NewTradeCache()
+}
+
+// hostKeys viper keys of host, bound to root flags in the same name
+var hostKeys = []string{"scheme", "host", "port"}
+
+// setHostFlags set root host flags by values with viper key as key
+func setHostFlags(values map[string]string) bool {
+	for key, value := range values {
+		flag := shellRoot.PersistentFlags().Lookup(key)
+
+		if err := flag.Value.Set(value); err != nil {
+			logger.Warn(err.Error())
+			return false
+		}
+
+		flag.Changed = true
+	}
+
+	return true
+}
+
+// parseHostFlags parse host string: [scheme://]host[:port] as flag values
+func parseHostFlags(host string) (map[string]string, bool) {
+	values := make(map[string]string)
+
+	parts := strings.Split(host, "://")
+	if len(parts) > 1 && strings.Contains(parts[0], "http") {
+		values["scheme"] = parts[0]
+	}
+
+	hostParts := strings.Split(parts[len(parts)-1], ":")
+	values["host"] = hostParts[0]
+
+	if len(hostParts) > 1 {
+		if _, err := strconv.Atoi(hostParts[1]); err != nil {
+			logger.Warn("Invalid host:", zap.String("url", host))
+			return nil, false
+		}
+
+		values["port"] = hostParts[1]
+	}
+
+	return values, true
+}
+
+// pinHost keep current host in root flags snapshot, so it's restored
+// before each command, and still can be overridden by command flags.
+func pinHost() {
+	values := make(map[string]string)
+
+	for _, key := range hostKeys {
+		values[key] = viper.GetString(key)
+	}
+
+	setHostFlags(values)
+
+	for _, key := range hostKeys {
+		shellFlags[shellRoot.PersistentFlags().Lookup(key)] = values[key]
+	}
+}
+
+// recoverAbort recover command aborted by exitWithError,
+// other panics are raised again.
+func recoverAbort() {
+	if r := recover(); r != nil {
+		if _, ok := r.(commandAbort); !ok {
+			panic(r)
+		}
+	}
+}
+
+func useHost(host string) {
+	prevHost := make(map[string]string)
+	for _, key := range hostKeys {
+		prevHost[key] = viper.GetString(key)
+	}
+
+	values, ok := parseHostFlags(host)
+	if !ok || !setHostFlags(values) {
+		setHostFlags(prevHost)
+		return
+	}
+
+	baseHost := common.GetBaseHost()
+
+	if cache, exist := shellAuths[baseHost]; exist {
+		auths = cache
+		pinHost()
+		return
+	}
+
+	prevAuths := auths
+	auths = newAuthCache()
+
+	var loaded bool
+
+	// previous host is restored if login failed or aborted
+	defer func() {
+		if !loaded {
+			auths = prevAuths
+			setHostFlags(prevHost)
+		}
+	}()
+
+	checkLoginInfo()
+
+	if err := auths.Load(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	loaded = true
+
+	shellAuths[baseHost] = auths
+	pinHost()
+}
+
+func shellUse(args []string) {
+	if len(args) < 1 {
+		fmt.Println(shellUsage)
+		return
+	}
+
+	switch args[0] {
+	case "host":
+		if len(args) < 2 {
+			fmt.Println(shellUsage)
+			return
+		}
+
+		useHost(args[1])
+	case "account":
+		var id string
+
+		if len(args) > 1 {
+			id = args[1]
+		}
+
+		if err := auths.UseAuth(id); err != nil {
+			fmt.Println(err)
+		}
+	default:
+		fmt.Println(shellUsage)
+	}
+}
+
+func executeShell(args []string) {
+	defer recoverAbort()
+
+	if args[0] == "use" {
+		shellUse(args[1:])
+		return
+	}
+
+	resetFlags(shellRoot)
+	resetCaches()
+	common.ResetExitError()
+
+	shellRoot.SetArgs(args)
+
+	// command flags are reset even if command aborted,
+	// so prompt shows the pinned host
+	defer resetFlags(shellRoot)
+
+	// error is already printed by cobra
+	shellRoot.Execute()
+
+	if len(args) == 1 && args[0] == "help" {
+		fmt.Println()
+		fmt.Println(shellUsage)
+	}
+}
+
+func shellPrompt() string {
+	if id := auths.CurrentID(); id != "" {
+		return fmt.Sprintf("ngecli(%s@%s)> ", id, common.GetBaseHost())
+	}
+
+	return fmt.Sprintf("ngecli(%s)> ", common.GetBaseHost())
+}
+
+func completeUse(args []string) []string {
+	if len(args) < 1 {
+		return []string{"host", "account"}
+	}
+
+	if len(args) > 1 {
+		return nil
+	}
+
+	switch args[0] {
+	case "host":
+		hosts := make([]string, 0, len(shellAuths))
+
+		for host := range shellAuths {
+			hosts = append(hosts, host)
+		}
+
+		sort.Strings(hosts)
+
+		return hosts
+	case "account":
+		ids, _ := auths.AllAuthIDs()
+
+		return ids
+	}
+
+	return nil
+}
+
+func completeFlags(cmd *cobra.Command) []string {
+	var flags []string
+
+	collect := func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			flags = append(flags, "--"+flag.Name)
+		}
+	}
+
+	cmd.LocalFlags().VisitAll(collect)
+	cmd.InheritedFlags().VisitAll(collect)
+
+	return flags
+}
+
+// completeShell complete last word in line with command tree
+func completeShell(line string) []string {
+	words := strings.Fields(line)
+
+	var current string
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	if len(words) > 0 && words[0] == "use" {
+		return completeUse(words[1:])
+	}
+
+	cmd := shellRoot
+
+	for _, word := range words {
+		for _, sub := range cmd.Commands() {
+			if sub.Name() == word || sub.HasAlias(word) {
+				cmd = sub
+				break
+			}
+		}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		return completeFlags(cmd)
+	}
+
+	var candidates []string
+
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() || sub.Name() == "help" {
+			candidates = append(candidates, sub.Name())
+		}
+	}
+
+	if cmd == shellRoot && len(words) < 1 {
+		candidates = append(candidates, shellBuiltins...)
+	}
+
+	return candidates
+}
+
+func runShell(root *cobra.Command) {
+	shellRoot = root
+
+	// shell exits if login failed before it started
+	checkLoginInfo()
+
+	if err := auths.Load(); err != nil {
+		exitWithError(err)
+	}
+
+	inShell = true
+
+	shellAuths[common.GetBaseHost()] = auths
+	shellFlags = snapshotFlags()
+	pinHost()
+
+	var histPath string
+	if home, err := homedir.Dir(); err == nil {
+		histPath = filepath.Join(home, ".ngecli", "history")
+	}
+
+	history := shell.NewHistory(histPath, shell.DefaultHistorySize)
+
+	reader := shell.NewReader(history)
+	reader.Completer = completeShell
+
+	fmt.Println(`Type "help" for available commands, "exit" to quit.`)
+
+	for {
+		line, err := reader.ReadLine(shellPrompt())
+
+		switch err {
+		case nil:
+		case common.ErrInterrupt:
+			continue
+		case io.EOF:
+			return
+		default:
+			logger.Error(err.Error())
+			return
+		}
+
+		args, err := shell.SplitArgs(line)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		if len(args) < 1 {
+			continue
+		}
+
+		if err := history.Add(line); err != nil {
+			logger.Warn(err.Error())
+		}
+
+		if args[0] == "exit" || args[0] == "quit" {
+			return
+		}
+
+		executeShell(args)
+	}
+}
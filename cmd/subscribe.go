@@ -0,0 +1,186 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/frozenpine/ngecli/ws"
+
+	"github.com/spf13/cobra"
+)
+
+type subscribeArgs struct {
+	heartbeat time.Duration
+	timeout   time.Duration
+	limit     int
+}
+
+var subscribeVariables subscribeArgs
+
+func newWsConfig(topics []string) (*ws.Config, error) {
+	cfg := ws.Config{
+		URL:       common.GetWsURL(),
+		Heartbeat: subscribeVariables.heartbeat,
+		Timeout:   subscribeVariables.timeout,
+	}
+
+	for _, topic := range topics {
+		if !ws.IsPrivate(topic) {
+			continue
+		}
+
+		checkLoginInfo()
+
+		authCtx, err := auths.NextAuth(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if authCtx == nil {
+			return nil, common.ErrWsAuth
+		}
+
+		key := authCtx.Value(ngerest.ContextAPIKey).(ngerest.APIKey)
+		cfg.Key, cfg.Secret = key.Key, key.Secret
+
+		break
+	}
+
+	return &cfg, nil
+}
+
+func printWsMessages(client *ws.Client, limit int) int {
+	var count int
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	formatters := make(map[string]models.Formatter)
+
+	for {
+		select {
+		case <-interrupt:
+			return count
+		case <-rootCtx.Done():
+			return count
+		case msg, ok := <-client.Messages():
+			if !ok {
+				return count
+			}
+
+			formatter, exist := formatters[msg.Table]
+			if !exist {
+				formatter = newFormatter()
+				formatters[msg.Table] = formatter
+			}
+
+			for _, row := range msg.Rows {
+				if err := formatter.Format(row); err != nil {
+					logger.Warn(err.Error())
+					continue
+				}
+
+				count++
+
+				if limit > 0 && count >= limit {
+					flushFormatter(formatter)
+					return count
+				}
+			}
+
+			flushFormatter(formatter)
+		}
+	}
+}
+
+// subscribeCmd represents the subscribe command
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe topic [topic...]",
+	Short: "Subscribe realtime tables.",
+	Long: `Subscribe realtime tables through websocket and print table rows.
+
+Topic format is: table[:symbol], available tables:
+  public:  trade, orderBookL2, quote, instrument
+  private: order, execution, position
+
+Connection will be kept alive with heartbeat, and topics will be
+resubscribed automatically after reconnected.`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		for _, topic := range args {
+			if _, _, err := ws.ParseTopic(topic); err != nil {
+				return err
+			}
+		}
+
+		if subscribeVariables.limit < 0 {
+			return common.ErrArgs
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := newWsConfig(args)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+
+		client := ws.NewClient(*cfg)
+		defer client.Close()
+
+		if err = client.Connect(); err != nil {
+			common.PrintError("Connect websocket failed", err)
+			return
+		}
+
+		if err = client.Subscribe(args...); err != nil {
+			common.PrintError("Subscribe failed", err)
+			return
+		}
+
+		count := printWsMessages(client, subscribeVariables.limit)
+
+		logger.Info("Subscription finished.", zap.Int("count", count))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(subscribeCmd)
+
+	subscribeCmd.Flags().DurationVar(
+		&subscribeVariables.heartbeat, "heartbeat", ws.DefaultHeartbeat,
+		"Idle duration before sending heartbeat ping.")
+	subscribeCmd.Flags().DurationVar(
+		&subscribeVariables.timeout, "timeout", ws.DefaultTimeout,
+		"Timeout for handshake and heartbeat pong.")
+	subscribeCmd.Flags().IntVar(
+		&subscribeVariables.limit, "limit", 0,
+		"Exit after number of rows printed, 0 means unlimited.")
+}
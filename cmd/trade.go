@@ -17,19 +17,18 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/frozenpine/ngecli/models"
+
 	"github.com/spf13/cobra"
 )
 
+var tradeCache *models.TradeCache
+
 // tradeCmd represents the trade command
 var tradeCmd = &cobra.Command{
 	Use:   "trade",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Short: "trade functions",
+	Long:  `All functions for Trade table.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("trade called")
 	},
@@ -38,13 +37,5 @@ to quickly create a Cobra application.`,
 func init() {
 	rootCmd.AddCommand(tradeCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// tradeCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// tradeCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	tradeCache = models.NewTradeCache()
 }
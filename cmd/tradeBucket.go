@@ -0,0 +1,159 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultBinSize = "1m"
+
+type tradeBucketArgs struct {
+	queryArgs
+
+	binSize string
+	partial bool
+}
+
+var tradeBucketVariables tradeBucketArgs
+
+func getTradeBucketOpts(
+	symbol string, args *tradeBucketArgs) *ngerest.TradeGetBucketedOpts {
+	tradeOpts := getTradeOpts(symbol, &args.queryArgs)
+
+	options := ngerest.TradeGetBucketedOpts{
+		BinSize:   optional.NewString(args.binSize),
+		Symbol:    tradeOpts.Symbol,
+		Filter:    tradeOpts.Filter,
+		Columns:   tradeOpts.Columns,
+		Count:     tradeOpts.Count,
+		Reverse:   tradeOpts.Reverse,
+		StartTime: tradeOpts.StartTime,
+		EndTime:   tradeOpts.EndTime,
+	}
+
+	if args.partial {
+		options.Partial = optional.NewBool(args.partial)
+	}
+
+	return &options
+}
+
+func printBucketResults(wait *sync.WaitGroup, results <-chan *models.TradeBin) {
+	var count int
+
+	formatter := newFormatter()
+
+	for bin := range results {
+		if err := formatter.Format(bin); err != nil {
+			logger.Warn(err.Error())
+		} else {
+			count++
+		}
+	}
+
+	flushFormatter(formatter)
+
+	logger.Info("All bucket results printed.", zap.Int("count", count))
+	wait.Done()
+}
+
+// tradeBucketCmd represents the tradeBucket command
+var tradeBucketCmd = &cobra.Command{
+	Use:   "bucket",
+	Short: "Get history trades in candles.",
+	Long:  `Get history trades bucketed by bin size in time window.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !models.CheckBinSize(tradeBucketVariables.binSize) {
+			return common.ErrBinSize
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Warn(err.Error())
+			return
+		}
+
+		query := func(start, count int) ([]interface{}, error) {
+			options := getTradeBucketOpts(symbol, &tradeBucketVariables)
+			options.Start = optional.NewFloat32(float32(start))
+			options.Count = optional.NewFloat32(float32(count))
+
+			bins, rsp, err := client.Trade.TradeGetBucketed(rootCtx, options)
+
+			rows := make([]interface{}, len(bins))
+			for idx := range bins {
+				rows[idx] = &bins[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		}
+
+		pager := tradeBucketVariables.newPager(query, func(row interface{}) string {
+			bin := row.(*ngerest.TradeBin)
+
+			return bin.Symbol + bin.Timestamp.String()
+		})
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printBucketResults(&waitOutput, tradeCache.GetBuckets())
+
+		total, err := pager.Fetch(func(row interface{}) {
+			tradeCache.PutBucket(row.(*ngerest.TradeBin))
+		})
+
+		tradeCache.CloseBuckets()
+
+		waitOutput.Wait()
+
+		if err != nil {
+			common.PrintError("Get trade bucket failed", err)
+		} else if total < 1 {
+			logger.Warn("No trade bucket found.")
+		}
+	},
+}
+
+func init() {
+	tradeCmd.AddCommand(tradeBucketCmd)
+
+	bindQueryFlags(tradeBucketCmd, &tradeBucketVariables.queryArgs)
+
+	tradeBucketCmd.Flags().StringVar(
+		&tradeBucketVariables.binSize, "bin", defaultBinSize,
+		"Bin size for trade bucket: "+strings.Join(models.TradeBinSize, "|"))
+	tradeBucketCmd.Flags().BoolVar(
+		&tradeBucketVariables.partial, "partial", false,
+		"Include current partial bin.")
+}
@@ -15,36 +15,131 @@
 package cmd
 
 import (
-	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
 
 	"github.com/spf13/cobra"
 )
 
+var tradeGetVariables queryArgs
+
+func getTradeOpts(symbol string, args *queryArgs) *ngerest.TradeGetOpts {
+	options := ngerest.TradeGetOpts{}
+
+	if symbol != "" {
+		options.Symbol = optional.NewString(symbol)
+	}
+
+	if args.filter != "" {
+		options.Filter = optional.NewString(args.filter)
+	}
+
+	if args.columns != "" {
+		options.Columns = optional.NewString(args.columns)
+	}
+
+	if args.reverse {
+		options.Reverse = optional.NewBool(args.reverse)
+	}
+
+	if args.start.IsSet() {
+		options.StartTime = optional.NewTime(args.start.GetTime())
+	}
+
+	if args.end.IsSet() {
+		options.EndTime = optional.NewTime(args.end.GetTime())
+	}
+
+	if args.count > 0 {
+		options.Count = optional.NewFloat32(float32(args.count))
+	}
+
+	return &options
+}
+
+func printTradeResults(wait *sync.WaitGroup, results <-chan *models.Trade) {
+	var count int
+
+	formatter := newFormatter()
+
+	for td := range results {
+		if err := formatter.Format(td); err != nil {
+			logger.Warn(err.Error())
+		} else {
+			count++
+		}
+	}
+
+	flushFormatter(formatter)
+
+	logger.Info("All trade results printed.", zap.Int("count", count))
+	wait.Done()
+}
+
 // tradeGetCmd represents the tradeGet command
 var tradeGetCmd = &cobra.Command{
 	Use:   "get",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Short: "Get history trades.",
+	Long:  `Get history trades in symbol with time window.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("tradeGet called")
+		client, err := clientHub.GetClient(common.GetBaseHost())
+		if err != nil {
+			logger.Warn(err.Error())
+			return
+		}
+
+		query := func(start, count int) ([]interface{}, error) {
+			options := getTradeOpts(symbol, &tradeGetVariables)
+			options.Start = optional.NewFloat32(float32(start))
+			options.Count = optional.NewFloat32(float32(count))
+
+			hisTrades, rsp, err := client.Trade.TradeGet(rootCtx, options)
+
+			rows := make([]interface{}, len(hisTrades))
+			for idx := range hisTrades {
+				rows[idx] = &hisTrades[idx]
+			}
+
+			return rows, common.NewAPIError("", err, rsp)
+		}
+
+		pager := tradeGetVariables.newPager(query, func(row interface{}) string {
+			return row.(*ngerest.Trade).TrdMatchID
+		})
+
+		waitOutput := sync.WaitGroup{}
+		waitOutput.Add(1)
+
+		go printTradeResults(&waitOutput, tradeCache.GetResults())
+
+		total, err := pager.Fetch(func(row interface{}) {
+			tradeCache.PutResult(row.(*ngerest.Trade))
+		})
+
+		tradeCache.CloseResults()
+
+		waitOutput.Wait()
+
+		if err != nil {
+			common.PrintError("Get trade failed", err)
+		} else if total < 1 {
+			logger.Warn("No history trades found.")
+		}
 	},
 }
 
 func init() {
 	tradeCmd.AddCommand(tradeGetCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// tradeGetCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// tradeGetCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	bindQueryFlags(tradeGetCmd, &tradeGetVariables)
 }
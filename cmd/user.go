@@ -0,0 +1,123 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultCurrency = "XBt"
+
+var userCurrency string
+
+// accountQuery query result rows of an account
+type accountQuery func(client *ngerest.APIClient,
+	id string, authCtx context.Context) ([]interface{}, error)
+
+// queryAccounts query all accounts in auth info and output all rows in one
+// formatter, so results can be aggregated in one table with identity column.
+func queryAccounts(action string, query accountQuery) {
+	checkLoginInfo()
+
+	client, err := clientHub.GetClient(common.GetBaseHost())
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	ids, err := auths.AuthIDs()
+	if err != nil {
+		common.PrintError("Load auth failed", err)
+		return
+	}
+
+	var total int
+
+	formatter := newFormatter()
+
+	for _, id := range ids {
+		rows, err := query(client, id, auths.GetAuthContext(nil, id))
+		if err != nil {
+			common.PrintError(fmt.Sprintf("%s failed[%s]", action, id), err)
+			continue
+		}
+
+		for _, row := range rows {
+			if err := formatter.Format(row); err != nil {
+				logger.Warn(err.Error())
+				continue
+			}
+
+			total++
+		}
+	}
+
+	flushFormatter(formatter)
+
+	if total < 1 {
+		logger.Warn("No results found.", zap.String("action", action))
+	}
+}
+
+// convertAccountRows convert ngerest account models to local rows
+func convertAccountRows(id string, count int,
+	convert func(idx int) (ori interface{}, converted interface{})) []interface{} {
+	rows := make([]interface{}, 0, count)
+
+	for idx := 0; idx < count; idx++ {
+		ori, converted := convert(idx)
+
+		if err := models.ConvertAccountModel(id, ori, converted); err != nil {
+			logger.Warn(err.Error())
+			continue
+		}
+
+		rows = append(rows, converted)
+	}
+
+	return rows
+}
+
+// userCmd represents the user command
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "user functions",
+	Long: `All functions for User & Wallet table.
+Results of all accounts in auth info will be output together,
+use "--output table" to get an aggregated table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("user called")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(userCmd)
+
+	userCmd.PersistentFlags().StringVar(
+		&userCurrency, "currency", defaultCurrency, "Currency for margin & wallet.")
+}
@@ -0,0 +1,51 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// userAffiliateCmd represents the userAffiliate command
+var userAffiliateCmd = &cobra.Command{
+	Use:   "affiliate",
+	Short: "Get affiliate status.",
+	Long:  `Get affiliate status of all accounts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		queryAccounts("Get affiliate status", func(client *ngerest.APIClient,
+			id string, authCtx context.Context) ([]interface{}, error) {
+			affiliate, rsp, err := client.User.UserGetAffiliateStatus(authCtx)
+			if err != nil {
+				return nil, common.NewAPIError("", err, rsp)
+			}
+
+			return convertAccountRows(id, 1, func(int) (interface{}, interface{}) {
+				return &affiliate, &models.Affiliate{}
+			}), nil
+		})
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userAffiliateCmd)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+type userWalletHistoryArgs struct {
+	count int
+	limit int
+}
+
+var userWalletHistoryVariables userWalletHistoryArgs
+
+// userWalletHistoryCmd represents the userWalletHistory command
+var userWalletHistoryCmd = &cobra.Command{
+	Use:   "wallet-history",
+	Short: "Get wallet history.",
+	Long: `Get wallet transactions in --currency of all accounts,
+including deposits, withdrawals & PNL, fetched page by page.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if userWalletHistoryVariables.count < 1 ||
+			userWalletHistoryVariables.limit < 0 {
+			return common.ErrArgs
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		queryAccounts("Get wallet history", func(client *ngerest.APIClient,
+			id string, authCtx context.Context) ([]interface{}, error) {
+			pager := models.Pager{
+				PageSize: userWalletHistoryVariables.count,
+				Limit:    userWalletHistoryVariables.limit,
+				Query: func(start, count int) ([]interface{}, error) {
+					history, err := models.GetWalletHistory(
+						authCtx, common.GetFullPath(), userCurrency, start, count)
+
+					return convertAccountRows(id, len(history),
+						func(idx int) (interface{}, interface{}) {
+							return &history[idx], &models.Transaction{}
+						}), err
+				},
+				Key: func(row interface{}) string {
+					return row.(*models.Transaction).TransactID
+				},
+			}
+
+			var rows []interface{}
+
+			_, err := pager.Fetch(func(row interface{}) {
+				rows = append(rows, row)
+			})
+
+			return rows, err
+		})
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userWalletHistoryCmd)
+
+	userWalletHistoryCmd.Flags().IntVarP(
+		&userWalletHistoryVariables.count, "count", "c", defaultQueryCount,
+		"Result count in each page query.")
+	userWalletHistoryCmd.Flags().IntVar(
+		&userWalletHistoryVariables.limit, "limit", 0,
+		"Total result count limit for each account, 0 means fetch all.")
+}
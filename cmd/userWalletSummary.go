@@ -0,0 +1,56 @@
+// Copyright © 2019 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngecli/models"
+
+	"github.com/spf13/cobra"
+)
+
+// userWalletSummaryCmd represents the userWalletSummary command
+var userWalletSummaryCmd = &cobra.Command{
+	Use:   "wallet-summary",
+	Short: "Get wallet summary.",
+	Long:  `Get summary of wallet transactions in --currency of all accounts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		queryAccounts("Get wallet summary", func(client *ngerest.APIClient,
+			id string, authCtx context.Context) ([]interface{}, error) {
+			summary, rsp, err := client.User.UserGetWalletSummary(
+				authCtx, &ngerest.UserGetWalletSummaryOpts{
+					Currency: optional.NewString(userCurrency),
+				})
+			if err != nil {
+				return nil, common.NewAPIError("", err, rsp)
+			}
+
+			return convertAccountRows(id, len(summary),
+				func(idx int) (interface{}, interface{}) {
+					return &summary[idx], &models.Transaction{}
+				}), nil
+		})
+	},
+}
+
+func init() {
+	userCmd.AddCommand(userWalletSummaryCmd)
+}
@@ -0,0 +1,334 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// ErrorKind category of errors, each kind is mapped to a distinct
+// exit code, so scripts can tell failures apart.
+type ErrorKind int
+
+const (
+	// KindUnknown errors not classified, such as network failure
+	KindUnknown ErrorKind = iota
+	// KindAuth auth info missing, login failed or rejected by 401/403
+	KindAuth
+	// KindValidation invalid args checked before request sent
+	KindValidation
+	// KindRateLimit rate limited locally or by server with 429
+	KindRateLimit
+	// KindRejected request rejected by server
+	KindRejected
+)
+
+// Exit codes for error kinds
+const (
+	ExitOK         = 0
+	ExitUnknown    = 1
+	ExitUsage      = 2
+	ExitAuth       = 3
+	ExitValidation = 4
+	ExitRateLimit  = 5
+	ExitRejected   = 6
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindAuth:
+		return "auth"
+	case KindValidation:
+		return "validation"
+	case KindRateLimit:
+		return "ratelimit"
+	case KindRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// ExitCode get exit code of error kind
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case KindAuth:
+		return ExitAuth
+	case KindValidation:
+		return ExitValidation
+	case KindRateLimit:
+		return ExitRateLimit
+	case KindRejected:
+		return ExitRejected
+	default:
+		return ExitUnknown
+	}
+}
+
+// kindOfErrors kinds of predefined errors
+var kindOfErrors = map[error]ErrorKind{
+	ErrIdentity:     KindAuth,
+	ErrAuthMissing:  KindAuth,
+	ErrAuthNotFound: KindAuth,
+	ErrAuthRecord:   KindAuth,
+	ErrWsAuth:       KindAuth,
+	ErrAccount:      KindAuth,
+	ErrPassphrase:   KindAuth,
+	ErrRelogin:      KindAuth,
+	ErrNoSession:    KindAuth,
+
+	ErrCount:            KindValidation,
+	ErrSymbol:           KindValidation,
+	ErrPrice:            KindValidation,
+	ErrQuantity:         KindValidation,
+	ErrTick:             KindValidation,
+	ErrVolumeRange:      KindValidation,
+	ErrMissMatchQtySide: KindValidation,
+	ErrOrder:            KindValidation,
+	ErrCancelTarget:     KindValidation,
+	ErrAmendTarget:      KindValidation,
+	ErrAmendNothing:     KindValidation,
+	ErrOrderOwner:       KindValidation,
+	ErrArgs:             KindValidation,
+	ErrBinSize:          KindValidation,
+	ErrSide:             KindValidation,
+	ErrHost:             KindValidation,
+	ErrSourceFormat:     KindValidation,
+	ErrOutputFormat:     KindValidation,
+	ErrOutputField:      KindValidation,
+	ErrWsTopic:          KindValidation,
+	ErrWsAction:         KindValidation,
+	ErrLeverage:         KindValidation,
+	ErrRiskLimit:        KindValidation,
+	ErrTransferAmount:   KindValidation,
+	ErrConfigKey:        KindValidation,
+	ErrConfigValue:      KindValidation,
+	ErrScheme:           KindValidation,
+	ErrPort:             KindValidation,
+	ErrURI:              KindValidation,
+	ErrEnvNotFound:      KindValidation,
+	ErrTimeFormat:       KindValidation,
+	ErrTimeZone:         KindValidation,
+
+	ErrTransferPrecision: KindValidation,
+
+	ErrOrderRate: KindValidation,
+
+	ErrInstrumentNotFound: KindValidation,
+	ErrInstrumentState:    KindValidation,
+	ErrPriceTick:          KindValidation,
+	ErrMaxPrice:           KindValidation,
+	ErrLotSize:            KindValidation,
+	ErrMaxOrderQty:        KindValidation,
+
+	ErrAmendResult:   KindRejected,
+	ErrOrderRejected: KindRejected,
+
+	ErrInflightCheck:     KindRateLimit,
+	ErrTokenInsufficient: KindRateLimit,
+}
+
+// Error error with kind & operation context, original error is wrapped
+type Error struct {
+	Kind ErrorKind
+	Op   string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+
+	return e.Op + ": " + e.Err.Error()
+}
+
+// Unwrap get wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError wrap error with kind & operation context,
+// kind of wrapped error will be used if kind is KindUnknown.
+func NewError(kind ErrorKind, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Kind: kind, Op: op, Err: err}
+}
+
+// APIError error returned from api request with http status
+// and error name & message parsed from server's error model.
+type APIError struct {
+	Op      string
+	Method  string
+	Path    string
+	Status  int
+	Name    string
+	Message string
+	Body    []byte
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	msg := e.Err.Error()
+
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s %s: %s",
+			http.StatusText(e.Status), e.Name, e.Message)
+	}
+
+	if e.Method != "" {
+		msg = fmt.Sprintf("%s %s %s", e.Method, e.Path, msg)
+	}
+
+	if e.Op == "" {
+		return msg
+	}
+
+	return e.Op + ": " + msg
+}
+
+// Unwrap get wrapped error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Kind get error kind by http status
+func (e *APIError) Kind() ErrorKind {
+	switch {
+	case e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden:
+		return KindAuth
+	case e.Status == http.StatusTooManyRequests:
+		return KindRateLimit
+	case e.Status >= http.StatusBadRequest:
+		return KindRejected
+	default:
+		return KindUnknown
+	}
+}
+
+// NewAPIError wrap error returned from ngerest call with operation context,
+// http status & request from response, error body is parsed as
+// ngerest.ModelError, errors without response are wrapped as Error.
+func NewAPIError(op string, err error, rsp *http.Response) error {
+	if err == nil {
+		return nil
+	}
+
+	if rsp == nil {
+		return NewError(KindUnknown, op, err)
+	}
+
+	var body []byte
+
+	if swErr, ok := err.(ngerest.GenericSwaggerError); ok {
+		body = swErr.Body()
+	}
+
+	return newAPIError(op, err, rsp, body)
+}
+
+// NewResponseError make error from failed response of direct request,
+// response body should be read by caller.
+func NewResponseError(op string, rsp *http.Response, body []byte) error {
+	return newAPIError(op, errors.New(rsp.Status), rsp, body)
+}
+
+func newAPIError(op string, err error, rsp *http.Response, body []byte) *APIError {
+	apiErr := APIError{Op: op, Status: rsp.StatusCode, Body: body, Err: err}
+
+	if rsp.Request != nil {
+		apiErr.Method = rsp.Request.Method
+		apiErr.Path = rsp.Request.URL.Path
+	}
+
+	apiErr.parseBody()
+
+	return &apiErr
+}
+
+func (e *APIError) parseBody() {
+	model := ngerest.ModelError{}
+
+	if err := json.Unmarshal(e.Body, &model); err != nil || model.Error == nil {
+		return
+	}
+
+	e.Name = model.Error.Name
+	e.Message = strings.TrimSpace(model.Error.Message)
+}
+
+// KindOf get error's kind, wrapped errors are checked recursively,
+// other errors are compared with predefined errors one by one,
+// as they may be unhashable as map key, such as GenericSwaggerError.
+func KindOf(err error) ErrorKind {
+	switch e := err.(type) {
+	case nil:
+		return KindUnknown
+	case *APIError:
+		return e.Kind()
+	case *Error:
+		if e.Kind != KindUnknown {
+			return e.Kind
+		}
+
+		return KindOf(e.Err)
+	}
+
+	for predefined, kind := range kindOfErrors {
+		if err == predefined {
+			return kind
+		}
+	}
+
+	return KindUnknown
+}
+
+// ExitCodeOf get exit code of error, ExitOK for nil error
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	return KindOf(err).ExitCode()
+}
+
+// exitError first error reported in command, used as exit code
+var exitError struct {
+	err  error
+	lock sync.Mutex
+}
+
+// SetExitError report error failing the command, only the first
+// reported error is kept to decide exit code.
+func SetExitError(err error) {
+	exitError.lock.Lock()
+	defer exitError.lock.Unlock()
+
+	if exitError.err == nil {
+		exitError.err = err
+	}
+}
+
+// ResetExitError clear reported error before running a new command
+func ResetExitError() {
+	exitError.lock.Lock()
+	defer exitError.lock.Unlock()
+
+	exitError.err = nil
+}
+
+// ExitCode get exit code of the first reported error
+func ExitCode() int {
+	exitError.lock.Lock()
+	defer exitError.lock.Unlock()
+
+	return ExitCodeOf(exitError.err)
+}
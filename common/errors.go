@@ -17,9 +17,43 @@ var (
 	// ErrQuantity invalid quantity
 	ErrQuantity = errors.New("order quantity can't be ZERO")
 
+	// ErrTick invalid price tick
+	ErrTick = errors.New("price tick should be positive")
+
+	// ErrVolumeRange invalid random volume range
+	ErrVolumeRange = errors.New("max volume can't be less than base volume")
+
 	// ErrMissMatchQtySide quantity miss-match with side
 	ErrMissMatchQtySide = errors.New("order quantity miss-match with side")
 
+	// ErrOrder invalid order
+	ErrOrder = errors.New("invalid order")
+
+	// ErrCancelTarget no cancel target specified
+	ErrCancelTarget = errors.New("cancel target missing, " +
+		"either orderID, clOrdID, all or after should be specified")
+
+	// ErrAmendTarget no amend target specified
+	ErrAmendTarget = errors.New("either orderID or origClOrdID should be specified")
+
+	// ErrAmendNothing nothing to be amended
+	ErrAmendNothing = errors.New("nothing to be amended")
+
+	// ErrOrderOwner order not found in any account
+	ErrOrderOwner = errors.New("order not found in any account")
+
+	// ErrAmendResult no result returned for amendment
+	ErrAmendResult = errors.New("no result returned for amendment")
+
+	// ErrOrderRejected order rejected in result
+	ErrOrderRejected = errors.New("order rejected")
+
+	// ErrArgs invalid command args
+	ErrArgs = errors.New("variables check failed")
+
+	// ErrBinSize invalid trade bucket bin size
+	ErrBinSize = errors.New("bin size should be one of: 1m, 5m, 1h, 1d")
+
 	// ErrSide invalid side
 	ErrSide = errors.New("side is either \"Buy\" or \"Sell\"")
 
@@ -29,12 +63,148 @@ var (
 	// ErrAuthMissing no auth info found
 	ErrAuthMissing = errors.New("no auth info found either in cli args or \"auths.yaml\"")
 
+	// ErrAuthRecord invalid record in auth file
+	ErrAuthRecord = errors.New("invalid auth record, identity with " +
+		"password or api key with secret should be specified")
+
+	// ErrAuthNotFound auth identity not found in loaded auth info
+	ErrAuthNotFound = errors.New("auth identity not found in loaded auth info")
+
 	// ErrHost host string is invalid
 	ErrHost = errors.New("invalid host")
 
+	// ErrSourceFormat unsupported order source file format
+	ErrSourceFormat = errors.New("source file should either be csv or json")
+
+	// ErrOutputFormat unsupported output format
+	ErrOutputFormat = errors.New("output format should be one of: " +
+		"table, csv, json, jsonl, yaml")
+
+	// ErrOutputField output field not found in result
+	ErrOutputField = errors.New("output field not found in result columns")
+
+	// ErrOutputRecord result can not be marshaled as a single csv record
+	ErrOutputRecord = errors.New("result should be marshaled as a single csv record")
+
+	// ErrInterrupt input interrupted by user
+	ErrInterrupt = errors.New("interrupted")
+
+	// ErrQuote unterminated quote in command line
+	ErrQuote = errors.New("unterminated quote in command line")
+
+	// ErrWsTopic invalid websocket subscription topic
+	ErrWsTopic = errors.New("topic should be in format: table[:symbol], " +
+		"table is one of: order, execution, position, trade, " +
+		"orderBookL2, quote, instrument")
+
+	// ErrWsAuth private table subscribed without api key
+	ErrWsAuth = errors.New("private table requires api key authentication")
+
+	// ErrWsAction unknown table action
+	ErrWsAction = errors.New("table action should be one of: " +
+		"partial, insert, update, delete")
+
+	// ErrWsClosed websocket client already closed
+	ErrWsClosed = errors.New("websocket client closed")
+
+	// ErrBookLevel invalid order book level
+	ErrBookLevel = errors.New("order book level should have id & side")
+
+	// ErrBookInconsistent order book delta mismatch with local levels
+	ErrBookInconsistent = errors.New("order book is inconsistent, re-sync needed")
+
+	// ErrLeverage leverage out of range
+	ErrLeverage = errors.New("leverage should be in range [0, 100], 0 means cross margin")
+
+	// ErrRiskLimit invalid risk limit
+	ErrRiskLimit = errors.New("risk limit should be greater than 0")
+
+	// ErrTransferAmount invalid margin transfer amount
+	ErrTransferAmount = errors.New("transfer amount should not be 0")
+
+	// ErrTransferPrecision margin transfer amount not exact in float32
+	ErrTransferPrecision = errors.New("transfer amount can not be sent " +
+		"exactly, amount beyond 16777216 should be multiple of its precision")
+
+	// ErrProfileNotFound profile not found in "auths.yaml"
+	ErrProfileNotFound = errors.New("profile not found in \"auths.yaml\"")
+
+	// ErrAccount incomplete account info
+	ErrAccount = errors.New("account should have either identity & password " +
+		"or api key & secret")
+
+	// ErrKeystore invalid keystore file
+	ErrKeystore = errors.New("invalid keystore file")
+
+	// ErrKeystorePerm keystore file accessible by others
+	ErrKeystorePerm = errors.New("keystore file should only be accessible by owner, " +
+		"permission should be 0600")
+
+	// ErrPassphrase wrong keystore passphrase
+	ErrPassphrase = errors.New("wrong keystore passphrase")
+
+	// ErrPassphraseConfirm passphrase confirmation mismatch
+	ErrPassphraseConfirm = errors.New("passphrase confirmation mismatch")
+
+	// ErrSealed invalid sealed secret
+	ErrSealed = errors.New("invalid sealed secret or keystore mismatch")
+
+	// ErrRelogin auth can not be refreshed by re-login
+	ErrRelogin = errors.New("auth without identity & password can not be refreshed")
+
+	// ErrNoSession no cached login session
+	ErrNoSession = errors.New("no cached login session")
+
 	// ErrInflightCheck inflight order count overflow
 	ErrInflightCheck = errors.New("inflight order exceeded")
 
+	// ErrConfigKey unknown config key
+	ErrConfigKey = errors.New("unknown config key")
+
+	// ErrConfigValue config value can not be parsed
+	ErrConfigValue = errors.New("invalid config value")
+
+	// ErrScheme invalid host scheme
+	ErrScheme = errors.New("scheme should either be \"http\" or \"https\"")
+
+	// ErrPort port out of range
+	ErrPort = errors.New("port should be in range [1, 65535]")
+
+	// ErrURI invalid base uri
+	ErrURI = errors.New("uri should start with \"/\"")
+
+	// ErrEnvNotFound environment not found in config file
+	ErrEnvNotFound = errors.New("environment not found in config file")
+
+	// ErrTimeFormat unsupported time expression
+	ErrTimeFormat = errors.New("time should be RFC3339, date [time], " +
+		"epoch ms, relative duration like -2h, -1d, or today/yesterday [time]")
+
+	// ErrTimeZone unknown time zone
+	ErrTimeZone = errors.New("time zone should be IANA name like " +
+		"Asia/Shanghai, Local, UTC, or offset like +08:00")
+
+	// ErrInstrumentNotFound symbol not found in instruments
+	ErrInstrumentNotFound = errors.New("instrument not found")
+
+	// ErrInstrumentState instrument not open for trading
+	ErrInstrumentState = errors.New("instrument is not open for trading")
+
+	// ErrPriceTick price not multiple of tick size
+	ErrPriceTick = errors.New("price should be multiple of tick size")
+
+	// ErrMaxPrice price exceeds max price
+	ErrMaxPrice = errors.New("price exceeds max price of instrument")
+
+	// ErrLotSize quantity not multiple of lot size
+	ErrLotSize = errors.New("quantity should be multiple of lot size")
+
+	// ErrMaxOrderQty quantity exceeds max order quantity
+	ErrMaxOrderQty = errors.New("quantity exceeds max order quantity of instrument")
+
 	// ErrTokenInsufficient timeout when getting token
 	ErrTokenInsufficient = errors.New("failed to get token in timeout duration")
+
+	// ErrOrderRate order rate exceeds max rate can be limited
+	ErrOrderRate = errors.New("order rate should not exceed 10000 per second")
 )
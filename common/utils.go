@@ -2,6 +2,9 @@ package common
 
 import (
 	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
@@ -11,8 +14,12 @@ import (
 	"github.com/frozenpine/viper"
 
 	"github.com/frozenpine/ngerest"
+
+	"golang.org/x/crypto/ssh/terminal"
 )
 
+var stdinReader = bufio.NewReader(os.Stdin)
+
 // GetFullPath to get base uri path
 func GetFullPath() string {
 	baseURI := viper.GetString("base-uri")
@@ -39,18 +46,43 @@ func GetBaseURL() string {
 	return scheme + "://" + GetBaseHost()
 }
 
+// GetWsURL to get websocket full url path
+func GetWsURL() string {
+	scheme := "ws"
+	if viper.GetString("scheme") == "https" {
+		scheme = "wss"
+	}
+
+	return scheme + "://" + GetBaseHost() + viper.GetString("ws-uri")
+}
+
+// Signature generate api signature same as REST api in hex,
+// message signed by HMAC-SHA256 is: VERB + PATH + EXPIRES + BODY,
+// PATH should contain query string if exists.
+func Signature(secret, verb, path string, expires int64, body string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+
+	message := strings.ToUpper(verb) + path +
+		strconv.FormatInt(expires, 10) + strings.TrimRight(body, "\r\n")
+
+	h.Write([]byte(message))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // ReadLine read line from io.Reader interface
 func ReadLine(prompt string, src io.Reader) string {
 	if prompt == "" {
 		prompt = "Please input: "
 	}
 
-	if src == nil {
-		src = os.Stdin
+	// stdin reader is shared, so buffered input will not be lost
+	// between lines when stdin is piped
+	reader := stdinReader
+	if src != nil {
+		reader = bufio.NewReader(src)
 	}
 
-	reader := bufio.NewReader(src)
-
 	fmt.Print(prompt)
 
 	text, _ := reader.ReadString('\n')
@@ -58,6 +90,22 @@ func ReadLine(prompt string, src io.Reader) string {
 	return strings.TrimRight(text, "\r\n")
 }
 
+// ReadPassword read line from stdin without echo if stdin is a terminal
+func ReadPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if !terminal.IsTerminal(fd) {
+		return ReadLine(prompt, nil), nil
+	}
+
+	fmt.Print(prompt)
+	defer fmt.Println()
+
+	value, err := terminal.ReadPassword(fd)
+
+	return string(value), err
+}
+
 // CheckSymbol validate order symbol
 func CheckSymbol(symbol string) error {
 	if symbol == "" {
@@ -85,12 +133,23 @@ func CheckQuantity(qty int64) error {
 	return nil
 }
 
-// PrintError to auto parse err and print in console
+// PrintError to auto parse err and print in console, err is also
+// reported by SetExitError to decide command's exit code.
 func PrintError(prefix string, err error) {
-	if swErr, ok := err.(ngerest.GenericSwaggerError); ok {
+	SetExitError(err)
+
+	switch e := err.(type) {
+	case ngerest.GenericSwaggerError:
 		fmt.Printf(
-			prefix+": %s\n%s\n", swErr.Error(), string(swErr.Body()))
-	} else {
+			prefix+": %s\n%s\n", e.Error(), string(e.Body()))
+	case *APIError:
+		if e.Message == "" && len(e.Body) > 0 {
+			fmt.Printf(
+				prefix+": %s\n%s\n", e.Error(), string(e.Body))
+		} else {
+			fmt.Printf(prefix+": %s\n", e.Error())
+		}
+	default:
 		fmt.Printf(prefix+": %s\n", err.Error())
 	}
 }
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"os"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
@@ -10,15 +11,7 @@ import (
 )
 
 var (
-	initOnce sync.Once
-
-	highPriority = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= zapcore.WarnLevel
-	})
-
-	lowPriority = zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl < zapcore.WarnLevel
-	})
+	lock sync.RWMutex
 
 	encodeConfig = zapcore.EncoderConfig{
 		MessageKey:     "msg",
@@ -32,113 +25,201 @@ var (
 		EncodeDuration: zapcore.NanosDurationEncoder,
 	}
 
-	cores []zapcore.Core
+	config = DefaultConfig()
 
-	logger *zap.Logger
+	// file writer is kept, so it can be closed when logger rebuilt
+	fileWriter zapcore.WriteSyncer
+
+	// name of default logger set by Use
+	loggerName string
+
+	logger = newLogger(config, "")
 )
 
-func init() {
-	consoleOut := zapcore.Lock(os.Stdout)
-	consoleErr := zapcore.Lock(os.Stderr)
+// newEncoder create encoder by format, console encoder is used
+// for unknown format.
+func newEncoder(format string, color bool) zapcore.Encoder {
+	encoderConfig := encodeConfig
 
-	consoleEncoder := zapcore.NewConsoleEncoder(encodeConfig)
+	if color {
+		encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	}
 
-	cores = append(cores,
-		zapcore.NewCore(consoleEncoder, consoleErr, highPriority),
-		zapcore.NewCore(consoleEncoder, consoleOut, lowPriority),
-	)
+	if format == FormatJSON {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
-func initLogger() {
-	initOnce.Do(func() {
-		if logger == nil {
-			logger = zap.New(zapcore.NewTee(cores...))
-		}
+// newCore create core writing warn & above to stderr, lower levels to
+// stdout, and all levels to log file if file writer is set.
+func newCore(cfg Config, level zapcore.Level) zapcore.Core {
+	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= level && lvl >= zapcore.WarnLevel
+	})
+
+	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= level && lvl < zapcore.WarnLevel
 	})
+
+	consoleEncoder := newEncoder(cfg.Format, cfg.Color)
+
+	cores := []zapcore.Core{
+		zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stderr), highPriority),
+		zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stdout), lowPriority),
+	}
+
+	if fileWriter != nil {
+		cores = append(cores, zapcore.NewCore(
+			newEncoder(cfg.FileFormat, false), fileWriter, level))
+	}
+
+	return zapcore.NewTee(cores...)
+}
+
+// nameLevel get level of named logger, level of the nearest parent name
+// is used if name not configured, parts of name are joined by periods.
+func nameLevel(cfg Config, name string) zapcore.Level {
+	for name != "" {
+		if level, exist := cfg.Levels[name]; exist {
+			return level
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			break
+		}
+
+		name = name[:idx]
+	}
+
+	return cfg.Level
+}
+
+func newLogger(cfg Config, name string) *zap.Logger {
+	log := zap.New(newCore(cfg, nameLevel(cfg, name)))
+
+	if name != "" {
+		log = log.Named(name)
+	}
+
+	return log
+}
+
+// Init rebuild logger by config, which should be called after config
+// loaded, previous log file will be synced & closed.
+func Init(cfg Config) error {
+	writer, err := newFileWriter(cfg)
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	closeFile()
+
+	config, fileWriter = cfg, writer
+	logger = newLogger(config, loggerName)
+
+	return nil
+}
+
+// Use replace default logger with named logger, so following logs are
+// tagged with name & leveled by level configured for name.
+func Use(name string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	loggerName = name
+	logger = newLogger(config, name)
+}
+
+// current get default logger
+func current() *zap.Logger {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return logger
+}
+
+// Enabled check if level is enabled by default logger
+func Enabled(level zapcore.Level) bool {
+	return current().Core().Enabled(level)
 }
 
 // Flush make sure log in buffer will be synced.
 func Flush() {
-	if logger == nil {
-		return
-	}
+	lock.RLock()
+	defer lock.RUnlock()
+
+	// sync error of console is ignored, as terminal can not be synced,
+	// log file is synced by core
+	logger.Sync()
 }
 
 // Sugar converts a Logger to a SugaredLogger.
 func Sugar() *zap.SugaredLogger {
-	initLogger()
-
-	return logger.Sugar()
+	return current().Sugar()
 }
 
-// Named adds a new path segment to the logger's name. Segments are joined by
-// periods.
+// Named create a new logger with name & level configured for name,
+// name is joined to default logger's name by periods.
 func Named(name string) *zap.Logger {
-	initLogger()
+	lock.RLock()
+	defer lock.RUnlock()
 
-	return logger.Named(name)
+	if loggerName != "" {
+		name = loggerName + "." + name
+	}
+
+	return newLogger(config, name)
 }
 
 // WithOptions clones the current Logger, applies the supplied Options, and
 // returns the resulting Logger. It's safe to use concurrently.
 func WithOptions(opts ...zap.Option) *zap.Logger {
-	initLogger()
-
-	return logger.WithOptions(opts...)
+	return current().WithOptions(opts...)
 }
 
 // With creates a child logger and adds structured context to it. Fields added
 // to the child don't affect the parent, and vice versa.
 func With(fields ...zapcore.Field) *zap.Logger {
-	initLogger()
-	return logger.With(fields...)
+	return current().With(fields...)
 }
 
 // Debug log debug level message
 func Debug(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Debug(msg, fields...)
+	current().Debug(msg, fields...)
 }
 
 // Info log info level message
 func Info(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Info(msg, fields...)
+	current().Info(msg, fields...)
 }
 
 // Warn log warn level message
 func Warn(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Warn(msg, fields...)
+	current().Warn(msg, fields...)
 }
 
 // Error log error level message
 func Error(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Error(msg, fields...)
+	current().Error(msg, fields...)
 }
 
 // DPanic log panic level message & throw a panic in development mode
 func DPanic(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.DPanic(msg, fields...)
+	current().DPanic(msg, fields...)
 }
 
 // Panic log panic level message & throw a panic
 func Panic(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Panic(msg, fields...)
+	current().Panic(msg, fields...)
 }
 
 // Fatal log fatal level message & exit
 func Fatal(msg string, fields ...zapcore.Field) {
-	initLogger()
-
-	logger.Fatal(msg, fields...)
+	current().Fatal(msg, fields...)
 }
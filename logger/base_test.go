@@ -1,9 +1,14 @@
 package logger
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLog(t *testing.T) {
@@ -13,3 +18,50 @@ func TestLog(t *testing.T) {
 
 	testLogger.Error("error occoured", zap.Bool("success", false))
 }
+
+func TestInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := DefaultConfig()
+	cfg.Level = zapcore.WarnLevel
+	cfg.Levels = map[string]zapcore.Level{"order": zapcore.DebugLevel}
+	cfg.File = filepath.Join(dir, "logs", "test.log")
+
+	if err = Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer Init(DefaultConfig())
+
+	if Enabled(zapcore.InfoLevel) {
+		t.Error("info should be disabled by level")
+	}
+
+	Use("order.new")
+	defer Use("")
+
+	if !Enabled(zapcore.DebugLevel) {
+		t.Error("sub command should inherit level of parent")
+	}
+
+	Debug("debug in order", zap.String("symbol", "XBTUSD"))
+
+	if !Named("http").Core().Enabled(zapcore.DebugLevel) {
+		t.Error("named logger should inherit level of command")
+	}
+
+	Flush()
+
+	content, err := ioutil.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(content), `"logger":"order.new"`) ||
+		!strings.Contains(string(content), "debug in order") {
+		t.Error("log file content mismatch:", string(content))
+	}
+}
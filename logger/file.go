@@ -1,27 +1,113 @@
 package logger
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/natefinch/lumberjack"
-	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-func init() {
-	fileLogger := lumberjack.Logger{
-		Filename:   "logs/ngecli.log",
+// Log formats
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+// Config settings of logging, log file is disabled if File is empty
+type Config struct {
+	// Level minimum level of all loggers
+	Level zapcore.Level
+	// Levels level of named loggers, overriding Level
+	Levels map[string]zapcore.Level
+	// Format console log format, either "console" or "json"
+	Format string
+	// Color colorize level in console log
+	Color bool
+
+	// File log file path
+	File string
+	// FileFormat log file format, either "console" or "json"
+	FileFormat string
+	// MaxSize max size in megabytes of log file before rotated
+	MaxSize int
+	// MaxAge max days to retain rotated log files
+	MaxAge int
+	// MaxBackups max count of rotated log files retained
+	MaxBackups int
+	// Compress compress rotated log files with gzip
+	Compress bool
+}
+
+// DefaultConfig default logging settings, logs are only written to console
+// at info level before logger initialized by config.
+func DefaultConfig() Config {
+	return Config{
+		Level:      zapcore.InfoLevel,
+		Format:     FormatConsole,
+		FileFormat: FormatJSON,
 		MaxSize:    500,
-		MaxBackups: 3,
 		MaxAge:     7,
+		MaxBackups: 3,
 		Compress:   true,
 	}
+}
+
+// ParseLevel parse level name, such as "debug", "info", "warn", "error"
+func ParseLevel(name string) (zapcore.Level, error) {
+	var level zapcore.Level
+
+	if err := level.UnmarshalText([]byte(strings.ToLower(name))); err != nil {
+		return level, fmt.Errorf("invalid log level: %s", name)
+	}
+
+	return level, nil
+}
 
-	writer := zapcore.AddSync(&fileLogger)
+// CheckFormat check if log format is supported
+func CheckFormat(format string) bool {
+	return format == FormatConsole || format == FormatJSON
+}
+
+// newFileWriter create rotated log file writer, nil writer is returned
+// if file is not set.
+func newFileWriter(cfg Config) (zapcore.WriteSyncer, error) {
+	if cfg.File == "" {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.File), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return &fileSyncer{Logger: &lumberjack.Logger{
+		Filename:   cfg.File,
+		MaxSize:    cfg.MaxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAge,
+		Compress:   cfg.Compress,
+	}}, nil
+}
+
+// fileSyncer rotated log file which can be synced, lumberjack has no
+// Sync, so file is closed on sync & reopened by next write.
+type fileSyncer struct {
+	*lumberjack.Logger
+}
+
+func (f *fileSyncer) Sync() error {
+	return f.Close()
+}
+
+// closeFile sync & close log file writer, lock should be held
+func closeFile() {
+	if fileWriter == nil {
+		return
+	}
 
-	fileCore := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encodeConfig),
-		writer,
-		zap.DebugLevel,
-	)
+	fileWriter.Sync()
 
-	cores = append(cores, fileCore)
+	fileWriter = nil
 }
@@ -0,0 +1,268 @@
+package mock
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+// pkcs8 client drops 76 trailing bytes of public key before parsing
+const publicKeyTrailing = 76
+
+var (
+	errKeyMissing       = errors.New("Missing API key.")
+	errKeyInvalid       = errors.New("Invalid API Key.")
+	errSignatureExpired = errors.New("This request has expired.")
+	errSignature        = errors.New("Signature not valid.")
+)
+
+// Account account registered in fake server
+type Account struct {
+	ID       float32
+	Identity string
+	Password string
+	Key      string
+	Secret   string
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// AddAccount register account with identity & password,
+// a default api key is generated for account.
+func (srv *Server) AddAccount(identity, password string) *Account {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	srv.accountID++
+
+	account := Account{
+		ID:       srv.accountID,
+		Identity: identity,
+		Password: password,
+		Key:      randomHex(12),
+		Secret:   randomHex(24),
+	}
+
+	srv.accounts[identity] = &account
+	srv.keys[account.Key] = &account
+
+	return &account
+}
+
+// ExpireKey rotate account's api key, requests signed by old key will be
+// rejected with 401, new key can be got by login again.
+func (srv *Server) ExpireKey(identity string) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	account, exist := srv.accounts[identity]
+	if !exist {
+		return
+	}
+
+	delete(srv.keys, account.Key)
+
+	account.Key = randomHex(12)
+	account.Secret = randomHex(24)
+
+	srv.keys[account.Key] = account
+}
+
+// keyAccount get account & secret of api key
+func (srv *Server) keyAccount(key string) (*Account, string) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	account, exist := srv.keys[key]
+	if !exist {
+		return nil, ""
+	}
+
+	return account, account.Secret
+}
+
+// authenticate verify api key signature of request
+func (srv *Server) authenticate(r *http.Request, body []byte) (
+	*Account, error) {
+	key := r.Header.Get("api-key")
+	if key == "" {
+		return nil, errKeyMissing
+	}
+
+	account, secret := srv.keyAccount(key)
+	if account == nil {
+		return nil, errKeyInvalid
+	}
+
+	expires, err := strconv.ParseInt(r.Header.Get("api-expires"), 10, 64)
+	if err != nil || expires < time.Now().Unix() {
+		return nil, errSignatureExpired
+	}
+
+	path := r.URL.Path
+	if r.URL.RawQuery != "" {
+		path = path + "?" + r.URL.RawQuery
+	}
+
+	signature := common.Signature(
+		secret, r.Method, path, expires, string(body))
+
+	if !hmac.Equal(
+		[]byte(signature), []byte(r.Header.Get("api-signature"))) {
+		return nil, errSignature
+	}
+
+	return account, nil
+}
+
+func (srv *Server) handlePublicKey(w http.ResponseWriter, r *http.Request) {
+	der, err := x509.MarshalPKIXPublicKey(&srv.exchangeKey.PublicKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	der = append(der, make([]byte, publicKeyTrailing)...)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"result": base64.StdEncoding.EncodeToString(der)})
+}
+
+func (srv *Server) handleLogin(
+	w http.ResponseWriter, r *http.Request, body []byte) {
+	params, err := parseParams(r, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity := params.Get("email")
+	if identity == "" {
+		identity = params.Get("mobile")
+	}
+
+	cipher, err := base64.StdEncoding.DecodeString(params.Get("password"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid password.")
+		return
+	}
+
+	password, err := rsa.DecryptPKCS1v15(rand.Reader, srv.exchangeKey, cipher)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid password.")
+		return
+	}
+
+	srv.lock.Lock()
+	account, exist := srv.accounts[identity]
+	srv.lock.Unlock()
+
+	if !exist || account.Password != string(password) {
+		writeError(w, http.StatusUnauthorized, "Invalid identity or password.")
+		return
+	}
+
+	token := randomHex(16)
+
+	srv.lock.Lock()
+	srv.tokens[token] = account
+	srv.lock.Unlock()
+
+	w.Header().Set("X-Auth-Token", token)
+	http.SetCookie(w, &http.Cookie{
+		Name: "session", Value: token, Path: "/", HttpOnly: true})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"result": map[string]string{"code": "0"}})
+}
+
+// tokenAccount get account logged in by x-auth-token or session cookie
+func (srv *Server) tokenAccount(r *http.Request) (*Account, string) {
+	token := r.Header.Get("x-auth-token")
+	if token == "" {
+		if cookie, err := r.Cookie("session"); err == nil {
+			token = cookie.Value
+		}
+	}
+
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	return srv.tokens[token], token
+}
+
+func (srv *Server) handleDefaultKey(
+	w http.ResponseWriter, r *http.Request, body []byte) {
+	account, _ := srv.tokenAccount(r)
+	if account == nil {
+		writeError(w, http.StatusUnauthorized, "Not logged in.")
+		return
+	}
+
+	der, err := base64.StdEncoding.DecodeString(
+		strings.TrimSpace(string(body)))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid public key.")
+		return
+	}
+
+	pubKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid public key.")
+		return
+	}
+
+	rsaKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid public key.")
+		return
+	}
+
+	srv.lock.Lock()
+	key, secret := account.Key, account.Secret
+	srv.lock.Unlock()
+
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, rsaKey, []byte(secret))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"result": map[string]string{
+			"code":   "0",
+			"apiKey": key,
+			"secret": base64.StdEncoding.EncodeToString(encrypted),
+			"userId": fmt.Sprint(account.ID),
+		}})
+}
+
+func (srv *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	account, token := srv.tokenAccount(r)
+	if account == nil {
+		writeError(w, http.StatusUnauthorized, "Not logged in.")
+		return
+	}
+
+	srv.lock.Lock()
+	delete(srv.tokens, token)
+	srv.lock.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package mock
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+const (
+	// DefaultSymbol symbol of contract served by default
+	DefaultSymbol = "XBTUSD"
+	// DefaultIndex symbol of index served by default
+	DefaultIndex = ".BXBT"
+
+	stateOpen     = "Open"
+	stateUnlisted = "Unlisted"
+
+	typIndex = "MRCXXX"
+)
+
+func defaultInstruments() map[string]*ngerest.Instrument {
+	now := time.Now().UTC()
+
+	return map[string]*ngerest.Instrument{
+		DefaultSymbol: {
+			Symbol:          DefaultSymbol,
+			RootSymbol:      "XBT",
+			State:           stateOpen,
+			Typ:             "FFWCSX",
+			Underlying:      "XBT",
+			QuoteCurrency:   "USD",
+			SettlCurrency:   "XBt",
+			ReferenceSymbol: DefaultIndex,
+			MaxOrderQty:     10000000,
+			MaxPrice:        1000000,
+			LotSize:         1,
+			TickSize:        0.5,
+			Multiplier:      -100000000,
+			IsInverse:       true,
+			InitMargin:      0.01,
+			MaintMargin:     0.005,
+			MakerFee:        -0.00025,
+			TakerFee:        0.00075,
+			LastPrice:       5050,
+			MarkPrice:       5050,
+			Timestamp:       now,
+		},
+		DefaultIndex: {
+			Symbol:     DefaultIndex,
+			RootSymbol: "XBT",
+			State:      stateUnlisted,
+			Typ:        typIndex,
+			TickSize:   0.01,
+			LastPrice:  5050,
+			Timestamp:  now,
+		},
+	}
+}
+
+func isIndex(ins *ngerest.Instrument) bool {
+	return ins.Typ == typIndex || strings.HasPrefix(ins.Symbol, ".")
+}
+
+// AddInstrument add or replace instrument by symbol
+func (srv *Server) AddInstrument(ins ngerest.Instrument) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if ins.Timestamp.IsZero() {
+		ins.Timestamp = time.Now().UTC()
+	}
+
+	srv.instruments[ins.Symbol] = &ins
+}
+
+// filterInstruments get copies of instruments matching filter,
+// sorted by symbol.
+func (srv *Server) filterInstruments(
+	filter func(ins *ngerest.Instrument) bool) []ngerest.Instrument {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	result := []ngerest.Instrument{}
+
+	for _, ins := range srv.instruments {
+		if filter(ins) {
+			result = append(result, *ins)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+
+	return result
+}
+
+// page slice rows by start & count query params
+func page(r *http.Request, size int) (int, int) {
+	query := r.URL.Query()
+
+	start, _ := strconv.Atoi(query.Get("start"))
+	if start < 0 || start > size {
+		start = size
+	}
+
+	end := size
+	if count, err := strconv.Atoi(query.Get("count")); err == nil &&
+		count >= 0 && start+count < size {
+		end = start + count
+	}
+
+	return start, end
+}
+
+func (srv *Server) handleInstrument(
+	w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	symbol := r.URL.Query().Get("symbol")
+
+	switch path {
+	case "/instrument":
+		result := srv.filterInstruments(func(ins *ngerest.Instrument) bool {
+			return symbol == "" || ins.Symbol == symbol
+		})
+
+		start, end := page(r, len(result))
+
+		writeJSON(w, http.StatusOK, result[start:end])
+	case "/instrument/active":
+		writeJSON(w, http.StatusOK, srv.filterInstruments(
+			func(ins *ngerest.Instrument) bool {
+				return ins.State == stateOpen && !isIndex(ins)
+			}))
+	case "/instrument/activeAndIndices":
+		writeJSON(w, http.StatusOK, srv.filterInstruments(
+			func(ins *ngerest.Instrument) bool {
+				return ins.State == stateOpen || isIndex(ins)
+			}))
+	case "/instrument/indices":
+		writeJSON(w, http.StatusOK, srv.filterInstruments(isIndex))
+	case "/instrument/compositeIndex":
+		if symbol == "" {
+			symbol = DefaultIndex
+		}
+
+		result := []ngerest.IndexComposite{}
+
+		for _, ins := range srv.filterInstruments(isIndex) {
+			if ins.Symbol != symbol {
+				continue
+			}
+
+			result = append(result, ngerest.IndexComposite{
+				Timestamp:   ins.Timestamp,
+				Symbol:      ins.Symbol,
+				IndexSymbol: ins.Symbol,
+				Reference:   "MOCK",
+				LastPrice:   ins.LastPrice,
+				Weight:      1,
+				Logged:      ins.Timestamp,
+			})
+		}
+
+		start, end := page(r, len(result))
+
+		writeJSON(w, http.StatusOK, result[start:end])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
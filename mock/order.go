@@ -0,0 +1,734 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+const (
+	statusNew      = "New"
+	statusPartial  = "PartiallyFilled"
+	statusFilled   = "Filled"
+	statusCanceled = "Canceled"
+	statusRejected = "Rejected"
+)
+
+var (
+	errOrderNotFound = errors.New("Not Found")
+	errSymbol        = errors.New("Invalid symbol.")
+	errSide          = errors.New("Invalid side.")
+	errQuantity      = errors.New("Invalid orderQty.")
+	errPrice         = errors.New("Invalid price.")
+	errOrdType       = errors.New("Invalid ordType.")
+	errDuplicate     = errors.New("Duplicate clOrdID.")
+	errClosed        = errors.New("Invalid ordStatus.")
+)
+
+func isClosed(ord *ngerest.Order) bool {
+	switch ord.OrdStatus {
+	case statusFilled, statusCanceled, statusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+func timestamp() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// orderBook in memory order book with price-time priority matching,
+// orders are matched regardless of account.
+type orderBook struct {
+	lock     sync.Mutex
+	orders   []*ngerest.Order
+	byID     map[string]*ngerest.Order
+	byClOrd  map[string]*ngerest.Order
+	deadline map[float32]*time.Timer
+}
+
+func newOrderBook() *orderBook {
+	return &orderBook{
+		byID:     make(map[string]*ngerest.Order),
+		byClOrd:  make(map[string]*ngerest.Order),
+		deadline: make(map[float32]*time.Timer),
+	}
+}
+
+func clOrdKey(account float32, clOrdID string) string {
+	return fmt.Sprint(account) + "/" + clOrdID
+}
+
+// Orders get copies of orders by account & symbol, 0 account or
+// empty symbol matches all.
+func (book *orderBook) Orders(account float32, symbol string) []ngerest.Order {
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	var result []ngerest.Order
+
+	for _, ord := range book.orders {
+		if (account == 0 || ord.Account == account) &&
+			(symbol == "" || ord.Symbol == symbol) {
+			result = append(result, *ord)
+		}
+	}
+
+	return result
+}
+
+// crossed check if taker's price crosses maker's price
+func crossed(taker, maker *ngerest.Order) bool {
+	if taker.OrdType == "Market" {
+		return true
+	}
+
+	if taker.Side == "Buy" {
+		return taker.Price >= maker.Price
+	}
+
+	return taker.Price <= maker.Price
+}
+
+// makers get resting orders opposite to taker sorted by priority
+func (book *orderBook) makers(taker *ngerest.Order) []*ngerest.Order {
+	var makers []*ngerest.Order
+
+	for _, ord := range book.orders {
+		if ord == taker || isClosed(ord) || ord.Symbol != taker.Symbol ||
+			ord.Side == taker.Side || !crossed(taker, ord) {
+			continue
+		}
+
+		makers = append(makers, ord)
+	}
+
+	sort.SliceStable(makers, func(i, j int) bool {
+		if taker.Side == "Buy" {
+			return makers[i].Price < makers[j].Price
+		}
+
+		return makers[i].Price > makers[j].Price
+	})
+
+	return makers
+}
+
+func fill(ord *ngerest.Order, qty float32, price float64, now int64) {
+	ord.AvgPx = (ord.AvgPx*float64(ord.CumQty) + price*float64(qty)) /
+		float64(ord.CumQty+qty)
+	ord.CumQty += qty
+	ord.LeavesQty -= qty
+	ord.TransactTime = now
+	ord.Timestamp = now
+
+	if ord.LeavesQty > 0 {
+		ord.OrdStatus = statusPartial
+	} else {
+		ord.OrdStatus = statusFilled
+		ord.WorkingIndicator = false
+	}
+}
+
+func cancel(ord *ngerest.Order, text string, now int64) {
+	ord.OrdStatus = statusCanceled
+	ord.WorkingIndicator = false
+	ord.LeavesQty = 0
+	ord.Timestamp = now
+
+	if text != "" {
+		ord.Text = text
+	}
+}
+
+// match match taker with resting orders, remaining quantity of market,
+// IOC & FOK orders will be canceled.
+func (book *orderBook) match(taker *ngerest.Order) {
+	now := timestamp()
+	makers := book.makers(taker)
+
+	if taker.TimeInForce == "FillOrKill" {
+		var available float32
+
+		for _, maker := range makers {
+			available += maker.LeavesQty
+		}
+
+		if available < taker.LeavesQty {
+			cancel(taker, "Canceled: Order had execInst of FillOrKill", now)
+			return
+		}
+	}
+
+	for _, maker := range makers {
+		if taker.LeavesQty <= 0 {
+			break
+		}
+
+		qty := float32(math.Min(
+			float64(taker.LeavesQty), float64(maker.LeavesQty)))
+
+		fill(maker, qty, maker.Price, now)
+		fill(taker, qty, maker.Price, now)
+	}
+
+	if taker.LeavesQty > 0 &&
+		(taker.OrdType == "Market" || taker.TimeInForce == "ImmediateOrCancel") {
+		cancel(taker, "Canceled: Unfilled remaining quantity", now)
+	}
+}
+
+// Insert validate, insert & match new order of account
+func (book *orderBook) Insert(account float32, params url.Values) (
+	*ngerest.Order, error) {
+	ord := ngerest.Order{
+		Account:     account,
+		Symbol:      params.Get("symbol"),
+		Side:        params.Get("side"),
+		ClOrdID:     params.Get("clOrdID"),
+		ClOrdLinkID: params.Get("clOrdLinkID"),
+		OrdType:     params.Get("ordType"),
+		TimeInForce: params.Get("timeInForce"),
+		ExecInst:    params.Get("execInst"),
+		Text:        params.Get("text"),
+	}
+
+	if ord.Symbol == "" {
+		return nil, errSymbol
+	}
+
+	qty, _ := strconv.ParseFloat(params.Get("orderQty"), 64)
+
+	if ord.Side == "" {
+		if qty > 0 {
+			ord.Side = "Buy"
+		} else {
+			ord.Side = "Sell"
+		}
+	}
+
+	if ord.Side != "Buy" && ord.Side != "Sell" {
+		return nil, errSide
+	}
+
+	if qty = math.Abs(qty); qty == 0 {
+		return nil, errQuantity
+	}
+
+	ord.OrderQty = float32(qty)
+	ord.LeavesQty = ord.OrderQty
+
+	if value := params.Get("price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price <= 0 {
+			return nil, errPrice
+		}
+
+		ord.Price = price
+	}
+
+	if ord.OrdType == "" {
+		if ord.Price > 0 {
+			ord.OrdType = "Limit"
+		} else {
+			ord.OrdType = "Market"
+		}
+	}
+
+	switch ord.OrdType {
+	case "Limit":
+		if ord.Price <= 0 {
+			return nil, errPrice
+		}
+	case "Market":
+	default:
+		return nil, errOrdType
+	}
+
+	if ord.TimeInForce == "" {
+		ord.TimeInForce = "GoodTillCancel"
+	}
+
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	if ord.ClOrdID != "" {
+		if _, exist := book.byClOrd[clOrdKey(account, ord.ClOrdID)]; exist {
+			return nil, errDuplicate
+		}
+	}
+
+	now := timestamp()
+
+	ord.OrderID = newOrderID()
+	ord.OrdStatus = statusNew
+	ord.WorkingIndicator = true
+	ord.TransactTime = now
+	ord.Timestamp = now
+
+	book.orders = append(book.orders, &ord)
+	book.byID[ord.OrderID] = &ord
+	if ord.ClOrdID != "" {
+		book.byClOrd[clOrdKey(account, ord.ClOrdID)] = &ord
+	}
+
+	book.match(&ord)
+
+	result := ord
+
+	return &result, nil
+}
+
+// find get account's order by orderID or clOrdID, book should be locked
+func (book *orderBook) find(account float32, orderID, clOrdID string) (
+	*ngerest.Order, error) {
+	var ord *ngerest.Order
+
+	switch {
+	case orderID != "":
+		ord = book.byID[orderID]
+	case clOrdID != "":
+		ord = book.byClOrd[clOrdKey(account, clOrdID)]
+	}
+
+	if ord == nil || ord.Account != account {
+		return nil, errOrderNotFound
+	}
+
+	return ord, nil
+}
+
+// Amend amend price or quantity of account's open order
+func (book *orderBook) Amend(account float32, params url.Values) (
+	*ngerest.Order, error) {
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	ord, err := book.find(
+		account, params.Get("orderID"), params.Get("origClOrdID"))
+	if err != nil {
+		return nil, err
+	}
+
+	if isClosed(ord) {
+		return nil, errClosed
+	}
+
+	leaves := ord.LeavesQty
+
+	if value := params.Get("orderQty"); value != "" {
+		qty, err := strconv.ParseFloat(value, 64)
+		if err != nil || float32(qty) <= ord.CumQty {
+			return nil, errQuantity
+		}
+
+		leaves = float32(qty) - ord.CumQty
+	}
+
+	if value := params.Get("leavesQty"); value != "" {
+		qty, err := strconv.ParseFloat(value, 64)
+		if err != nil || qty <= 0 {
+			return nil, errQuantity
+		}
+
+		leaves = float32(qty)
+	}
+
+	price := ord.Price
+
+	if value := params.Get("price"); value != "" {
+		if price, err = strconv.ParseFloat(value, 64); err != nil ||
+			price <= 0 || ord.OrdType != "Limit" {
+			return nil, errPrice
+		}
+	}
+
+	if clOrdID := params.Get("clOrdID"); clOrdID != "" {
+		key := clOrdKey(account, clOrdID)
+
+		if _, exist := book.byClOrd[key]; exist {
+			return nil, errDuplicate
+		}
+
+		delete(book.byClOrd, clOrdKey(account, ord.ClOrdID))
+		book.byClOrd[key] = ord
+		ord.ClOrdID = clOrdID
+	}
+
+	if text := params.Get("text"); text != "" {
+		ord.Text = text
+	}
+
+	ord.OrderQty = ord.CumQty + leaves
+	ord.LeavesQty = leaves
+	ord.Price = price
+	ord.Timestamp = timestamp()
+
+	book.match(ord)
+
+	result := *ord
+
+	return &result, nil
+}
+
+// Cancel cancel account's orders by orderIDs or clOrdIDs
+func (book *orderBook) Cancel(
+	account float32, orderIDs, clOrdIDs []string, text string) (
+	[]ngerest.Order, error) {
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	var targets []*ngerest.Order
+
+	for _, id := range orderIDs {
+		if ord, err := book.find(account, id, ""); err == nil {
+			targets = append(targets, ord)
+		}
+	}
+
+	for _, id := range clOrdIDs {
+		if ord, err := book.find(account, "", id); err == nil {
+			targets = append(targets, ord)
+		}
+	}
+
+	if len(targets) < 1 {
+		return nil, errOrderNotFound
+	}
+
+	return book.cancelOrders(targets, text), nil
+}
+
+// cancelOrders cancel open orders in targets, book should be locked
+func (book *orderBook) cancelOrders(
+	targets []*ngerest.Order, text string) []ngerest.Order {
+	now := timestamp()
+	result := make([]ngerest.Order, 0, len(targets))
+
+	for _, ord := range targets {
+		if !isClosed(ord) {
+			cancel(ord, text, now)
+		}
+
+		result = append(result, *ord)
+	}
+
+	return result
+}
+
+// CancelAll cancel all account's open orders matching symbol & filter
+func (book *orderBook) CancelAll(
+	account float32, symbol string, filter map[string]interface{},
+	text string) []ngerest.Order {
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	var targets []*ngerest.Order
+
+	for _, ord := range book.orders {
+		if ord.Account != account || isClosed(ord) ||
+			(symbol != "" && ord.Symbol != symbol) ||
+			!matchFilter(ord, filter) {
+			continue
+		}
+
+		targets = append(targets, ord)
+	}
+
+	return book.cancelOrders(targets, text)
+}
+
+// CancelAfter cancel all account's open orders after timeout,
+// timeout less than 1 disables previous settings.
+func (book *orderBook) CancelAfter(account float32, timeout time.Duration) {
+	book.lock.Lock()
+	defer book.lock.Unlock()
+
+	if timer, exist := book.deadline[account]; exist {
+		timer.Stop()
+		delete(book.deadline, account)
+	}
+
+	if timeout <= 0 {
+		return
+	}
+
+	book.deadline[account] = time.AfterFunc(timeout, func() {
+		book.CancelAll(
+			account, "", nil, "Canceled: Cancel all after timeout")
+	})
+}
+
+func newOrderID() string {
+	id := randomHex(16)
+
+	return strings.Join(
+		[]string{id[:8], id[8:12], id[12:16], id[16:20], id[20:]}, "-")
+}
+
+// matchValue check if field equals to value, or any of values in array
+func matchValue(field, value interface{}) bool {
+	if values, ok := value.([]interface{}); ok {
+		for _, v := range values {
+			if fmt.Sprint(field) == fmt.Sprint(v) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return fmt.Sprint(field) == fmt.Sprint(value)
+}
+
+// matchFilter check if order's fields equal to filter values,
+// array values match any of its elements,
+// "open" filter matches orders not closed.
+func matchFilter(ord *ngerest.Order, filter map[string]interface{}) bool {
+	if len(filter) < 1 {
+		return true
+	}
+
+	data, _ := json.Marshal(ord)
+
+	fields := make(map[string]interface{})
+	json.Unmarshal(data, &fields)
+
+	for name, value := range filter {
+		if name == "open" {
+			if fmt.Sprint(value) == "true" && isClosed(ord) {
+				return false
+			}
+
+			continue
+		}
+
+		if !matchValue(fields[name], value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseFilter(value string) (map[string]interface{}, error) {
+	filter := make(map[string]interface{})
+
+	if value == "" {
+		return filter, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &filter); err != nil {
+		return nil, errors.New("Invalid filter.")
+	}
+
+	return filter, nil
+}
+
+// splitIDs split ids in json array or comma separated string
+func splitIDs(value string) []string {
+	var ids []string
+
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+
+	if json.Unmarshal([]byte(value), &ids) == nil {
+		return ids
+	}
+
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// bulkParams parse json array in "orders" param as params of each order
+func bulkParams(value string) ([]url.Values, error) {
+	var orders []map[string]interface{}
+
+	if err := json.Unmarshal([]byte(value), &orders); err != nil ||
+		len(orders) < 1 {
+		return nil, errors.New("Invalid orders.")
+	}
+
+	result := make([]url.Values, 0, len(orders))
+
+	for _, ord := range orders {
+		params := url.Values{}
+
+		for name, value := range ord {
+			params.Set(name, fmt.Sprint(value))
+		}
+
+		result = append(result, params)
+	}
+
+	return result, nil
+}
+
+func (srv *Server) queryOrders(
+	w http.ResponseWriter, account *Account, params url.Values) {
+	filter, err := parseFilter(params.Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orders := srv.book.Orders(account.ID, params.Get("symbol"))
+
+	result := make([]ngerest.Order, 0, len(orders))
+	for idx := range orders {
+		if matchFilter(&orders[idx], filter) {
+			result = append(result, orders[idx])
+		}
+	}
+
+	if params.Get("reverse") == "true" {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+
+	if start, _ := strconv.Atoi(params.Get("start")); start > 0 {
+		if start > len(result) {
+			start = len(result)
+		}
+
+		result = result[start:]
+	}
+
+	if count, _ := strconv.Atoi(params.Get("count")); count > 0 &&
+		count < len(result) {
+		result = result[:count]
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func orderError(w http.ResponseWriter, err error) {
+	if err == errOrderNotFound {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// bulkOrders apply handle to each order in bulk request
+func bulkOrders(w http.ResponseWriter, params url.Values,
+	handle func(url.Values) (*ngerest.Order, error)) {
+	orders, err := bulkParams(params.Get("orders"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := make([]ngerest.Order, 0, len(orders))
+
+	for _, ord := range orders {
+		value, err := handle(ord)
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+
+		result = append(result, *value)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (srv *Server) handleOrder(w http.ResponseWriter, r *http.Request,
+	path string, account *Account, body []byte) {
+	params, err := parseParams(r, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	insert := func(params url.Values) (*ngerest.Order, error) {
+		return srv.book.Insert(account.ID, params)
+	}
+	amend := func(params url.Values) (*ngerest.Order, error) {
+		return srv.book.Amend(account.ID, params)
+	}
+
+	switch r.Method + " " + path {
+	case "GET /order":
+		srv.queryOrders(w, account, params)
+	case "POST /order":
+		ord, err := insert(params)
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ord)
+	case "POST /order/bulk":
+		bulkOrders(w, params, insert)
+	case "PUT /order":
+		ord, err := amend(params)
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ord)
+	case "PUT /order/bulk":
+		bulkOrders(w, params, amend)
+	case "DELETE /order":
+		orders, err := srv.book.Cancel(account.ID,
+			splitIDs(params.Get("orderID")), splitIDs(params.Get("clOrdID")),
+			params.Get("text"))
+		if err != nil {
+			orderError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, orders)
+	case "DELETE /order/all":
+		filter, err := parseFilter(params.Get("filter"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSON(w, http.StatusOK, srv.book.CancelAll(
+			account.ID, params.Get("symbol"), filter, params.Get("text")))
+	case "POST /order/cancelAllAfter":
+		timeout, err := strconv.ParseFloat(params.Get("timeout"), 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid timeout.")
+			return
+		}
+
+		after := time.Duration(timeout) * time.Millisecond
+
+		srv.book.CancelAfter(account.ID, after)
+
+		now := time.Now().UTC()
+		result := map[string]interface{}{"now": now}
+		if after > 0 {
+			result["cancelTime"] = now.Add(after)
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
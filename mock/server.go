@@ -0,0 +1,274 @@
+// Package mock provides an in-process fake NGE server for offline tests,
+// covering key exchange, login, default api key, instrument & order endpoints.
+package mock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// DefaultBaseURI base uri of REST api served by fake server
+const DefaultBaseURI = "/api/v1"
+
+// fault error injected to requests matching method & path
+type fault struct {
+	method string
+	path   string
+	status int
+	times  int
+}
+
+func (f *fault) match(method, path string) bool {
+	return (f.method == "" || strings.EqualFold(f.method, method)) &&
+		(f.path == "" || f.path == path)
+}
+
+// Server fake NGE server, all states are kept in memory
+type Server struct {
+	*httptest.Server
+
+	exchangeKey *rsa.PrivateKey
+
+	lock      sync.Mutex
+	accounts  map[string]*Account
+	keys      map[string]*Account
+	tokens    map[string]*Account
+	faults    []*fault
+	latency   time.Duration
+	requests  map[string]int
+	accountID float32
+
+	book        *orderBook
+	instruments map[string]*ngerest.Instrument
+}
+
+// NewServer create & start fake server, server should be closed after use
+func NewServer() *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		panic(err)
+	}
+
+	srv := Server{
+		exchangeKey: key,
+		accounts:    make(map[string]*Account),
+		keys:        make(map[string]*Account),
+		tokens:      make(map[string]*Account),
+		requests:    make(map[string]int),
+		accountID:   100000,
+		book:        newOrderBook(),
+		instruments: defaultInstruments(),
+	}
+
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+
+	return &srv
+}
+
+// Host get host address of server without port
+func (srv *Server) Host() string {
+	host, _, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	return host
+}
+
+// Port get listening port of server
+func (srv *Server) Port() int {
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	value, _ := strconv.Atoi(port)
+
+	return value
+}
+
+// InjectError make next times requests matching method & path failed
+// with status, empty method or path matches all, path is relative to
+// base uri, times less than 1 means failing forever.
+func (srv *Server) InjectError(method, path string, status, times int) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	srv.faults = append(srv.faults, &fault{
+		method: method, path: path, status: status, times: times})
+}
+
+// ClearErrors remove all injected errors
+func (srv *Server) ClearErrors() {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	srv.faults = nil
+}
+
+// SetLatency set delay before each request handled
+func (srv *Server) SetLatency(latency time.Duration) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	srv.latency = latency
+}
+
+// RequestCount get count of requests handled by method & path,
+// path is relative to base uri.
+func (srv *Server) RequestCount(method, path string) int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	return srv.requests[strings.ToUpper(method)+" "+path]
+}
+
+// Orders get orders of symbol in book, empty symbol means all symbols
+func (srv *Server) Orders(symbol string) []ngerest.Order {
+	return srv.book.Orders(0, symbol)
+}
+
+// takeFault get status of injected error matching request, 0 means none
+func (srv *Server) takeFault(method, path string) int {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	for idx, f := range srv.faults {
+		if !f.match(method, path) {
+			continue
+		}
+
+		if f.times > 0 {
+			if f.times--; f.times == 0 {
+				srv.faults = append(srv.faults[:idx], srv.faults[idx+1:]...)
+			}
+		}
+
+		return f.status
+	}
+
+	return 0
+}
+
+func (srv *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, DefaultBaseURI)
+
+	srv.lock.Lock()
+	latency := srv.latency
+	srv.requests[r.Method+" "+path]++
+	srv.lock.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	if status := srv.takeFault(r.Method, path); status != 0 {
+		writeError(w, status, "Injected error.")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch path {
+	case "/user/getPublicKey":
+		srv.handlePublicKey(w, r)
+	case "/user/login":
+		srv.handleLogin(w, r, body)
+	case "/user/getUserSysApiKey":
+		srv.handleDefaultKey(w, r, body)
+	case "/user/logout":
+		srv.handleLogout(w, r)
+	case "/instrument", "/instrument/active", "/instrument/activeAndIndices",
+		"/instrument/indices", "/instrument/compositeIndex":
+		srv.handleInstrument(w, r, path)
+	case "/order", "/order/bulk", "/order/all", "/order/cancelAllAfter":
+		account, err := srv.authenticate(r, body)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		srv.handleOrder(w, r, path, account, body)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	name := "HTTPError"
+	if status < 500 && status != http.StatusTooManyRequests {
+		name = "ValidationError"
+	}
+
+	writeJSON(w, status, ngerest.ModelError{
+		Error: &ngerest.ErrorError{Message: message, Name: name}})
+}
+
+// parseParams parse request params from json object, urlencoded or
+// multipart form body, and query string, as ngerest client sends form
+// params in multipart body with json content type.
+func parseParams(r *http.Request, body []byte) (url.Values, error) {
+	params := r.URL.Query()
+
+	body = bytes.TrimSpace(body)
+
+	switch {
+	case len(body) == 0:
+	case body[0] == '{':
+		values := make(map[string]interface{})
+
+		if err := json.Unmarshal(body, &values); err != nil {
+			return nil, err
+		}
+
+		for name, value := range values {
+			params.Set(name, fmt.Sprint(value))
+		}
+	case bytes.HasPrefix(body, []byte("--")):
+		boundary := string(bytes.TrimPrefix(
+			bytes.SplitN(body, []byte("\r\n"), 2)[0], []byte("--")))
+
+		form, err := multipart.NewReader(
+			bytes.NewReader(body), boundary).ReadForm(1 << 20)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, values := range form.Value {
+			for _, value := range values {
+				params.Add(name, value)
+			}
+		}
+	default:
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range values {
+			params[name] = append(params[name], value...)
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,274 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+	"github.com/frozenpine/pkcs8"
+)
+
+func newClient(srv *Server) *ngerest.APIClient {
+	cfg := ngerest.NewConfiguration()
+	client := ngerest.NewAPIClient(cfg)
+	client.ChangeBasePath(srv.URL + DefaultBaseURI)
+
+	return client
+}
+
+func login(t *testing.T, srv *Server, client *ngerest.APIClient,
+	identity, password string) context.Context {
+	pubKey, _, err := client.KeyExchange.GetPublicKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth, _, err := client.User.UserLogin(context.Background(),
+		map[string]string{
+			"email":    identity,
+			"password": pubKey.Encrypt(password),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, _, err := client.User.UserGetDefaultAPIKey(
+		auth, pkcs8.GeneratePriveKey(1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return context.WithValue(context.Background(), ngerest.ContextAPIKey,
+		ngerest.APIKey{Key: key.APIKey, Secret: key.APISecret})
+}
+
+func TestLogin(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	account := srv.AddAccount("a@b.com", "password")
+	client := newClient(srv)
+
+	pubKey, _, err := client.KeyExchange.GetPublicKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, rsp, err := client.User.UserLogin(context.Background(),
+		map[string]string{
+			"email":    "a@b.com",
+			"password": pubKey.Encrypt("wrong"),
+		})
+	if err == nil || rsp.StatusCode != http.StatusUnauthorized {
+		t.Error("login with wrong password should fail:", err)
+	}
+
+	auth := login(t, srv, client, "a@b.com", "password")
+
+	if key := auth.Value(ngerest.ContextAPIKey).(ngerest.APIKey); key.Key != account.Key ||
+		key.Secret != account.Secret {
+		t.Error("default api key mismatch:", key)
+	}
+}
+
+func TestOrderMatch(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddAccount("maker@b.com", "maker")
+	srv.AddAccount("taker@b.com", "taker")
+
+	client := newClient(srv)
+	maker := login(t, srv, client, "maker@b.com", "maker")
+	taker := login(t, srv, client, "taker@b.com", "taker")
+
+	sell, _, err := client.Order.OrderNew(maker, "XBTUSD", &ngerest.OrderNewOpts{
+		Side:     optional.NewString("Sell"),
+		OrderQty: optional.NewFloat32(10),
+		Price:    optional.NewFloat64(100),
+		ClOrdID:  optional.NewString("maker-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sell.OrdStatus != statusNew || sell.LeavesQty != 10 {
+		t.Error("resting order mismatch:", sell)
+	}
+
+	buy, _, err := client.Order.OrderNew(taker, "XBTUSD", &ngerest.OrderNewOpts{
+		Side:     optional.NewString("Buy"),
+		OrderQty: optional.NewFloat32(4),
+		Price:    optional.NewFloat64(101),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buy.OrdStatus != statusFilled || buy.AvgPx != 100 {
+		t.Error("crossed order should be filled at maker's price:", buy)
+	}
+
+	amended, _, err := client.Order.OrderAmend(maker, &ngerest.OrderAmendOpts{
+		OrigClOrdID: optional.NewString("maker-1"),
+		Price:       optional.NewFloat64(102),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if amended.OrdStatus != statusPartial || amended.LeavesQty != 6 ||
+		amended.Price != 102 {
+		t.Error("amended order mismatch:", amended)
+	}
+
+	if _, rsp, err := client.Order.OrderCancel(taker, &ngerest.OrderCancelOpts{
+		OrderID: optional.NewString(sell.OrderID),
+	}); err == nil || rsp.StatusCode != http.StatusNotFound {
+		t.Error("order of other account should not be canceled:", err)
+	}
+
+	canceled, _, err := client.Order.OrderCancelAll(
+		maker, &ngerest.OrderCancelAllOpts{Symbol: optional.NewString("XBTUSD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(canceled) != 1 || canceled[0].OrdStatus != statusCanceled {
+		t.Error("cancel all result mismatch:", canceled)
+	}
+
+	orders, _, err := client.Order.OrderGetOrders(
+		taker, &ngerest.OrderGetOrdersOpts{Symbol: optional.NewString("XBTUSD")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(orders) != 1 || orders[0].OrderID != buy.OrderID {
+		t.Error("orders should be queried by account:", orders)
+	}
+
+	orders, _, err = client.Order.OrderGetOrders(
+		maker, &ngerest.OrderGetOrdersOpts{
+			Filter: optional.NewString(
+				`{"clOrdID":["maker-0","maker-1"]}`),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(orders) != 1 || orders[0].OrderID != sell.OrderID {
+		t.Error("orders should be filtered by any of values:", orders)
+	}
+
+	if len(srv.Orders("")) != 2 {
+		t.Error("book should contain all orders:", srv.Orders(""))
+	}
+}
+
+func TestSignature(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddAccount("a@b.com", "password")
+
+	client := newClient(srv)
+	auth := login(t, srv, client, "a@b.com", "password")
+
+	key := auth.Value(ngerest.ContextAPIKey).(ngerest.APIKey)
+	key.Secret = "invalid"
+
+	_, rsp, err := client.Order.OrderGetOrders(context.WithValue(
+		context.Background(), ngerest.ContextAPIKey, key), nil)
+	if err == nil || rsp.StatusCode != http.StatusUnauthorized {
+		t.Error("request with invalid signature should be rejected:", err)
+	}
+
+	srv.ExpireKey("a@b.com")
+
+	if _, rsp, err = client.Order.OrderGetOrders(auth, nil); err == nil ||
+		rsp.StatusCode != http.StatusUnauthorized {
+		t.Error("request with expired key should be rejected:", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddAccount("a@b.com", "password")
+
+	client := newClient(srv)
+	auth := login(t, srv, client, "a@b.com", "password")
+
+	srv.InjectError("GET", "/order", http.StatusServiceUnavailable, 1)
+
+	if _, rsp, err := client.Order.OrderGetOrders(auth, nil); err == nil ||
+		rsp.StatusCode != http.StatusServiceUnavailable {
+		t.Error("injected error should be returned:", err)
+	}
+
+	if _, _, err := client.Order.OrderGetOrders(auth, nil); err != nil {
+		t.Error("injected error should be consumed:", err)
+	}
+
+	if srv.RequestCount("GET", "/order") != 2 {
+		t.Error("request count mismatch:", srv.RequestCount("GET", "/order"))
+	}
+
+	srv.SetLatency(100 * time.Millisecond)
+
+	start := time.Now()
+	if _, _, err := client.Order.OrderGetOrders(auth, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("request should be delayed by latency")
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := newClient(srv)
+
+	srv.AddInstrument(ngerest.Instrument{
+		Symbol: "XBTZ19", State: "Settled", TickSize: 0.5})
+
+	active, _, err := client.Instrument.InstrumentGetActive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(active) != 1 || active[0].Symbol != DefaultSymbol ||
+		active[0].TickSize != 0.5 {
+		t.Error("active instruments mismatch:", active)
+	}
+
+	indices, _, err := client.Instrument.InstrumentGetIndices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(indices) != 1 || indices[0].Symbol != DefaultIndex {
+		t.Error("indices mismatch:", indices)
+	}
+
+	all, _, err := client.Instrument.InstrumentGet(context.Background(),
+		&ngerest.InstrumentGetOpts{
+			Start: optional.NewFloat32(1),
+			Count: optional.NewFloat32(1),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) != 1 || all[0].Symbol != DefaultSymbol {
+		t.Error("instrument page mismatch:", all)
+	}
+}
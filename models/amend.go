@@ -0,0 +1,118 @@
+package models
+
+import (
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+)
+
+// Amendment order amendment, either OrderID or OrigClOrdID must be specified
+type Amendment struct {
+	OrderID        string  `csv:"orderID,omitempty" json:"orderID,omitempty"`
+	OrigClOrdID    string  `csv:"origClOrdID,omitempty" json:"origClOrdID,omitempty"`
+	ClOrdID        string  `csv:"clOrdID,omitempty" json:"clOrdID,omitempty"`
+	OrderQty       float32 `csv:"orderQty,omitempty" json:"orderQty,omitempty"`
+	LeavesQty      float32 `csv:"leavesQty,omitempty" json:"leavesQty,omitempty"`
+	Price          float64 `csv:"price,omitempty" json:"price,omitempty"`
+	StopPx         float64 `csv:"stopPx,omitempty" json:"stopPx,omitempty"`
+	PegOffsetValue float64 `csv:"pegOffsetValue,omitempty" json:"pegOffsetValue,omitempty"`
+	Text           string  `csv:"text,omitempty" json:"text,omitempty"`
+}
+
+// Validate check amendment's completion, zero value means not changed
+func (amend *Amendment) Validate() error {
+	if amend.OrderID == "" && amend.OrigClOrdID == "" {
+		return common.ErrAmendTarget
+	}
+
+	if amend.OrderQty == 0 && amend.LeavesQty == 0 && amend.Price == 0 &&
+		amend.StopPx == 0 && amend.PegOffsetValue == 0 {
+		return common.ErrAmendNothing
+	}
+
+	if amend.Price != 0 {
+		if err := common.CheckPrice(amend.Price); err != nil {
+			return err
+		}
+	}
+
+	if amend.OrderQty != 0 {
+		if err := common.CheckQuantity(int64(amend.OrderQty)); err != nil {
+			return err
+		}
+	}
+
+	if amend.LeavesQty != 0 {
+		if err := common.CheckQuantity(int64(amend.LeavesQty)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AmendOpts convert amendment to ngerest amend options
+func (amend *Amendment) AmendOpts() *ngerest.OrderAmendOpts {
+	opts := ngerest.OrderAmendOpts{}
+
+	if amend.OrderID != "" {
+		opts.OrderID = optional.NewString(amend.OrderID)
+	}
+	if amend.OrigClOrdID != "" {
+		opts.OrigClOrdID = optional.NewString(amend.OrigClOrdID)
+	}
+	if amend.ClOrdID != "" {
+		opts.ClOrdID = optional.NewString(amend.ClOrdID)
+	}
+	if amend.OrderQty != 0 {
+		opts.OrderQty = optional.NewFloat32(amend.OrderQty)
+	}
+	if amend.LeavesQty != 0 {
+		opts.LeavesQty = optional.NewFloat32(amend.LeavesQty)
+	}
+	if amend.Price != 0 {
+		opts.Price = optional.NewFloat64(amend.Price)
+	}
+	if amend.StopPx != 0 {
+		opts.StopPx = optional.NewFloat64(amend.StopPx)
+	}
+	if amend.PegOffsetValue != 0 {
+		opts.PegOffsetValue = optional.NewFloat64(amend.PegOffsetValue)
+	}
+	if amend.Text != "" {
+		opts.Text = optional.NewString(amend.Text)
+	}
+
+	return &opts
+}
+
+// AmendRecord amendment record read from amendment source file
+type AmendRecord struct {
+	Line      int
+	Amendment *Amendment
+	Err       error
+}
+
+// ReadAmendFile read amendments from source file in stream,
+// file format is the same as ReadOrderFile.
+func ReadAmendFile(path string) (<-chan *AmendRecord, error) {
+	records := make(chan *AmendRecord)
+
+	handler := func(line int, value interface{}, err error) {
+		record := AmendRecord{Line: line, Err: err}
+
+		if err == nil {
+			record.Amendment = value.(*Amendment)
+		}
+
+		records <- &record
+	}
+
+	if err := readSourceFile(
+		path, &Amendment{}, handler, func() { close(records) }); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+func TestAmendmentValidate(t *testing.T) {
+	cases := []struct {
+		amend Amendment
+		err   error
+	}{
+		{Amendment{Price: 100}, common.ErrAmendTarget},
+		{Amendment{OrderID: "abc"}, common.ErrAmendNothing},
+		{Amendment{OrderID: "abc", Price: -1}, common.ErrPrice},
+		{Amendment{OrigClOrdID: "abc", OrderQty: 10}, nil},
+	}
+
+	for idx, c := range cases {
+		if err := c.amend.Validate(); err != c.err {
+			t.Fatalf("case[%d] expect: %v, got: %v", idx, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antihax/optional"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// Permissions api key permissions
+type Permissions []string
+
+func (p Permissions) String() string {
+	return strings.Join(p, ",")
+}
+
+// MarshalCSV marshal permissions to csv as comma separated string
+func (p Permissions) MarshalCSV() (string, error) {
+	return p.String(), nil
+}
+
+// APIKeyInfo api key info with an extra Identity column like account models,
+// permissions in ngerest.APIKeyInfo can't be decoded as they are generated
+// as []XAny, so api keys are listed by GetAPIKeys directly.
+type APIKeyInfo struct {
+	Identity    string      `csv:"identity" json:"identity"`
+	ID          string      `csv:"id" json:"id"`
+	Secret      string      `csv:"secret,omitempty" json:"secret,omitempty"`
+	Name        string      `csv:"name" json:"name"`
+	Nonce       float64     `csv:"nonce" json:"nonce"`
+	Cidr        string      `csv:"cidr,omitempty" json:"cidr,omitempty"`
+	Permissions Permissions `csv:"permissions" json:"permissions"`
+	Enabled     bool        `csv:"enabled" json:"enabled"`
+	UserID      float64     `csv:"userId" json:"userId"`
+	Created     time.Time   `csv:"created,omitempty" json:"created,omitempty"`
+}
+
+// Authentication convert api key to auth info in auth file format,
+// identity is left empty so key can be used as auth's identity.
+func (key *APIKeyInfo) Authentication() *Authentication {
+	return &Authentication{
+		APIKey: APIKey{
+			Key:    key.ID,
+			Secret: key.Secret,
+		},
+	}
+}
+
+// ConvertAPIKey convert ngerest api key info, permissions in ngerest model
+// are lost in decoding, so they should be filled by caller.
+func ConvertAPIKey(id string, ori *ngerest.APIKeyInfo) *APIKeyInfo {
+	return &APIKeyInfo{
+		Identity: id,
+		ID:       ori.ID,
+		Secret:   ori.Secret,
+		Name:     ori.Name,
+		Nonce:    float64(ori.Nonce),
+		Cidr:     ori.Cidr,
+		Enabled:  ori.Enabled,
+		UserID:   float64(ori.UserID),
+		Created:  ori.Created,
+	}
+}
+
+// NewAPIKeyOpts make options for creating api key,
+// permissions are sent in json array format.
+func NewAPIKeyOpts(name, cidr string,
+	permissions Permissions, enabled bool) *ngerest.APIKeyNewOpts {
+	opts := ngerest.APIKeyNewOpts{
+		Enabled: optional.NewBool(enabled),
+	}
+
+	if name != "" {
+		opts.Name = optional.NewString(name)
+	}
+
+	if cidr != "" {
+		opts.Cidr = optional.NewString(cidr)
+	}
+
+	if len(permissions) > 0 {
+		perms, _ := json.Marshal([]string(permissions))
+
+		opts.Permissions = optional.NewString(string(perms))
+	}
+
+	return &opts
+}
+
+// GetAPIKeys get all api keys of account authenticated in ctx,
+// secrets are omitted as they should only be shown once when created.
+func GetAPIKeys(ctx context.Context, basePath, id string,
+	reverse bool) ([]*APIKeyInfo, error) {
+	query := url.Values{}
+	query.Set("reverse", strconv.FormatBool(reverse))
+
+	var keys []*APIKeyInfo
+
+	if err := signedGet(ctx, basePath, "/apiKey", query, &keys); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		key.Identity = id
+		key.Secret = ""
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/frozenpine/ngerest"
+	"github.com/gocarina/gocsv"
+)
+
+func TestAPIKeyOpts(t *testing.T) {
+	opts := NewAPIKeyOpts("bot", "", Permissions{"order", "orderCancel"}, false)
+
+	if opts.Name.Value() != "bot" || opts.Cidr.IsSet() || opts.Enabled.Value() {
+		t.Error("options mismatch:", opts)
+	}
+
+	if perms := opts.Permissions.Value(); perms != `["order","orderCancel"]` {
+		t.Error("permissions should be json array:", perms)
+	}
+
+	if opts = NewAPIKeyOpts("", "", nil, true); opts.Permissions.IsSet() {
+		t.Error("empty permissions should not be set")
+	}
+
+	keys := []*APIKeyInfo{{
+		Identity: "test", ID: "K1", Name: "bot", Enabled: true,
+		Permissions: Permissions{"order", "orderCancel"},
+	}}
+
+	content, err := gocsv.MarshalString(&keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(content, `"order,orderCancel"`) {
+		t.Error("permissions should be marshaled as one column:", content)
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/apiKey" || r.Header.Get("api-key") != "KEY" ||
+				r.Header.Get("api-signature") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[{"id":"K1","secret":"S1","name":"bot",`+
+				`"permissions":["order"],"enabled":true,"userId":1}]`)
+		}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), ngerest.ContextAPIKey,
+		ngerest.APIKey{Key: "KEY", Secret: "SECRET"})
+
+	keys, err := GetAPIKeys(ctx, server.URL, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0].Identity != "test" ||
+		keys[0].Permissions.String() != "order" {
+		t.Error("api keys mismatch:", keys)
+	}
+
+	if keys[0].Secret != "" {
+		t.Error("secret should be omitted in listing")
+	}
+
+	if _, err = GetAPIKeys(context.Background(),
+		server.URL, "test", false); err == nil {
+		t.Error("unauthorized request should fail")
+	}
+}
+
+func TestWriteAuthFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	authFile := filepath.Join(dir, "auths.csv")
+
+	for _, key := range []*APIKeyInfo{
+		{ID: "K1", Secret: "S1"}, {ID: "K2", Secret: "S2"},
+	} {
+		if err = WriteAuthFile(authFile,
+			[]*Authentication{key.Authentication()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := os.Stat(authFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Error("auth file should only be accessed by owner:", info.Mode())
+	}
+
+	cache := NewAuthCache(context.Background(), &ClientHub{})
+
+	if err = cache.readAuthFile(authFile); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, _ := cache.AllAuthIDs(); len(ids) != 2 || ids[0] != "K1" || ids[1] != "K2" {
+		t.Error("auth ids mismatch:", ids)
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"net/http"
 	"sync"
 
 	"github.com/frozenpine/ngecli/common"
@@ -15,6 +16,9 @@ import (
 type ClientHub struct {
 	clientsMap map[string]*ngerest.APIClient
 	initFlag   sync.Once
+
+	refresh     AuthRefresher
+	refreshLock sync.RWMutex
 }
 
 func (hub *ClientHub) init() {
@@ -36,6 +40,10 @@ func (hub *ClientHub) GetClient(host string) (*ngerest.APIClient, error) {
 	}
 
 	cfg := ngerest.NewConfiguration()
+	cfg.HTTPClient = &http.Client{
+		Transport: &authTransport{base: apiTransport(), hub: hub},
+	}
+
 	client := ngerest.NewAPIClient(cfg)
 
 	hostURL := viper.GetString("scheme") + "://" + host
@@ -48,3 +56,19 @@ func (hub *ClientHub) GetClient(host string) (*ngerest.APIClient, error) {
 
 	return client, nil
 }
+
+// SetAuthRefresher set func to refresh api key rejected by server,
+// requests from hub's clients will be retried once with new api key.
+func (hub *ClientHub) SetAuthRefresher(refresh AuthRefresher) {
+	hub.refreshLock.Lock()
+	defer hub.refreshLock.Unlock()
+
+	hub.refresh = refresh
+}
+
+func (hub *ClientHub) refresher() AuthRefresher {
+	hub.refreshLock.RLock()
+	defer hub.refreshLock.RUnlock()
+
+	return hub.refresh
+}
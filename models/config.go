@@ -0,0 +1,479 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/viper"
+)
+
+const (
+	// EnvironmentsKey config key of named environments
+	EnvironmentsKey = "envs"
+	// DefaultEnvKey config key of environment used if --env not specified
+	DefaultEnvKey = "default-env"
+	// LogLevelsKey config key of named loggers' levels
+	LogLevelsKey = "log.levels"
+	// LogLevelSep separator of sub command names in log levels' keys,
+	// as periods are used for nested config keys.
+	LogLevelSep = "/"
+)
+
+var (
+	hostPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+	symbolPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	envPattern    = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	loggerPattern = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*$`)
+)
+
+// settingParser parse & validate setting value from string
+type settingParser func(value string) (interface{}, error)
+
+func parseScheme(value string) (interface{}, error) {
+	if value != "http" && value != "https" {
+		return nil, common.ErrScheme
+	}
+
+	return value, nil
+}
+
+func parseHost(value string) (interface{}, error) {
+	if !hostPattern.MatchString(value) {
+		return nil, common.ErrHost
+	}
+
+	return value, nil
+}
+
+func parsePort(value string) (interface{}, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, common.ErrPort
+	}
+
+	return port, nil
+}
+
+func parseURI(value string) (interface{}, error) {
+	if !strings.HasPrefix(value, "/") || strings.ContainsAny(value, " ?#") {
+		return nil, common.ErrURI
+	}
+
+	return value, nil
+}
+
+func parseSymbol(value string) (interface{}, error) {
+	if !symbolPattern.MatchString(value) {
+		return nil, common.ErrSymbol
+	}
+
+	return value, nil
+}
+
+func parseOutput(value string) (interface{}, error) {
+	if !CheckOutputFormat(value) {
+		return nil, common.ErrOutputFormat
+	}
+
+	return value, nil
+}
+
+func parseName(value string) (interface{}, error) {
+	if value != "" && !envPattern.MatchString(value) {
+		return nil, common.ErrConfigValue
+	}
+
+	return value, nil
+}
+
+func parseCount(value string) (interface{}, error) {
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, common.ErrConfigValue
+	}
+
+	return count, nil
+}
+
+// parseDuration parse duration string, integer is treated as nanoseconds
+// same as durations written by viper.
+func parseDuration(value string) (interface{}, error) {
+	if ns, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if ns <= 0 {
+			return nil, common.ErrConfigValue
+		}
+
+		return ns, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return nil, common.ErrConfigValue
+	}
+
+	return value, nil
+}
+
+func parseLevel(value string) (interface{}, error) {
+	if _, err := logger.ParseLevel(value); err != nil {
+		return nil, common.ErrConfigValue
+	}
+
+	return strings.ToLower(value), nil
+}
+
+func parseLogFormat(value string) (interface{}, error) {
+	if !logger.CheckFormat(value) {
+		return nil, common.ErrConfigValue
+	}
+
+	return value, nil
+}
+
+func parseBool(value string) (interface{}, error) {
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, common.ErrConfigValue
+	}
+
+	return result, nil
+}
+
+func parseTimeZone(value string) (interface{}, error) {
+	if _, err := LoadTimeZone(value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func parsePath(value string) (interface{}, error) {
+	return value, nil
+}
+
+// envSchema settings can be overridden by environment
+var envSchema = map[string]settingParser{
+	"scheme":   parseScheme,
+	"host":     parseHost,
+	"port":     parsePort,
+	"base-uri": parseURI,
+	"ws-uri":   parseURI,
+	"symbol":   parseSymbol,
+	"profile":  parseName,
+}
+
+// configSchema top level settings in config file
+var configSchema = map[string]settingParser{
+	"scheme":            parseScheme,
+	"host":              parseHost,
+	"port":              parsePort,
+	"base-uri":          parseURI,
+	"ws-uri":            parseURI,
+	"symbol":            parseSymbol,
+	"output":            parseOutput,
+	"tz":                parseTimeZone,
+	"verbose":           parseCount,
+	"connect-timeout":   parseDuration,
+	"read-timeout":      parseDuration,
+	"retry-backoff":     parseDuration,
+	"max-retries":       parseCount,
+	"ratelimit-reserve": parseCount,
+	DefaultEnvKey:       parseName,
+	"log.level":         parseLevel,
+	"log.format":        parseLogFormat,
+	"log.color":         parseBool,
+	"log.file":          parsePath,
+	"log.file-format":   parseLogFormat,
+	"log.max-size":      parseCount,
+	"log.max-age":       parseCount,
+	"log.max-backups":   parseCount,
+	"log.compress":      parseBool,
+}
+
+// ParseSetting validate setting value by config key & convert it to
+// value saved in config file, environment settings are keyed as
+// "envs.<name>.<key>", levels of named loggers are keyed as
+// "log.levels.<name>", with sub command names joined by LogLevelSep.
+func ParseSetting(key, value string) (interface{}, error) {
+	key = strings.ToLower(key)
+
+	parser, exist := configSchema[key]
+
+	switch parts := strings.Split(key, "."); {
+	case parts[0] == EnvironmentsKey:
+		if len(parts) != 3 || !envPattern.MatchString(parts[1]) {
+			return nil, common.NewError(
+				common.KindValidation, key, common.ErrConfigKey)
+		}
+
+		parser, exist = envSchema[parts[2]]
+	case strings.HasPrefix(key, LogLevelsKey+"."):
+		name := strings.TrimPrefix(key, LogLevelsKey+".")
+
+		parser, exist = parseLevel, loggerPattern.MatchString(name)
+	}
+
+	if !exist {
+		return nil, common.NewError(
+			common.KindValidation, key, common.ErrConfigKey)
+	}
+
+	result, err := parser(value)
+
+	return result, common.NewError(common.KindValidation, key, err)
+}
+
+// ValidateConfig validate all settings in config file,
+// errors of all invalid settings will be returned.
+func ValidateConfig(settings map[string]interface{}) []error {
+	var errs []error
+
+	for _, key := range sortedKeys(settings) {
+		value := settings[key]
+
+		// nested settings are validated by dotted keys
+		if sub, ok := value.(map[string]interface{}); ok {
+			nested := make(map[string]interface{}, len(sub))
+
+			for name, value := range sub {
+				nested[key+"."+name] = value
+			}
+
+			errs = append(errs, ValidateConfig(nested)...)
+
+			continue
+		}
+
+		if _, err := ParseSetting(key, fmt.Sprint(value)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func sortedKeys(settings map[string]interface{}) []string {
+	keys := make([]string, 0, len(settings))
+
+	for key := range settings {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ConfigFile settings read from config file, which can be modified
+// without settings from flags & defaults mixed in.
+type ConfigFile struct {
+	path     string
+	settings map[string]interface{}
+}
+
+// LoadConfigFile read settings from config file
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	return &ConfigFile{path: path, settings: v.AllSettings()}, nil
+}
+
+// Path get config file path
+func (cfg *ConfigFile) Path() string {
+	return cfg.path
+}
+
+// Settings get all settings in config file
+func (cfg *ConfigFile) Settings() map[string]interface{} {
+	return cfg.settings
+}
+
+// parent get map containing the last part of dotted key,
+// maps in path will be created if create is true,
+// but settings in path will not be replaced by maps.
+func (cfg *ConfigFile) parent(key string, create bool) (
+	map[string]interface{}, string) {
+	parts := strings.Split(strings.ToLower(key), ".")
+	settings := cfg.settings
+
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := settings[part].(map[string]interface{})
+		if !ok {
+			if _, exist := settings[part]; exist || !create {
+				return nil, ""
+			}
+
+			sub = make(map[string]interface{})
+			settings[part] = sub
+		}
+
+		settings = sub
+	}
+
+	return settings, parts[len(parts)-1]
+}
+
+// Get get setting in config file by dotted key
+func (cfg *ConfigFile) Get(key string) (interface{}, bool) {
+	settings, name := cfg.parent(key, false)
+	if settings == nil {
+		return nil, false
+	}
+
+	value, exist := settings[name]
+
+	return value, exist
+}
+
+// Set validate & set setting by dotted key
+func (cfg *ConfigFile) Set(key, value string) error {
+	result, err := ParseSetting(key, value)
+	if err != nil {
+		return err
+	}
+
+	settings, name := cfg.parent(key, true)
+	if settings == nil {
+		return common.NewError(
+			common.KindValidation, key, common.ErrConfigKey)
+	}
+
+	settings[name] = result
+
+	return nil
+}
+
+// Unset remove setting by dotted key, false will be returned
+// if setting not found.
+func (cfg *ConfigFile) Unset(key string) bool {
+	settings, name := cfg.parent(key, false)
+	if settings == nil {
+		return false
+	}
+
+	if _, exist := settings[name]; !exist {
+		return false
+	}
+
+	delete(settings, name)
+
+	return true
+}
+
+// Environments get names of all environments in config file
+func (cfg *ConfigFile) Environments() []string {
+	envs, _ := cfg.settings[EnvironmentsKey].(map[string]interface{})
+
+	return sortedKeys(envs)
+}
+
+// Save write settings back to config file
+func (cfg *ConfigFile) Save() error {
+	v := viper.New()
+
+	for key, value := range cfg.settings {
+		v.Set(key, value)
+	}
+
+	if err := v.WriteConfigAs(cfg.path); err != nil {
+		return err
+	}
+
+	return os.Chmod(cfg.path, 0600)
+}
+
+// Environment named environment with its own host & auth profile,
+// empty settings are not overridden.
+type Environment struct {
+	Name    string `mapstructure:"-" csv:"name" json:"name"`
+	Scheme  string `mapstructure:"scheme" csv:"scheme" json:"scheme"`
+	Host    string `mapstructure:"host" csv:"host" json:"host"`
+	Port    int    `mapstructure:"port" csv:"port" json:"port"`
+	BaseURI string `mapstructure:"base-uri" csv:"base_uri" json:"base_uri"`
+	WsURI   string `mapstructure:"ws-uri" csv:"ws_uri" json:"ws_uri"`
+	Symbol  string `mapstructure:"symbol" csv:"symbol" json:"symbol"`
+	Profile string `mapstructure:"profile" csv:"profile" json:"profile"`
+	Default bool   `mapstructure:"-" csv:"default" json:"default"`
+}
+
+// Settings get non-empty settings overridden by environment
+func (env *Environment) Settings() map[string]interface{} {
+	settings := make(map[string]interface{})
+
+	for key, value := range map[string]string{
+		"scheme":   env.Scheme,
+		"host":     env.Host,
+		"base-uri": env.BaseURI,
+		"ws-uri":   env.WsURI,
+		"symbol":   env.Symbol,
+	} {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+
+	if env.Port > 0 {
+		settings["port"] = env.Port
+	}
+
+	return settings
+}
+
+// GetEnvironment get named environment from config, "default-env" will be
+// used if name is empty, nil will be returned if no environment specified.
+func GetEnvironment(v *viper.Viper, name string) (*Environment, error) {
+	if name == "" {
+		name = v.GetString(DefaultEnvKey)
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	key := EnvironmentsKey + "." + strings.ToLower(name)
+
+	if !v.IsSet(key) {
+		return nil, common.NewError(common.KindValidation, name,
+			common.ErrEnvNotFound)
+	}
+
+	env := Environment{Name: name}
+
+	if err := v.UnmarshalKey(key, &env); err != nil {
+		return nil, err
+	}
+
+	return &env, nil
+}
+
+// Environments get all environments in config sorted by name
+func Environments(v *viper.Viper) ([]*Environment, error) {
+	defaultEnv := v.GetString(DefaultEnvKey)
+
+	var envs []*Environment
+
+	for _, name := range sortedKeys(v.GetStringMap(EnvironmentsKey)) {
+		env, err := GetEnvironment(v, name)
+		if err != nil {
+			return nil, err
+		}
+
+		env.Default = name == defaultEnv
+		envs = append(envs, env)
+	}
+
+	return envs, nil
+}
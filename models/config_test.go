@@ -0,0 +1,155 @@
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/viper"
+)
+
+func TestParseSetting(t *testing.T) {
+	valid := map[string]string{
+		"scheme":               "http",
+		"host":                 "trade.example.com",
+		"port":                 "8080",
+		"base-uri":             "/api/v1",
+		"symbol":               "XBTUSD",
+		"connect-timeout":      "5s",
+		"read-timeout":         "30000000000",
+		"envs.dev.host":        "dev",
+		"envs.dev.profile":     "dev-accounts",
+		"log.level":            "debug",
+		"log.levels.order":     "warn",
+		"log.levels.order/new": "info",
+		"log.compress":         "false",
+	}
+
+	for key, value := range valid {
+		if _, err := ParseSetting(key, value); err != nil {
+			t.Error(key, "should be valid:", err)
+		}
+	}
+
+	invalid := map[string]string{
+		"scheme":               "ftp",
+		"host":                 "http://trade",
+		"port":                 "65536",
+		"base-uri":             "api/v1",
+		"symbol":               "",
+		"connect-timeout":      "-1s",
+		"unknown":              "value",
+		"envs.dev":             "dev",
+		"envs.dev.output":      "csv",
+		"log.level":            "loud",
+		"log.format":           "xml",
+		"log.levels.order.new": "info",
+		"log.levels./order":    "info",
+	}
+
+	for key, value := range invalid {
+		if _, err := ParseSetting(key, value); common.KindOf(err) != common.KindValidation {
+			t.Error(key, "should be invalid:", value)
+		}
+	}
+
+	if port, _ := ParseSetting("port", "80"); port != 80 {
+		t.Error("port should be parsed as int:", port)
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+
+	if err = ioutil.WriteFile(
+		path, []byte("host: trade\nport: 80\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = cfg.Set("port", "abc"); err == nil {
+		t.Error("invalid port should not be set")
+	}
+
+	for key, value := range map[string]string{
+		"envs.dev.host":    "dev",
+		"envs.dev.port":    "8080",
+		"envs.dev.profile": "dev-accounts",
+		"envs.prod.host":   "prod",
+		DefaultEnvKey:      "dev",
+	} {
+		if err = cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for key, value := range map[string]string{
+		"log.levels.order":     "debug",
+		"log.levels.order/new": "info",
+	} {
+		if err = cfg.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scalar := ConfigFile{settings: map[string]interface{}{
+		"log": map[string]interface{}{"levels": "debug"}}}
+
+	if err = scalar.Set("log.levels.order", "info"); err == nil {
+		t.Error("setting should not be replaced by map")
+	}
+
+	if level, _ := cfg.Get("log.levels.order"); level != "debug" {
+		t.Error("level of parent command should be kept:", level)
+	}
+
+	if !cfg.Unset("envs.prod") || cfg.Unset("envs.staging") {
+		t.Error("unset result mismatch")
+	}
+
+	if errs := ValidateConfig(cfg.Settings()); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	if err = cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err = v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := GetEnvironment(v, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if env == nil || env.Name != "dev" || env.Host != "dev" ||
+		env.Port != 8080 || env.Profile != "dev-accounts" {
+		t.Error("default environment mismatch:", env)
+	}
+
+	if settings := env.Settings(); len(settings) != 2 {
+		t.Error("only host & port should be overridden:", settings)
+	}
+
+	if _, err = GetEnvironment(v, "prod"); common.KindOf(err) != common.KindValidation {
+		t.Error("removed environment should not be found:", err)
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestAPIError(t *testing.T) {
+	var status int
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error":{"message":"Invalid symbol.",`+
+				`"name":"HTTPError"}}`)
+		}))
+	defer server.Close()
+
+	client := ngerest.NewAPIClient(ngerest.NewConfiguration())
+	client.ChangeBasePath(server.URL)
+
+	for code, kind := range map[int]common.ErrorKind{
+		http.StatusBadRequest:      common.KindRejected,
+		http.StatusUnauthorized:    common.KindAuth,
+		http.StatusForbidden:       common.KindAuth,
+		http.StatusTooManyRequests: common.KindRateLimit,
+	} {
+		status = code
+
+		_, rsp, err := client.Position.PositionGet(context.Background(), nil)
+
+		err = common.NewAPIError("get position", err, rsp)
+
+		apiErr, ok := err.(*common.APIError)
+		if !ok {
+			t.Fatal("error should be api error:", err)
+		}
+
+		if apiErr.Status != code || apiErr.Name != "HTTPError" ||
+			apiErr.Message != "Invalid symbol." ||
+			apiErr.Method != "GET" || apiErr.Path != "/position" {
+			t.Error("api error mismatch:", apiErr)
+		}
+
+		if common.KindOf(err) != kind ||
+			common.ExitCodeOf(err) != kind.ExitCode() {
+			t.Error("api error kind mismatch:", code, common.KindOf(err))
+		}
+	}
+
+	_, _, err := client.Position.PositionGet(context.Background(), nil)
+	if _, ok := err.(ngerest.GenericSwaggerError); !ok ||
+		common.KindOf(err) != common.KindUnknown {
+		t.Error("unwrapped swagger error should be unknown kind:", err)
+	}
+
+	status = http.StatusUnauthorized
+
+	_, err = GetAPIKeys(context.Background(), server.URL, "test", false)
+	if common.KindOf(err) != common.KindAuth {
+		t.Error("direct request should return api error:", err)
+	}
+
+	if common.NewAPIError("", nil, nil) != nil {
+		t.Error("nil error should not be wrapped")
+	}
+
+	wrapped := common.NewError(common.KindUnknown, "check", common.ErrSide)
+	if common.ExitCodeOf(wrapped) != common.ExitValidation {
+		t.Error("kind of wrapped error should be used:", wrapped)
+	}
+
+	if common.ExitCodeOf(common.ErrTokenInsufficient) != common.ExitRateLimit {
+		t.Error("local rate limit should have its exit code")
+	}
+
+	if common.ExitCodeOf(nil) != common.ExitOK {
+		t.Error("nil error should exit ok")
+	}
+}
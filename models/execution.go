@@ -0,0 +1,211 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+const (
+	// ReconcileOK order's cumQty & avgPx match its fills
+	ReconcileOK = "OK"
+	// ReconcileMismatch order's cumQty or avgPx differs from its fills
+	ReconcileMismatch = "Mismatch"
+	// ReconcileMissing order of fills not found in order cache
+	ReconcileMissing = "Missing"
+
+	// DefaultPxTolerance default relative tolerance for avgPx comparison
+	DefaultPxTolerance = 1e-6
+)
+
+// Execution execution table
+type Execution struct {
+	ExecID                string    `csv:"execID" json:"execID"`
+	OrderID               string    `csv:"orderID,omitempty" json:"orderID,omitempty"`
+	ClOrdID               string    `csv:"clOrdID,omitempty" json:"clOrdID,omitempty"`
+	ClOrdLinkID           string    `csv:"clOrdLinkID,omitempty" json:"clOrdLinkID,omitempty"`
+	Account               float32   `csv:"account,omitempty" json:"account,omitempty"`
+	Symbol                string    `csv:"symbol,omitempty" json:"symbol,omitempty"`
+	Side                  OrderSide `csv:"side,omitempty" json:"side,omitempty"`
+	LastQty               float32   `csv:"lastQty,omitempty" json:"lastQty,omitempty"`
+	LastPx                float64   `csv:"lastPx,omitempty" json:"lastPx,omitempty"`
+	UnderlyingLastPx      float64   `csv:"underlyingLastPx,omitempty" json:"underlyingLastPx,omitempty"`
+	LastMkt               string    `csv:"lastMkt,omitempty" json:"lastMkt,omitempty"`
+	LastLiquidityInd      string    `csv:"lastLiquidityInd,omitempty" json:"lastLiquidityInd,omitempty"`
+	SimpleOrderQty        float64   `csv:"simpleOrderQty,omitempty" json:"simpleOrderQty,omitempty"`
+	OrderQty              float32   `csv:"orderQty,omitempty" json:"orderQty,omitempty"`
+	Price                 float64   `csv:"price,omitempty" json:"price,omitempty"`
+	DisplayQty            float32   `csv:"displayQty,omitempty" json:"displayQty,omitempty"`
+	StopPx                float64   `csv:"stopPx,omitempty" json:"stopPx,omitempty"`
+	PegOffsetValue        float64   `csv:"pegOffsetValue,omitempty" json:"pegOffsetValue,omitempty"`
+	PegPriceType          string    `csv:"pegPriceType,omitempty" json:"pegPriceType,omitempty"`
+	Currency              string    `csv:"currency,omitempty" json:"currency,omitempty"`
+	SettlCurrency         string    `csv:"settlCurrency,omitempty" json:"settlCurrency,omitempty"`
+	ExecType              string    `csv:"execType,omitempty" json:"execType,omitempty"`
+	OrdType               string    `csv:"ordType,omitempty" json:"ordType,omitempty"`
+	TimeInForce           string    `csv:"timeInForce,omitempty" json:"timeInForce,omitempty"`
+	ExecInst              string    `csv:"execInst,omitempty" json:"execInst,omitempty"`
+	ContingencyType       string    `csv:"contingencyType,omitempty" json:"contingencyType,omitempty"`
+	ExDestination         string    `csv:"exDestination,omitempty" json:"exDestination,omitempty"`
+	OrdStatus             string    `csv:"ordStatus,omitempty" json:"ordStatus,omitempty"`
+	Triggered             string    `csv:"triggered,omitempty" json:"triggered,omitempty"`
+	WorkingIndicator      bool      `csv:"workingIndicator,omitempty" json:"workingIndicator,omitempty"`
+	OrdRejReason          string    `csv:"ordRejReason,omitempty" json:"ordRejReason,omitempty"`
+	SimpleLeavesQty       float64   `csv:"simpleLeavesQty,omitempty" json:"simpleLeavesQty,omitempty"`
+	LeavesQty             float32   `csv:"leavesQty,omitempty" json:"leavesQty,omitempty"`
+	SimpleCumQty          float64   `csv:"simpleCumQty,omitempty" json:"simpleCumQty,omitempty"`
+	CumQty                float32   `csv:"cumQty,omitempty" json:"cumQty,omitempty"`
+	AvgPx                 float64   `csv:"avgPx,omitempty" json:"avgPx,omitempty"`
+	Commission            float64   `csv:"commission,omitempty" json:"commission,omitempty"`
+	TradePublishIndicator string    `csv:"tradePublishIndicator,omitempty" json:"tradePublishIndicator,omitempty"`
+	MultiLegReportingType string    `csv:"multiLegReportingType,omitempty" json:"multiLegReportingType,omitempty"`
+	Text                  string    `csv:"text,omitempty" json:"text,omitempty"`
+	TrdMatchID            string    `csv:"trdMatchID,omitempty" json:"trdMatchID,omitempty"`
+	ExecCost              float32   `csv:"execCost,omitempty" json:"execCost,omitempty"`
+	ExecComm              float32   `csv:"execComm,omitempty" json:"execComm,omitempty"`
+	HomeNotional          float64   `csv:"homeNotional,omitempty" json:"homeNotional,omitempty"`
+	ForeignNotional       float64   `csv:"foreignNotional,omitempty" json:"foreignNotional,omitempty"`
+	TransactTime          time.Time `csv:"transactTime,omitempty" json:"transactTime,omitempty"`
+	Timestamp             time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+}
+
+// IsFill check if execution is a trade fill
+func (exec *Execution) IsFill() bool {
+	return exec.ExecType == "Trade" && exec.LastQty != 0
+}
+
+// ConvertExecution convert ngerest.Execution structure to local Execution structure
+func ConvertExecution(ori *ngerest.Execution) (*Execution, error) {
+	var converted Execution
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// Reconciliation reconcile result of an order with its fills
+type Reconciliation struct {
+	OrderID    string  `csv:"orderID" json:"orderID"`
+	Symbol     string  `csv:"symbol" json:"symbol"`
+	OrdStatus  string  `csv:"ordStatus" json:"ordStatus"`
+	Fills      int     `csv:"fills" json:"fills"`
+	CumQty     float64 `csv:"cumQty" json:"cumQty"`
+	FillQty    float64 `csv:"fillQty" json:"fillQty"`
+	AvgPx      float64 `csv:"avgPx" json:"avgPx"`
+	FillAvgPx  float64 `csv:"fillAvgPx" json:"fillAvgPx"`
+	Status     string  `csv:"status" json:"status"`
+	fillCost   float64
+	fillInvert float64
+}
+
+// add fill to reconciliation, both linear & inverse average prices
+// are accumulated, as avgPx of inverse contract is harmonic mean.
+func (r *Reconciliation) add(exec *Execution) {
+	qty := math.Abs(float64(exec.LastQty))
+
+	r.Fills++
+	r.FillQty += qty
+	r.fillCost += qty * exec.LastPx
+
+	if exec.LastPx > 0 {
+		r.fillInvert += qty / exec.LastPx
+	}
+}
+
+func matchPx(px, expected, tolerance float64) bool {
+	if expected == 0 {
+		return px == 0
+	}
+
+	return math.Abs(px-expected)/expected <= tolerance
+}
+
+// check compare fills with order's cumQty & avgPx
+func (r *Reconciliation) check(ord *Order, tolerance float64) {
+	if ord == nil {
+		r.Status = ReconcileMissing
+		r.FillAvgPx = r.fillCost / r.FillQty
+		return
+	}
+
+	r.Symbol, r.OrdStatus = ord.Symbol, ord.OrdStatus
+	r.CumQty, r.AvgPx = float64(ord.CumQty), ord.AvgPx
+
+	linear := r.fillCost / r.FillQty
+	inverse := r.FillQty / r.fillInvert
+
+	r.FillAvgPx = linear
+
+	switch {
+	case r.FillQty != r.CumQty:
+		r.Status = ReconcileMismatch
+	case matchPx(linear, r.AvgPx, tolerance):
+		r.Status = ReconcileOK
+	case matchPx(inverse, r.AvgPx, tolerance):
+		r.FillAvgPx = inverse
+		r.Status = ReconcileOK
+	default:
+		r.Status = ReconcileMismatch
+	}
+}
+
+// CacheOrder put order into cache by OrderID for reconciliation,
+// exist order will be replaced.
+func (cache *OrderCache) CacheOrder(ord *Order) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.orderCache[ord.OrderID] = ord
+}
+
+// GetOrder get cached order by OrderID
+func (cache *OrderCache) GetOrder(orderID string) (*Order, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	ord, exist := cache.orderCache[orderID]
+
+	return ord, exist
+}
+
+// Reconcile join fills to cached orders by OrderID, and compare summed
+// fill qty & avgPx with order's CumQty & AvgPx, results are sorted by
+// OrderID, avgPx is compared in relative tolerance.
+func (cache *OrderCache) Reconcile(
+	execs []*Execution, tolerance float64) []*Reconciliation {
+	summary := make(map[string]*Reconciliation)
+
+	for _, exec := range execs {
+		if !exec.IsFill() || exec.OrderID == "" {
+			continue
+		}
+
+		r, exist := summary[exec.OrderID]
+		if !exist {
+			r = &Reconciliation{OrderID: exec.OrderID, Symbol: exec.Symbol}
+			summary[exec.OrderID] = r
+		}
+
+		r.add(exec)
+	}
+
+	results := make([]*Reconciliation, 0, len(summary))
+
+	for orderID, r := range summary {
+		ord, _ := cache.GetOrder(orderID)
+
+		r.check(ord, tolerance)
+
+		results = append(results, r)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].OrderID < results[j].OrderID
+	})
+
+	return results
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestReconcile(t *testing.T) {
+	cache := NewOrderCache()
+	defer cache.Close()
+
+	cache.CacheOrder(&Order{
+		OrderID: "linear", Symbol: "XBTUSD", OrdStatus: "Filled",
+		CumQty: 30, AvgPx: 3500 + 1.0/3})
+	cache.CacheOrder(&Order{
+		OrderID: "inverse", Symbol: "XBTUSD", OrdStatus: "Filled",
+		CumQty: 20, AvgPx: 2 / (1/3000.0 + 1/4000.0)})
+	cache.CacheOrder(&Order{
+		OrderID: "qty", Symbol: "XBTUSD", OrdStatus: "PartiallyFilled",
+		CumQty: 15, AvgPx: 3500})
+	cache.CacheOrder(&Order{
+		OrderID: "px", Symbol: "XBTUSD", OrdStatus: "Filled",
+		CumQty: 10, AvgPx: 3600})
+
+	execs := []*Execution{
+		{OrderID: "linear", ExecType: "Trade", LastQty: 10, LastPx: 3500},
+		{OrderID: "linear", ExecType: "Trade", LastQty: 20, LastPx: 3500.5},
+		{OrderID: "linear", ExecType: "New"},
+		{OrderID: "inverse", ExecType: "Trade", LastQty: 10, LastPx: 3000},
+		{OrderID: "inverse", ExecType: "Trade", LastQty: 10, LastPx: 4000},
+		{OrderID: "qty", ExecType: "Trade", LastQty: 10, LastPx: 3500},
+		{OrderID: "px", ExecType: "Trade", LastQty: -10, LastPx: 3500},
+		{OrderID: "missing", ExecType: "Trade", LastQty: 5, LastPx: 3500},
+		{ExecType: "Funding", LastQty: 1},
+	}
+
+	expected := map[string]string{
+		"linear":  ReconcileOK,
+		"inverse": ReconcileOK,
+		"qty":     ReconcileMismatch,
+		"px":      ReconcileMismatch,
+		"missing": ReconcileMissing,
+	}
+
+	results := cache.Reconcile(execs, DefaultPxTolerance)
+	if len(results) != len(expected) {
+		t.Fatal("reconcile results count mismatch:", len(results))
+	}
+
+	for _, r := range results {
+		if r.Status != expected[r.OrderID] {
+			t.Error("reconcile status mismatch:", r.OrderID, r.Status)
+		}
+	}
+
+	if results[0].OrderID != "inverse" || results[0].Fills != 2 {
+		t.Error("results should be sorted by orderID:", results[0])
+	}
+
+	var buff bytes.Buffer
+
+	formatter, _ := NewFormatter("csv", &buff, nil)
+	formatter.Format(results[0])
+	formatter.Flush()
+
+	if header := bytes.SplitN(buff.Bytes(), []byte("\n"), 2)[0]; string(header) !=
+		"orderID,symbol,ordStatus,fills,cumQty,fillQty,avgPx,fillAvgPx,status" {
+		t.Error("reconcile csv header mismatch:", string(header))
+	}
+}
+
+func TestConvertExecution(t *testing.T) {
+	exec, err := ConvertExecution(&ngerest.Execution{
+		ExecID: "1", OrderID: "2", Side: "Sell", ExecType: "Trade",
+		LastQty: 10, LastPx: 3500})
+
+	if err != nil || exec.Side != Sell || !exec.IsFill() {
+		t.Error("convert execution failed:", exec, err)
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// FlagStrings string list flag separated by comma, values will be
+// overwritten by the first Set and appended by the followings.
+type FlagStrings struct {
+	values  []string
+	changed bool
+}
+
+// String get values joined by comma.
+func (s *FlagStrings) String() string {
+	return strings.Join(s.values, ",")
+}
+
+// Set set values by comma separated string, empty values are ignored.
+func (s *FlagStrings) Set(value string) error {
+	if !s.changed {
+		s.values = nil
+		s.changed = true
+	}
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			s.values = append(s.values, v)
+		}
+	}
+
+	return nil
+}
+
+// Type get format type string.
+func (s *FlagStrings) Type() string {
+	return "strings"
+}
+
+// Values get flag values.
+func (s *FlagStrings) Values() []string {
+	return s.values
+}
+
+// Reset clear all values.
+func (s *FlagStrings) Reset() {
+	s.values = nil
+	s.changed = false
+}
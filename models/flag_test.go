@@ -0,0 +1,28 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlagStrings(t *testing.T) {
+	var flag FlagStrings
+
+	flag.Set("a, b,,")
+	flag.Set("c")
+
+	if !reflect.DeepEqual(flag.Values(), []string{"a", "b", "c"}) {
+		t.Error("values miss-match:", flag.Values())
+	}
+
+	if flag.String() != "a,b,c" {
+		t.Error("string miss-match:", flag.String())
+	}
+
+	flag.Reset()
+	flag.Set("d")
+
+	if !reflect.DeepEqual(flag.Values(), []string{"d"}) {
+		t.Error("values should be overwritten after reset:", flag.Values())
+	}
+}
@@ -0,0 +1,546 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/gocarina/gocsv"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DefaultOutputFormat default output format for query results
+const DefaultOutputFormat = "jsonl"
+
+// OutputFormats supported output formats
+var OutputFormats = []string{"table", "csv", "json", "jsonl", "yaml"}
+
+// CheckOutputFormat check if output format is valid
+func CheckOutputFormat(format string) bool {
+	for _, f := range OutputFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Formatter format result rows to output
+type Formatter interface {
+	// Format format and write a result row to output,
+	// row must be a struct pointer with csv & json tags.
+	Format(row interface{}) error
+	// Flush write all buffered content to output.
+	Flush() error
+}
+
+// NewFormatter create formatter for specified format,
+// fields is the column names to be output, empty means all columns.
+func NewFormatter(format string, out io.Writer, fields []string) (Formatter, error) {
+	switch format {
+	case "table":
+		return &tableFormatter{
+			columnSelector: columnSelector{fields: fields},
+			writer:         tabwriter.NewWriter(out, 0, 4, 2, ' ', 0),
+		}, nil
+	case "csv":
+		return &csvFormatter{
+			columnSelector: columnSelector{fields: fields},
+			writer:         csv.NewWriter(out),
+		}, nil
+	case "json":
+		return &jsonFormatter{
+			fieldSelector: fieldSelector{fields: fields}, out: out}, nil
+	case "jsonl":
+		return &jsonlFormatter{
+			fieldSelector: fieldSelector{fields: fields}, out: out}, nil
+	case "yaml":
+		return &yamlFormatter{
+			fieldSelector: fieldSelector{fields: fields}, out: out}, nil
+	default:
+		return nil, common.ErrOutputFormat
+	}
+}
+
+// marshalCSVRecord marshal row to csv header & values with gocsv,
+// map row will be marshaled with sorted keys as header.
+func marshalCSVRecord(row interface{}) ([]string, []string, error) {
+	if value := reflect.ValueOf(row); value.Kind() == reflect.Map {
+		return marshalMapRecord(value)
+	}
+
+	rows := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(row)), 0, 1)
+	rows = reflect.Append(rows, reflect.ValueOf(row))
+
+	content, err := gocsv.MarshalBytes(rows.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(records) != 2 {
+		return nil, nil, common.ErrOutputRecord
+	}
+
+	return records[0], records[1], nil
+}
+
+func marshalMapRecord(row reflect.Value) ([]string, []string, error) {
+	header := make([]string, 0, row.Len())
+
+	for _, key := range row.MapKeys() {
+		header = append(header, fmt.Sprint(key.Interface()))
+	}
+
+	sort.Strings(header)
+
+	values := make([]string, len(header))
+
+	for idx, name := range header {
+		value := row.MapIndex(reflect.ValueOf(name).Convert(row.Type().Key()))
+
+		switch v := value.Interface().(type) {
+		case nil:
+		case string:
+			values[idx] = v
+		case float64:
+			values[idx] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			content, err := json.Marshal(v)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			values[idx] = string(content)
+		}
+	}
+
+	return header, values, nil
+}
+
+// fieldsCheck check specified fields only once with the first row,
+// rows are dropped after check failed, so that unknown fields
+// are reported once in all formats.
+type fieldsCheck struct {
+	checked bool
+	failed  bool
+}
+
+// once run check with the first row, skip is true if check failed
+func (c *fieldsCheck) once(check func() error) (skip bool, err error) {
+	if c.checked {
+		return c.failed, nil
+	}
+
+	c.checked = true
+
+	if err = check(); err != nil {
+		c.failed = true
+		common.SetExitError(err)
+
+		return true, err
+	}
+
+	return false, nil
+}
+
+// checkFields check if all fields are in row's names,
+// map rows are not checked as their keys may differ in each row.
+func checkFields(row interface{}, fields, names []string) error {
+	if reflect.ValueOf(row).Kind() == reflect.Map {
+		return nil
+	}
+
+	exist := make(map[string]bool, len(names))
+	for _, name := range names {
+		exist[name] = true
+	}
+
+	for _, field := range fields {
+		if !exist[field] {
+			return common.NewError(
+				common.KindValidation, field, common.ErrOutputField)
+		}
+	}
+
+	return nil
+}
+
+// columnSelector select csv columns by field names,
+// header will be resolved with the first row,
+// missing columns in following rows will be empty.
+type columnSelector struct {
+	fieldsCheck
+
+	fields  []string
+	columns []string
+}
+
+// selectRow select columns of row, header is returned with the first row,
+// skip is true if row should not be output for fields check failed.
+func (s *columnSelector) selectRow(row interface{}) (
+	header []string, values []string, skip bool, err error) {
+	names, record, err := marshalCSVRecord(row)
+	if err != nil {
+		return
+	}
+
+	if skip, err = s.once(func() error {
+		return checkFields(row, s.fields, names)
+	}); skip {
+		return
+	}
+
+	if s.columns == nil {
+		s.columns = s.fields
+		if len(s.columns) < 1 {
+			s.columns = names
+		}
+
+		header = s.columns
+	}
+
+	columns := make(map[string]string, len(names))
+	for idx, name := range names {
+		columns[name] = record[idx]
+	}
+
+	for _, name := range s.columns {
+		values = append(values, columns[name])
+	}
+
+	return
+}
+
+type csvFormatter struct {
+	columnSelector
+
+	writer *csv.Writer
+}
+
+func (f *csvFormatter) Format(row interface{}) error {
+	header, values, skip, err := f.selectRow(row)
+	if skip || err != nil {
+		return err
+	}
+
+	if header != nil {
+		if err = f.writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	return f.writer.Write(values)
+}
+
+func (f *csvFormatter) Flush() error {
+	f.writer.Flush()
+
+	return f.writer.Error()
+}
+
+type tableFormatter struct {
+	columnSelector
+
+	writer *tabwriter.Writer
+}
+
+func (f *tableFormatter) Format(row interface{}) error {
+	header, values, skip, err := f.selectRow(row)
+	if skip || err != nil {
+		return err
+	}
+
+	if header != nil {
+		if _, err = io.WriteString(
+			f.writer, strings.Join(header, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(f.writer, strings.Join(values, "\t")+"\n")
+
+	return err
+}
+
+func (f *tableFormatter) Flush() error {
+	return f.writer.Flush()
+}
+
+// orderedFields fields in order of specified json fields or row's columns,
+// marshaled as json object & yaml map with order kept.
+type orderedFields yaml.MapSlice
+
+// MarshalJSON marshal fields as json object in order
+func (fields orderedFields) MarshalJSON() ([]byte, error) {
+	var buff bytes.Buffer
+
+	buff.WriteByte('{')
+
+	for idx, item := range fields {
+		if idx > 0 {
+			buff.WriteByte(',')
+		}
+
+		key, err := json.Marshal(fmt.Sprint(item.Key))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buff.Write(key)
+		buff.WriteByte(':')
+		buff.Write(value)
+	}
+
+	buff.WriteByte('}')
+
+	return buff.Bytes(), nil
+}
+
+// MarshalYAML marshal fields as yaml map in order
+func (fields orderedFields) MarshalYAML() (interface{}, error) {
+	return yaml.MapSlice(fields), nil
+}
+
+// decodeFields decode json object to fields in order,
+// numbers are kept as json.Number to avoid float conversion.
+func decodeFields(content []byte) (orderedFields, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, common.ErrOutputRecord
+	}
+
+	var fields orderedFields
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+
+		if err = decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, yaml.MapItem{Key: token, Value: value})
+	}
+
+	return fields, nil
+}
+
+// selectFields convert row to ordered fields only with specified json fields,
+// all fields are kept in row's order if not specified.
+func selectFields(row interface{}, fields []string) (orderedFields, error) {
+	content, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := decodeFields(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) < 1 {
+		return values, nil
+	}
+
+	index := make(map[string]interface{}, len(values))
+	for _, item := range values {
+		index[fmt.Sprint(item.Key)] = item.Value
+	}
+
+	selected := make(orderedFields, 0, len(fields))
+	for _, field := range fields {
+		if v, exist := index[field]; exist {
+			selected = append(selected, yaml.MapItem{Key: field, Value: v})
+		}
+	}
+
+	return selected, nil
+}
+
+// jsonFieldNames get json field names of struct type,
+// including fields of embedded structs.
+func jsonFieldNames(typ reflect.Type) []string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+
+	for idx := 0; idx < typ.NumField(); idx++ {
+		field := typ.Field(idx)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.Anonymous && name == "" {
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		}
+
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// fieldSelector select json fields of row by field names,
+// fields are checked by row's type, as empty fields may be omitted.
+type fieldSelector struct {
+	fieldsCheck
+
+	fields []string
+}
+
+// selectRow select fields of row in order, skip is true if row should
+// not be output for fields check failed.
+func (s *fieldSelector) selectRow(row interface{}) (
+	selected orderedFields, skip bool, err error) {
+	if skip, err = s.once(func() error {
+		return checkFields(
+			row, s.fields, jsonFieldNames(reflect.TypeOf(row)))
+	}); skip {
+		return
+	}
+
+	selected, err = selectFields(row, s.fields)
+
+	return
+}
+
+type jsonlFormatter struct {
+	fieldSelector
+
+	out io.Writer
+}
+
+func (f *jsonlFormatter) Format(row interface{}) error {
+	var value interface{} = row
+
+	if len(f.fields) > 0 {
+		selected, skip, err := f.selectRow(row)
+		if skip || err != nil {
+			return err
+		}
+
+		value = selected
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.out.Write(append(content, '\n'))
+
+	return err
+}
+
+func (f *jsonlFormatter) Flush() error {
+	return nil
+}
+
+// jsonFormatter output all rows as an indented json array
+type jsonFormatter struct {
+	fieldSelector
+
+	out  io.Writer
+	rows []interface{}
+}
+
+func (f *jsonFormatter) Format(row interface{}) error {
+	if len(f.fields) < 1 {
+		f.rows = append(f.rows, row)
+		return nil
+	}
+
+	selected, skip, err := f.selectRow(row)
+	if skip || err != nil {
+		return err
+	}
+
+	f.rows = append(f.rows, selected)
+
+	return nil
+}
+
+func (f *jsonFormatter) Flush() error {
+	if f.rows == nil {
+		f.rows = []interface{}{}
+	}
+
+	content, err := json.MarshalIndent(f.rows, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.out.Write(append(content, '\n'))
+
+	f.rows = nil
+
+	return err
+}
+
+// yamlFormatter output each row as a yaml sequence item
+type yamlFormatter struct {
+	fieldSelector
+
+	out io.Writer
+}
+
+func (f *yamlFormatter) Format(row interface{}) error {
+	selected, skip, err := f.selectRow(row)
+	if skip || err != nil {
+		return err
+	}
+
+	content, err := yaml.Marshal([]interface{}{selected})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.out.Write(content)
+
+	return err
+}
+
+func (f *yamlFormatter) Flush() error {
+	return nil
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/gocarina/gocsv"
+)
+
+func TestFormatter(t *testing.T) {
+	orders := []*Order{
+		{OrderID: "1", Side: Buy, Price: 100.5, OrderQty: 10, OrdStatus: "New"},
+		{OrderID: "22", Side: Sell, Price: 101, OrderQty: -5, OrdStatus: "Filled"},
+	}
+
+	cases := map[string]string{
+		"csv": "orderID,side,price\n1,Buy,100.5\n22,Sell,101\n",
+		"table": "orderID  side  price\n" +
+			"1        Buy   100.5\n" +
+			"22       Sell  101\n",
+		"jsonl": "{\"orderID\":\"1\",\"side\":\"Buy\",\"price\":100.5}\n" +
+			"{\"orderID\":\"22\",\"side\":\"Sell\",\"price\":101}\n",
+		"json": "[\n  {\n    \"orderID\": \"1\",\n    \"side\": \"Buy\",\n" +
+			"    \"price\": 100.5\n  },\n  {\n    \"orderID\": \"22\",\n" +
+			"    \"side\": \"Sell\",\n    \"price\": 101\n  }\n]\n",
+		"yaml": "- orderID: \"1\"\n  side: Buy\n  price: 100.5\n" +
+			"- orderID: \"22\"\n  side: Sell\n  price: 101\n",
+	}
+
+	for format, expected := range cases {
+		var buff bytes.Buffer
+
+		formatter, err := NewFormatter(
+			format, &buff, []string{"orderID", "side", "price"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ord := range orders {
+			if err := formatter.Format(ord); err != nil {
+				t.Fatal(format, err)
+			}
+		}
+
+		if err := formatter.Flush(); err != nil {
+			t.Fatal(format, err)
+		}
+
+		if buff.String() != expected {
+			t.Errorf("%s output miss-match:\n%s", format, buff.String())
+		}
+	}
+
+	var buff bytes.Buffer
+
+	formatter, _ := NewFormatter("yaml", &buff, []string{"price", "orderQty"})
+	formatter.Format(&Order{Price: 1500000, OrderQty: 2000000})
+
+	if buff.String() != "- price: 1500000\n  orderQty: 2000000\n" {
+		t.Error("yaml numbers miss-match:", buff.String())
+	}
+
+	buff.Reset()
+
+	formatter, _ = NewFormatter("csv", &buff, nil)
+	formatter.Format(orders[0])
+	formatter.Flush()
+
+	parsed := []*Order{}
+	if err := gocsv.Unmarshal(strings.NewReader(buff.String()), &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed) != 1 || parsed[0].OrderID != "1" ||
+		parsed[0].Side != Buy || parsed[0].Price != 100.5 {
+		t.Error("csv output can not be read back:", buff.String())
+	}
+}
+
+func TestFormatterMapRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"symbol": "XBTUSD", "price": 3500.5, "size": 10.0, "side": nil},
+		{"symbol": "XBTUSD", "price": 3501.0, "tags": []string{"a"}},
+	}
+
+	cases := map[string]string{
+		"csv": "price,side,size,symbol\n3500.5,,10,XBTUSD\n3501,,,XBTUSD\n",
+		"table": "price   side  size  symbol\n" +
+			"3500.5        10    XBTUSD\n" +
+			"3501                XBTUSD\n",
+	}
+
+	for format, expected := range cases {
+		var buff bytes.Buffer
+
+		formatter, _ := NewFormatter(format, &buff, nil)
+
+		for _, row := range rows {
+			if err := formatter.Format(row); err != nil {
+				t.Fatal(format, err)
+			}
+		}
+
+		formatter.Flush()
+
+		if buff.String() != expected {
+			t.Errorf("%s output miss-match:\n%s", format, buff.String())
+		}
+	}
+
+	var buff bytes.Buffer
+
+	formatter, _ := NewFormatter("csv", &buff, []string{"tags", "missing"})
+	formatter.Format(rows[1])
+	formatter.Flush()
+
+	if buff.String() != "tags,missing\n\"[\"\"a\"\"]\",\n" {
+		t.Error("csv output miss-match:", buff.String())
+	}
+}
+
+func TestFormatterUnknownField(t *testing.T) {
+	defer common.ResetExitError()
+
+	for _, format := range OutputFormats {
+		var buff bytes.Buffer
+
+		formatter, _ := NewFormatter(format, &buff, []string{"orderID", "bad"})
+
+		if err := formatter.Format(&Order{OrderID: "1"}); err == nil {
+			t.Errorf("%s: unknown field should be reported", format)
+		}
+
+		if err := formatter.Format(&Order{OrderID: "2"}); err != nil {
+			t.Errorf("%s: unknown field should be reported once: %v", format, err)
+		}
+
+		formatter.Flush()
+
+		if output := strings.TrimSpace(buff.String()); output != "" &&
+			output != "[]" {
+			t.Errorf("%s: rows should be dropped: %s", format, output)
+		}
+	}
+
+	var buff bytes.Buffer
+
+	// empty field omitted in json is still a known field
+	formatter, _ := NewFormatter("jsonl", &buff, []string{"orderID", "price"})
+
+	if err := formatter.Format(&Order{OrderID: "1"}); err != nil {
+		t.Error("omitted field should be known:", err)
+	}
+}
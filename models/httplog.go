@@ -0,0 +1,131 @@
+package models
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "******"
+
+// maxLogBody max length of request & response body logged
+const maxLogBody = 4096
+
+// sensitiveHeaders headers whose values are redacted in logs
+var sensitiveHeaders = map[string]bool{
+	"Api-Key":       true,
+	"Api-Signature": true,
+	"X-Auth-Token":  true,
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+const sensitiveFields = `password|secret|apiSecret|api_secret|token`
+
+var (
+	// json string field, such as "password":"xxx"
+	jsonSecret = regexp.MustCompile(
+		`("(?i:` + sensitiveFields + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// url encoded field, such as password=xxx
+	formSecret = regexp.MustCompile(
+		`((?:^|&)(?i:` + sensitiveFields + `)=)[^&]*`)
+	// multipart form field
+	multipartSecret = regexp.MustCompile(
+		`(name="(?i:` + sensitiveFields + `)"\r\n\r\n)[^\r\n]*`)
+)
+
+// redactHeader copy header with sensitive values redacted
+func redactHeader(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			result[name] = redacted
+			continue
+		}
+
+		result[name] = strings.Join(values, "; ")
+	}
+
+	return result
+}
+
+// redactBody redact sensitive fields in json, url encoded or multipart body
+func redactBody(body []byte) string {
+	content := string(body)
+
+	if len(content) > maxLogBody {
+		content = content[:maxLogBody] + "..."
+	}
+
+	content = jsonSecret.ReplaceAllString(content, `$1"`+redacted+`"`)
+	content = formSecret.ReplaceAllString(content, "${1}"+redacted)
+	content = multipartSecret.ReplaceAllString(content, "${1}"+redacted)
+
+	return content
+}
+
+// logTransport http transport logging requests & responses at debug
+// level with secrets redacted, bodies are only read if debug enabled.
+type logTransport struct {
+	base http.RoundTripper
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := logger.Named("http")
+
+	if !log.Core().Enabled(zapcore.DebugLevel) {
+		return t.base.RoundTrip(req)
+	}
+
+	fields := []zap.Field{
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.String()),
+		zap.Any("header", redactHeader(req.Header)),
+	}
+
+	if req.GetBody != nil {
+		if reader, err := req.GetBody(); err == nil {
+			body, _ := ioutil.ReadAll(reader)
+			fields = append(fields, zap.String("body", redactBody(body)))
+		}
+	}
+
+	log.Debug("Request sent.", fields...)
+
+	start := time.Now()
+
+	rsp, err := t.base.RoundTrip(req)
+	if err != nil {
+		log.Debug("Request failed.", zap.String("method", req.Method),
+			zap.String("url", req.URL.String()), zap.Error(err))
+		return rsp, err
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return rsp, err
+	}
+
+	log.Debug("Response received.",
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.String()),
+		zap.Int("status", rsp.StatusCode),
+		zap.Duration("elapsed", time.Since(start)),
+		zap.Any("header", redactHeader(rsp.Header)),
+		zap.String("body", redactBody(body)))
+
+	return rsp, nil
+}
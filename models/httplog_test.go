@@ -0,0 +1,37 @@
+package models
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	header := redactHeader(http.Header{
+		"Api-Key":      {"KEY"},
+		"Api-Expires":  {"1560000000"},
+		"X-Auth-Token": {"TOKEN"},
+	})
+
+	if header["Api-Key"] != redacted || header["X-Auth-Token"] != redacted ||
+		header["Api-Expires"] != "1560000000" {
+		t.Error("header redaction mismatch:", header)
+	}
+
+	for body, secret := range map[string]string{
+		`{"email":"a@b.com","password":"PASS"}`:                                         "PASS",
+		`{"apiKey":"KEY","secret":"SEC\"RET","code":"0"}`:                               `SEC\"RET`,
+		`email=a%40b.com&password=PASS&code=0`:                                          "PASS",
+		"--b\r\nContent-Disposition: form-data; name=\"password\"\r\n\r\nPASS\r\n--b--": "PASS",
+	} {
+		result := redactBody([]byte(body))
+
+		if strings.Contains(result, secret) || !strings.Contains(result, redacted) {
+			t.Error("body redaction mismatch:", result)
+		}
+	}
+
+	if result := redactBody([]byte(`{"symbol":"XBTUSD"}`)); result != `{"symbol":"XBTUSD"}` {
+		t.Error("body without secret should not be changed:", result)
+	}
+}
@@ -0,0 +1,326 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+const (
+	// InstrumentOpen state of instrument open for trading
+	InstrumentOpen = "Open"
+
+	// DefaultInstrumentTTL duration before cached instruments reloaded
+	DefaultInstrumentTTL = 5 * time.Minute
+
+	// tickEpsilon tolerance in ticks for float rounding error
+	tickEpsilon = 1e-6
+)
+
+// Instrument instrument table, only trading related fields are kept
+type Instrument struct {
+	Symbol           string    `csv:"symbol" json:"symbol"`
+	RootSymbol       string    `csv:"rootSymbol,omitempty" json:"rootSymbol,omitempty"`
+	State            string    `csv:"state,omitempty" json:"state,omitempty"`
+	Typ              string    `csv:"typ,omitempty" json:"typ,omitempty"`
+	Listing          time.Time `csv:"listing,omitempty" json:"listing,omitempty"`
+	Expiry           time.Time `csv:"expiry,omitempty" json:"expiry,omitempty"`
+	PositionCurrency string    `csv:"positionCurrency,omitempty" json:"positionCurrency,omitempty"`
+	Underlying       string    `csv:"underlying,omitempty" json:"underlying,omitempty"`
+	QuoteCurrency    string    `csv:"quoteCurrency,omitempty" json:"quoteCurrency,omitempty"`
+	SettlCurrency    string    `csv:"settlCurrency,omitempty" json:"settlCurrency,omitempty"`
+	ReferenceSymbol  string    `csv:"referenceSymbol,omitempty" json:"referenceSymbol,omitempty"`
+	MaxOrderQty      float32   `csv:"maxOrderQty,omitempty" json:"maxOrderQty,omitempty"`
+	MaxPrice         float64   `csv:"maxPrice,omitempty" json:"maxPrice,omitempty"`
+	LotSize          float32   `csv:"lotSize,omitempty" json:"lotSize,omitempty"`
+	TickSize         float64   `csv:"tickSize,omitempty" json:"tickSize,omitempty"`
+	Multiplier       float32   `csv:"multiplier,omitempty" json:"multiplier,omitempty"`
+	IsQuanto         bool      `csv:"isQuanto,omitempty" json:"isQuanto,omitempty"`
+	IsInverse        bool      `csv:"isInverse,omitempty" json:"isInverse,omitempty"`
+	InitMargin       float64   `csv:"initMargin,omitempty" json:"initMargin,omitempty"`
+	MaintMargin      float64   `csv:"maintMargin,omitempty" json:"maintMargin,omitempty"`
+	RiskLimit        float32   `csv:"riskLimit,omitempty" json:"riskLimit,omitempty"`
+	RiskStep         float32   `csv:"riskStep,omitempty" json:"riskStep,omitempty"`
+	MakerFee         float64   `csv:"makerFee,omitempty" json:"makerFee,omitempty"`
+	TakerFee         float64   `csv:"takerFee,omitempty" json:"takerFee,omitempty"`
+	FundingRate      float64   `csv:"fundingRate,omitempty" json:"fundingRate,omitempty"`
+	PrevClosePrice   float64   `csv:"prevClosePrice,omitempty" json:"prevClosePrice,omitempty"`
+	LimitDownPrice   float64   `csv:"limitDownPrice,omitempty" json:"limitDownPrice,omitempty"`
+	LimitUpPrice     float64   `csv:"limitUpPrice,omitempty" json:"limitUpPrice,omitempty"`
+	Volume24h        float32   `csv:"volume24h,omitempty" json:"volume24h,omitempty"`
+	OpenInterest     float32   `csv:"openInterest,omitempty" json:"openInterest,omitempty"`
+	LastPrice        float64   `csv:"lastPrice,omitempty" json:"lastPrice,omitempty"`
+	BidPrice         float64   `csv:"bidPrice,omitempty" json:"bidPrice,omitempty"`
+	MidPrice         float64   `csv:"midPrice,omitempty" json:"midPrice,omitempty"`
+	AskPrice         float64   `csv:"askPrice,omitempty" json:"askPrice,omitempty"`
+	MarkPrice        float64   `csv:"markPrice,omitempty" json:"markPrice,omitempty"`
+	FairPrice        float64   `csv:"fairPrice,omitempty" json:"fairPrice,omitempty"`
+	Timestamp        time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+}
+
+// IndexComposite composite index table
+type IndexComposite struct {
+	Timestamp   time.Time `csv:"timestamp" json:"timestamp"`
+	Symbol      string    `csv:"symbol,omitempty" json:"symbol,omitempty"`
+	IndexSymbol string    `csv:"indexSymbol,omitempty" json:"indexSymbol,omitempty"`
+	Reference   string    `csv:"reference,omitempty" json:"reference,omitempty"`
+	LastPrice   float64   `csv:"lastPrice,omitempty" json:"lastPrice,omitempty"`
+	Weight      float64   `csv:"weight,omitempty" json:"weight,omitempty"`
+	Logged      time.Time `csv:"logged,omitempty" json:"logged,omitempty"`
+}
+
+// ConvertInstrument convert ngerest.Instrument structure to local Instrument structure
+func ConvertInstrument(ori *ngerest.Instrument) (*Instrument, error) {
+	var converted Instrument
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// ConvertIndexComposite convert ngerest.IndexComposite structure to local IndexComposite structure
+func ConvertIndexComposite(ori *ngerest.IndexComposite) (*IndexComposite, error) {
+	var converted IndexComposite
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// formatFloat format float without trailing zeros
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// isMultiple check if value is multiple of step in tolerance
+func isMultiple(value, step float64) bool {
+	count := value / step
+
+	return math.Abs(count-math.Round(count)) < tickEpsilon
+}
+
+// BasePrice get reference price for new orders, zero if no price available
+func (ins *Instrument) BasePrice() float64 {
+	for _, price := range []float64{
+		ins.LastPrice, ins.MidPrice, ins.MarkPrice, ins.PrevClosePrice,
+	} {
+		if price > 0 {
+			return price
+		}
+	}
+
+	return 0
+}
+
+// CheckState check if instrument is open for trading,
+// instrument without state is treated as open.
+func (ins *Instrument) CheckState() error {
+	if ins.State != "" && ins.State != InstrumentOpen {
+		return common.NewError(common.KindValidation,
+			ins.Symbol+" state "+ins.State, common.ErrInstrumentState)
+	}
+
+	return nil
+}
+
+// CheckPrice check price by tick size & max price
+func (ins *Instrument) CheckPrice(price float64) error {
+	if err := common.CheckPrice(price); err != nil {
+		return err
+	}
+
+	op := fmt.Sprintf("%s price %s", ins.Symbol, formatFloat(price))
+
+	if ins.TickSize > 0 && !isMultiple(price, ins.TickSize) {
+		return common.NewError(common.KindValidation,
+			op+", tick size "+formatFloat(ins.TickSize), common.ErrPriceTick)
+	}
+
+	if ins.MaxPrice > 0 && price > ins.MaxPrice {
+		return common.NewError(common.KindValidation,
+			op+", max price "+formatFloat(ins.MaxPrice), common.ErrMaxPrice)
+	}
+
+	return nil
+}
+
+// CheckQuantity check absolute quantity by lot size & max order quantity
+func (ins *Instrument) CheckQuantity(qty float64) error {
+	qty = math.Abs(qty)
+
+	if err := common.CheckQuantity(int64(qty)); err != nil {
+		return err
+	}
+
+	op := fmt.Sprintf("%s quantity %s", ins.Symbol, formatFloat(qty))
+
+	if lot := float64(ins.LotSize); lot > 0 && !isMultiple(qty, lot) {
+		return common.NewError(common.KindValidation,
+			op+", lot size "+formatFloat(lot), common.ErrLotSize)
+	}
+
+	if max := float64(ins.MaxOrderQty); max > 0 && qty > max {
+		return common.NewError(common.KindValidation,
+			op+", max order quantity "+formatFloat(max), common.ErrMaxOrderQty)
+	}
+
+	return nil
+}
+
+// CheckOrder check order's state, price & quantity by instrument,
+// prices are checked only if specified.
+func (ins *Instrument) CheckOrder(ord *Order) error {
+	if err := ins.CheckState(); err != nil {
+		return err
+	}
+
+	if ord.Price != 0 {
+		if err := ins.CheckPrice(ord.Price); err != nil {
+			return err
+		}
+	}
+
+	if ord.StopPx != 0 {
+		if err := ins.CheckPrice(ord.StopPx); err != nil {
+			return err
+		}
+	}
+
+	return ins.CheckQuantity(float64(ord.OrderQty))
+}
+
+// CheckAmendment check changed price & quantities by instrument
+func (ins *Instrument) CheckAmendment(amend *Amendment) error {
+	if err := ins.CheckState(); err != nil {
+		return err
+	}
+
+	for _, price := range []float64{amend.Price, amend.StopPx} {
+		if price == 0 {
+			continue
+		}
+
+		if err := ins.CheckPrice(price); err != nil {
+			return err
+		}
+	}
+
+	for _, qty := range []float32{amend.OrderQty, amend.LeavesQty} {
+		if qty == 0 {
+			continue
+		}
+
+		if err := ins.CheckQuantity(float64(qty)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InstrumentLoader load all instruments
+type InstrumentLoader func() ([]ngerest.Instrument, error)
+
+// InstrumentRegistry instruments cached by symbol,
+// which are reloaded after ttl expired.
+type InstrumentRegistry struct {
+	loader InstrumentLoader
+	ttl    time.Duration
+
+	lock        sync.Mutex
+	instruments map[string]*Instrument
+	loaded      time.Time
+	err         error
+}
+
+// load reload instruments by loader, lock should be held by caller,
+// load error is also cached, so failed loading is not retried until
+// ttl expired.
+func (reg *InstrumentRegistry) load() error {
+	reg.loaded = time.Now()
+
+	results, err := reg.loader()
+	if err != nil {
+		reg.instruments, reg.err = nil, err
+		return err
+	}
+
+	instruments := make(map[string]*Instrument, len(results))
+
+	for idx := range results {
+		converted, err := ConvertInstrument(&results[idx])
+		if err != nil {
+			reg.instruments, reg.err = nil, err
+			return err
+		}
+
+		// the latest record is kept if instrument has history records
+		if prev, exist := instruments[converted.Symbol]; exist &&
+			prev.Timestamp.After(converted.Timestamp) {
+			continue
+		}
+
+		instruments[converted.Symbol] = converted
+	}
+
+	reg.instruments, reg.err = instruments, nil
+
+	return nil
+}
+
+// Get get instrument by symbol, instruments will be loaded if not
+// loaded or expired.
+func (reg *InstrumentRegistry) Get(symbol string) (*Instrument, error) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	if reg.loaded.IsZero() || time.Since(reg.loaded) > reg.ttl {
+		if err := reg.load(); err != nil {
+			return nil, err
+		}
+	} else if reg.err != nil {
+		return nil, reg.err
+	}
+
+	ins, exist := reg.instruments[symbol]
+	if !exist {
+		return nil, common.NewError(
+			common.KindValidation, symbol, common.ErrInstrumentNotFound)
+	}
+
+	return ins, nil
+}
+
+// Invalidate drop cached instruments, so they will be reloaded in next Get
+func (reg *InstrumentRegistry) Invalidate() {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	reg.instruments, reg.loaded, reg.err = nil, time.Time{}, nil
+}
+
+// NewInstrumentRegistry create instrument registry with loader,
+// DefaultInstrumentTTL is used if ttl is not positive.
+func NewInstrumentRegistry(
+	loader InstrumentLoader, ttl time.Duration) *InstrumentRegistry {
+	if ttl <= 0 {
+		ttl = DefaultInstrumentTTL
+	}
+
+	reg := InstrumentRegistry{
+		loader: loader,
+		ttl:    ttl,
+	}
+
+	return &reg
+}
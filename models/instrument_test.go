@@ -0,0 +1,145 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// causeIs check if err or error wrapped by err is target
+func causeIs(err, target error) bool {
+	if wrapped, ok := err.(*common.Error); ok {
+		err = wrapped.Err
+	}
+
+	return err == target
+}
+
+func TestInstrumentCheck(t *testing.T) {
+	ins := Instrument{
+		Symbol:      "XBTUSD",
+		State:       InstrumentOpen,
+		TickSize:    0.5,
+		MaxPrice:    1000000,
+		LotSize:     10,
+		MaxOrderQty: 1000,
+	}
+
+	for _, price := range []float64{0.5, 5050, 5050.5, 1000000} {
+		if err := ins.CheckPrice(price); err != nil {
+			t.Error(price, "should be valid:", err)
+		}
+	}
+
+	for price, expect := range map[float64]error{
+		-1:        common.ErrPrice,
+		5050.3:    common.ErrPriceTick,
+		1000000.5: common.ErrMaxPrice,
+	} {
+		if err := ins.CheckPrice(price); !causeIs(err, expect) ||
+			common.KindOf(err) != common.KindValidation {
+			t.Error(price, "check result mismatch:", err)
+		}
+	}
+
+	for qty, expect := range map[float64]error{
+		10:    nil,
+		-1000: nil,
+		0:     common.ErrQuantity,
+		15:    common.ErrLotSize,
+		1010:  common.ErrMaxOrderQty,
+	} {
+		if err := ins.CheckQuantity(qty); !causeIs(err, expect) {
+			t.Error(qty, "check result mismatch:", err)
+		}
+	}
+
+	ord := Order{Symbol: "XBTUSD", Price: 5050.5, OrderQty: 20, StopPx: 5000.1}
+	if err := ins.CheckOrder(&ord); !causeIs(err, common.ErrPriceTick) {
+		t.Error("stop price should be checked:", err)
+	}
+
+	amend := Amendment{OrderID: "1", LeavesQty: 5}
+	if err := ins.CheckAmendment(&amend); !causeIs(err, common.ErrLotSize) {
+		t.Error("leaves quantity should be checked:", err)
+	}
+
+	ins.State = "Settled"
+	err := ins.CheckOrder(&Order{Price: 5050, OrderQty: 10})
+	if !causeIs(err, common.ErrInstrumentState) {
+		t.Error("settled instrument should not be traded:", err)
+	}
+}
+
+func TestInstrumentRegistry(t *testing.T) {
+	var (
+		loads   int
+		loadErr error
+	)
+
+	reg := NewInstrumentRegistry(func() ([]ngerest.Instrument, error) {
+		loads++
+
+		if loadErr != nil {
+			return nil, loadErr
+		}
+
+		return []ngerest.Instrument{
+			{Symbol: "XBTUSD", State: InstrumentOpen, TickSize: 0.5},
+		}, nil
+	}, 0)
+
+	for i := 0; i < 2; i++ {
+		ins, err := reg.Get("XBTUSD")
+		if err != nil || ins.TickSize != 0.5 {
+			t.Fatal("instrument mismatch:", ins, err)
+		}
+	}
+
+	if _, err := reg.Get("ETHUSD"); !causeIs(err, common.ErrInstrumentNotFound) {
+		t.Error("unknown symbol should not be found:", err)
+	}
+
+	if loads != 1 {
+		t.Error("instruments should be cached:", loads)
+	}
+
+	loadErr = errors.New("network failure")
+	reg.Invalidate()
+
+	for i := 0; i < 2; i++ {
+		if _, err := reg.Get("XBTUSD"); err != loadErr {
+			t.Error("load error should be returned:", err)
+		}
+	}
+
+	if loads != 2 {
+		t.Error("load error should be cached until expired:", loads)
+	}
+}
+
+func TestInstrumentRegistryHistory(t *testing.T) {
+	now := time.Now()
+
+	reg := NewInstrumentRegistry(func() ([]ngerest.Instrument, error) {
+		return []ngerest.Instrument{
+			{Symbol: "XBTM19", State: InstrumentOpen, Timestamp: now},
+			{Symbol: "XBTM19", State: "Settled", Timestamp: now.Add(time.Hour)},
+			{Symbol: "XBTM19", State: InstrumentOpen,
+				Timestamp: now.Add(-time.Hour)},
+		}, nil
+	}, 0)
+
+	ins, err := reg.Get("XBTM19")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ins.CheckState(); !causeIs(err, common.ErrInstrumentState) {
+		t.Error("state of latest record should be checked:", ins.State, err)
+	}
+}
@@ -0,0 +1,314 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKeySize = 32
+
+	// sealedPrefix prefix of secrets sealed by keystore, secrets without
+	// prefix are shadowed by legacy RSA key.
+	sealedPrefix = "aead:"
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// PassphraseFunc get master passphrase of keystore,
+// empty passphrase means keystore is not protected.
+type PassphraseFunc func() (string, error)
+
+type keystoreFile struct {
+	Version int `json:"version"`
+	// Salt for deriving key from passphrase, data key is sealed
+	// with passphrase only if salt is not empty.
+	Salt string `json:"salt,omitempty"`
+	Key  string `json:"key"`
+}
+
+// Keystore per-installation secret store, secrets are sealed with
+// AES-256-GCM by a random data key generated on first run,
+// data key can be protected by an optional master passphrase.
+type Keystore struct {
+	path string
+	key  []byte
+	aead cipher.AEAD
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func randomBytes(size int) ([]byte, error) {
+	data := make([]byte, size)
+
+	_, err := io.ReadFull(rand.Reader, data)
+
+	return data, err
+}
+
+func seal(aead cipher.AEAD, plain []byte) ([]byte, error) {
+	nonce, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func unseal(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, common.ErrSealed
+	}
+
+	nonce, data := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, common.ErrSealed
+	}
+
+	return plain, nil
+}
+
+func passphraseAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(
+		[]byte(passphrase), salt, scryptN, scryptR, scryptP, keystoreKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAEAD(key)
+}
+
+// Seal encrypt secret with data key, result is base64 encoded
+// with sealedPrefix.
+func (ks *Keystore) Seal(secret string) (string, error) {
+	sealed, err := seal(ks.aead, []byte(secret))
+	if err != nil {
+		return "", err
+	}
+
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypt secret sealed by Seal
+func (ks *Keystore) Open(sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return "", common.ErrSealed
+	}
+
+	data, err := base64.StdEncoding.DecodeString(
+		strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil {
+		return "", common.ErrSealed
+	}
+
+	plain, err := unseal(ks.aead, data)
+
+	return string(plain), err
+}
+
+// SetPassphrase protect data key with new passphrase and save keystore,
+// empty passphrase will remove protection.
+func (ks *Keystore) SetPassphrase(passphrase string) error {
+	return ks.save(passphrase)
+}
+
+// Path get keystore file path
+func (ks *Keystore) Path() string {
+	return ks.path
+}
+
+func (ks *Keystore) save(passphrase string) error {
+	content := keystoreFile{Version: keystoreVersion}
+
+	key := ks.key
+
+	if passphrase != "" {
+		salt, err := randomBytes(keystoreKeySize)
+		if err != nil {
+			return err
+		}
+
+		aead, err := passphraseAEAD(passphrase, salt)
+		if err != nil {
+			return err
+		}
+
+		if key, err = seal(aead, ks.key); err != nil {
+			return err
+		}
+
+		content.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+
+	content.Key = base64.StdEncoding.EncodeToString(key)
+
+	data, err := json.MarshalIndent(&content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to temp file first, so data key will not be lost
+	// if keystore is failed to be written
+	tmpPath := ks.path + ".tmp"
+
+	if err = ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, ks.path)
+}
+
+func (ks *Keystore) load(passphrase PassphraseFunc) error {
+	info, err := os.Stat(ks.path)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		return common.ErrKeystorePerm
+	}
+
+	data, err := ioutil.ReadFile(ks.path)
+	if err != nil {
+		return err
+	}
+
+	content := keystoreFile{}
+
+	if err = json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+
+	if content.Version != keystoreVersion {
+		return common.ErrKeystore
+	}
+
+	if ks.key, err = base64.StdEncoding.DecodeString(content.Key); err != nil {
+		return common.ErrKeystore
+	}
+
+	if content.Salt != "" {
+		salt, err := base64.StdEncoding.DecodeString(content.Salt)
+		if err != nil {
+			return common.ErrKeystore
+		}
+
+		pass, err := passphrase()
+		if err != nil {
+			return err
+		}
+
+		aead, err := passphraseAEAD(pass, salt)
+		if err != nil {
+			return err
+		}
+
+		if ks.key, err = unseal(aead, ks.key); err != nil {
+			return common.ErrPassphrase
+		}
+	}
+
+	if len(ks.key) != keystoreKeySize {
+		return common.ErrKeystore
+	}
+
+	ks.aead, err = newAEAD(ks.key)
+
+	return err
+}
+
+// IsSealed to judge if secret is sealed by keystore
+func IsSealed(secret string) bool {
+	return strings.HasPrefix(secret, sealedPrefix)
+}
+
+// OpenKeystore open keystore file, a new keystore without passphrase will
+// be generated with 0600 permission if file not exists, passphrase is only
+// called if keystore is protected.
+func OpenKeystore(path string, passphrase PassphraseFunc) (*Keystore, error) {
+	if passphrase == nil {
+		passphrase = func() (string, error) { return "", nil }
+	}
+
+	ks := Keystore{path: path}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		if err = ks.load(passphrase); err != nil {
+			return nil, err
+		}
+
+		return &ks, nil
+	}
+
+	key, err := randomBytes(keystoreKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.key = key
+
+	if ks.aead, err = newAEAD(key); err != nil {
+		return nil, err
+	}
+
+	if err = ks.save(""); err != nil {
+		return nil, err
+	}
+
+	return &ks, nil
+}
+
+var defaultKeystore struct {
+	path       string
+	passphrase PassphraseFunc
+
+	store *Keystore
+	err   error
+	once  sync.Once
+}
+
+// SetDefaultKeystore set keystore used by Password, keystore is
+// opened lazily when the first secret is sealed or opened.
+func SetDefaultKeystore(path string, passphrase PassphraseFunc) {
+	defaultKeystore.path = path
+	defaultKeystore.passphrase = passphrase
+}
+
+// DefaultKeystore get default keystore set by SetDefaultKeystore
+func DefaultKeystore() (*Keystore, error) {
+	defaultKeystore.once.Do(func() {
+		if defaultKeystore.path == "" {
+			defaultKeystore.err = common.ErrKeystore
+			return
+		}
+
+		defaultKeystore.store, defaultKeystore.err = OpenKeystore(
+			defaultKeystore.path, defaultKeystore.passphrase)
+	})
+
+	return defaultKeystore.store, defaultKeystore.err
+}
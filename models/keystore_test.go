@@ -0,0 +1,209 @@
+package models
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+// legacyShadowed "yuanyang" shadowed by legacy RSA key
+const legacyShadowed = "Kaia8b1g91tItzxpxgH1Syc4lZ2t02Gr9AHunwe4iSzO4EBVQkzLNuGMmwuW1Y0WRI302NiCVWZCIsjn+UPqgQKzNjWymCj3WIU4Ma8WH0gdunQJe8yStVBYX8RuF5SkroN8JArn8sBDSMLaHxJOgzB/+rBy8akVu61R0VoRS7Nsr5RTCCe/f3TwUlreRobcEo8hAhnWBjSvL+t4TXqpJrPJVr0nvYuvlhXM+iCzOKSWUdeYnmV29KiuBCl7GUa9TLgZgl/raHjAX45wRuKZI6tlffuJXA6SI7EKggO9Vh+UKUaJ3FnNSNRT2mv/CWBsC8jmm+MK1QwQzQiHEELuIw=="
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		panic(err)
+	}
+
+	SetDefaultKeystore(filepath.Join(dir, "keystore"), nil)
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keystore")
+
+	store, err := OpenKeystore(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Error("keystore should only be accessed by owner:", info.Mode())
+	}
+
+	sealed, err := store.Seal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsSealed(sealed) {
+		t.Error("sealed secret should have prefix:", sealed)
+	}
+
+	if another, _ := store.Seal("secret"); another == sealed {
+		t.Error("sealed secret should be randomized by nonce")
+	}
+
+	if err = store.SetPassphrase("master"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = OpenKeystore(path, func() (string, error) {
+		return "wrong", nil
+	}); err != common.ErrPassphrase {
+		t.Error("wrong passphrase should fail:", err)
+	}
+
+	reopened, err := OpenKeystore(path, func() (string, error) {
+		return "master", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plain, err := reopened.Open(sealed); err != nil || plain != "secret" {
+		t.Error("open sealed secret failed:", plain, err)
+	}
+
+	other, err := OpenKeystore(filepath.Join(dir, "other"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = other.Open(sealed); err != common.ErrSealed {
+		t.Error("secret sealed by other keystore should fail:", err)
+	}
+
+	os.Chmod(path, 0644)
+
+	if _, err = OpenKeystore(path, nil); err != common.ErrKeystorePerm {
+		t.Error("keystore readable by others should fail:", err)
+	}
+}
+
+func TestPasswordMigrate(t *testing.T) {
+	pass := NewPassword()
+
+	if err := pass.ShadowSet(legacyShadowed); err != nil {
+		t.Fatal(err)
+	}
+
+	if !pass.IsLegacy() {
+		t.Error("password should be legacy")
+	}
+
+	if err := pass.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if pass.IsLegacy() || pass.Show() != "yuanyang" {
+		t.Error("migrate password failed:", pass.String())
+	}
+
+	if err := pass.ShadowSet("aead:invalid"); err == nil {
+		t.Error("invalid sealed password should fail")
+	}
+
+	if pass.Show() != "yuanyang" {
+		t.Error("password should not be changed by failed ShadowSet")
+	}
+}
+
+func TestNewPasswordByPEM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pemPath := filepath.Join(dir, "legacy.pem")
+
+	if err = ioutil.WriteFile(pemPath, []byte(legacyKeyPEM), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pass, err := NewPasswordByPEM(pemPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pass.legacy == nil {
+		t.Fatal("legacy key should be read from pem file")
+	}
+
+	if err = pass.ShadowSet(legacyShadowed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = NewPasswordByPEM(filepath.Join(dir, "typo.pem")); err == nil {
+		t.Error("missing pem file should be reported")
+	}
+}
+
+func TestMigrateSavedAuths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	authFile := filepath.Join(dir, "auths.yaml")
+
+	content := "trade:\n" +
+		"  identity: a@b.com\n" +
+		"  password: " + legacyShadowed + "\n" +
+		"  key: KEY\n" +
+		"  secret: SECRET\n"
+
+	if err = ioutil.WriteFile(authFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewAuthCache(context.Background(), &ClientHub{})
+	cache.SetConfigFile(authFile)
+
+	count, err := cache.MigrateSavedAuths(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Error("password & secret should be migrated:", count)
+	}
+
+	if err = cache.WriteConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, _ := os.Stat(authFile); info.Mode().Perm() != 0600 {
+		t.Error("auth file should only be accessed by owner:", info.Mode())
+	}
+
+	reloaded := NewAuthCache(context.Background(), &ClientHub{})
+	reloaded.SetConfigFile(authFile)
+
+	store, _ := DefaultKeystore()
+
+	if secret, err := store.Open(
+		reloaded.savedAuths.GetString("trade_secret")); secret != "SECRET" {
+		t.Error("saved api secret mismatch:", secret, err)
+	}
+
+	if count, _ = reloaded.MigrateSavedAuths(nil); count != 0 {
+		t.Error("sealed entries should not be migrated again:", count)
+	}
+}
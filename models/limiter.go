@@ -0,0 +1,85 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+// TokenBucket token bucket refilled in fixed rate
+type TokenBucket struct {
+	tokens chan struct{}
+	ticker *time.Ticker
+	stop   chan struct{}
+}
+
+func (b *TokenBucket) refill() {
+	for {
+		select {
+		case <-b.ticker.C:
+			select {
+			case b.tokens <- struct{}{}:
+			default:
+				// bucket is full
+			}
+		case <-b.stop:
+			b.ticker.Stop()
+			return
+		}
+	}
+}
+
+// Take take a token from bucket, it will block until token available
+// or timeChan fired, nil bucket means unlimited.
+func (b *TokenBucket) Take(timeChan <-chan time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	select {
+	case <-b.tokens:
+		return nil
+	case <-timeChan:
+		return common.ErrTokenInsufficient
+	}
+}
+
+// Stop stop refilling bucket
+func (b *TokenBucket) Stop() {
+	if b == nil {
+		return
+	}
+
+	close(b.stop)
+}
+
+// maxTokenBurst max tokens can be held in bucket
+const maxTokenBurst = 1000
+
+// NewTokenBucket create a full token bucket refilled with rate tokens
+// per second, rate is capped by MaxOrderRate, burst size is the same as
+// rate(at least 1, at most 1000), if rate <= 0, nil bucket will be returned.
+func NewTokenBucket(rate float64) *TokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+
+	rate = math.Min(rate, MaxOrderRate)
+
+	burst := int(math.Min(maxTokenBurst, math.Max(1, math.Ceil(rate))))
+
+	bucket := TokenBucket{
+		tokens: make(chan struct{}, burst),
+		ticker: time.NewTicker(time.Duration(float64(time.Second) / rate)),
+		stop:   make(chan struct{}),
+	}
+
+	for i := 0; i < burst; i++ {
+		bucket.tokens <- struct{}{}
+	}
+
+	go bucket.refill()
+
+	return &bucket
+}
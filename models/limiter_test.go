@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(10)
+	defer bucket.Stop()
+
+	for i := 0; i < 10; i++ {
+		if err := bucket.Take(time.After(time.Millisecond)); err != nil {
+			t.Fatal("bucket should be full when created:", i)
+		}
+	}
+
+	if err := bucket.Take(time.After(time.Millisecond)); err == nil {
+		t.Fatal("bucket should be empty.")
+	}
+
+	if err := bucket.Take(time.After(time.Second)); err != nil {
+		t.Fatal("bucket should be refilled.")
+	}
+
+	var unlimited *TokenBucket
+
+	if err := unlimited.Take(nil); err != nil {
+		t.Fatal("nil bucket should be unlimited.")
+	}
+
+	huge := NewTokenBucket(1e12)
+	defer huge.Stop()
+
+	if cap(huge.tokens) != maxTokenBurst {
+		t.Error("burst of huge rate should be capped:", cap(huge.tokens))
+	}
+}
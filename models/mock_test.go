@@ -0,0 +1,187 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/mock"
+
+	"github.com/antihax/optional"
+	"github.com/frozenpine/ngerest"
+	"github.com/frozenpine/viper"
+)
+
+// useMockServer point base host to fake server, which should be closed
+// after test.
+func useMockServer() *mock.Server {
+	srv := mock.NewServer()
+
+	viper.Set("scheme", "http")
+	viper.Set("host", srv.Host())
+	viper.Set("port", srv.Port())
+	viper.Set("base-uri", mock.DefaultBaseURI)
+
+	return srv
+}
+
+func TestAuthCacheLogin(t *testing.T) {
+	srv := useMockServer()
+	defer srv.Close()
+
+	account := srv.AddAccount("a@b.com", "password")
+
+	hub := ClientHub{}
+	cache := NewAuthCache(context.Background(), &hub)
+
+	password := NewPassword()
+	if err := password.Set("wrong"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.Login("a@b.com", password); common.KindOf(err) != common.KindAuth {
+		t.Error("login with wrong password should be auth error:", err)
+	}
+
+	password.Set("password")
+
+	key, err := cache.loginKey("a@b.com", password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Key != account.Key || key.Secret != account.Secret {
+		t.Error("default api key mismatch:", key)
+	}
+
+	cache.addAuth(&Authentication{
+		Identity: "a@b.com", Password: *password, APIKey: *key})
+
+	srv.ExpireKey("a@b.com")
+
+	client, err := hub.GetClient(common.GetBaseHost())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.Order.OrderGetOrders(
+		cache.GetAuthContext(nil, "a@b.com"), nil); err != nil {
+		t.Error("expired api key should be refreshed by re-login:", err)
+	}
+
+	if srv.RequestCount("POST", "/user/login") != 3 {
+		t.Error("login count mismatch:", srv.RequestCount("POST", "/user/login"))
+	}
+}
+
+func TestOrderCacheDispatchMock(t *testing.T) {
+	srv := useMockServer()
+	defer srv.Close()
+
+	hub := ClientHub{}
+	auths := NewAuthCache(context.Background(), &hub)
+
+	ids := []string{"a@b.com", "b@b.com"}
+	accounts := make(map[string]float32)
+
+	for _, id := range ids {
+		account := srv.AddAccount(id, "password")
+		accounts[id] = account.ID
+
+		auths.addAuth(&Authentication{Identity: id, APIKey: APIKey{
+			Key: account.Key, Secret: account.Secret}})
+	}
+
+	client, err := hub.GetClient(common.GetBaseHost())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := func(id string, ord *Order) (*ngerest.Order, error) {
+		result, rsp, err := client.Order.OrderNew(
+			auths.GetAuthContext(nil, id), ord.Symbol, &ngerest.OrderNewOpts{
+				Side:     optional.NewString(ord.Side.String()),
+				OrderQty: optional.NewFloat32(ord.OrderQty),
+				Price:    optional.NewFloat64(ord.Price),
+				ClOrdID:  optional.NewString(ord.ClOrdID),
+			})
+		if err != nil {
+			return nil, common.NewAPIError("", err, rsp)
+		}
+
+		return &result, nil
+	}
+
+	srv.SetLatency(20 * time.Millisecond)
+	srv.InjectError(http.MethodPost, "/order", http.StatusBadRequest, 1)
+
+	cache := NewOrderCache()
+	cache.SetOrderRate(0, 0)
+	cache.SetMaxInflight(1)
+
+	var (
+		failed    int
+		countLock sync.Mutex
+	)
+
+	waitSend := cache.Dispatch(2, sender, func(ord *Order, err error) {
+		if err != nil {
+			countLock.Lock()
+			failed++
+			countLock.Unlock()
+		}
+	})
+
+	var results []*Order
+
+	waitResults := sync.WaitGroup{}
+	waitResults.Add(1)
+
+	go func() {
+		defer waitResults.Done()
+
+		for ord := range cache.GetResults() {
+			results = append(results, ord)
+		}
+	}()
+
+	for i := 0; i < 6; i++ {
+		// orders re-fed from exported results with OrderID
+		ord := Order{
+			OrderID: "exported" + strconv.Itoa(i), Symbol: mock.DefaultSymbol,
+			Side: Buy, OrderQty: 1, Price: 5000}
+
+		// inflight slot of failed order should also be released
+		if err := cache.PutOrder(ids[i%2], &ord, time.Second); err != nil {
+			t.Fatal("inflight slot leaked:", err)
+		}
+	}
+
+	cache.CloseInputs()
+	waitSend.Wait()
+	cache.CloseResults()
+	waitResults.Wait()
+
+	if failed != 1 || len(results) != 5 {
+		t.Fatalf("failed: %d, results: %d", failed, len(results))
+	}
+
+	if orders := srv.Orders(mock.DefaultSymbol); len(orders) != 5 {
+		t.Error("orders in book miss-match:", len(orders))
+	}
+
+	for _, ord := range results {
+		id, _, exist := cache.GetOwner(ord.OrderID)
+		if !exist {
+			t.Fatal("result not bound to sender:", ord.OrderID)
+		}
+
+		if accounts[id] != ord.Account {
+			t.Error("owner of result miss-match:", id, ord.Account)
+		}
+	}
+}
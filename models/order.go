@@ -2,15 +2,18 @@ package models
 
 import (
 	"bytes"
-	"encoding/gob"
-	"encoding/json"
+	"crypto/rand"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
 
 	"github.com/frozenpine/ngerest"
+
+	"go.uber.org/zap"
 )
 
 // OrderSide order side
@@ -52,13 +55,16 @@ func (s *OrderSide) Opposite() OrderSide {
 	}
 }
 
-// MatchSide match side with quantity
+// MatchSide match side with quantity, side is inferred from sign of
+// quantity if not specified, absolute quantity is accepted for Sell
+// as exported orders, but negative quantity for Buy is miss-matched.
 func (s *OrderSide) MatchSide(qty int64) error {
 	switch *s {
 	case Buy:
 		if qty < 0 {
 			return common.ErrMissMatchQtySide
 		}
+	case Sell:
 	case "":
 		if qty > 0 {
 			*s = Buy
@@ -72,19 +78,24 @@ func (s *OrderSide) MatchSide(qty int64) error {
 	return nil
 }
 
-// UnmarshalCSV unmarshal csv column to OrderSide
+// UnmarshalCSV unmarshal csv column to OrderSide,
+// empty column will be left unset to match side with quantity
 func (s *OrderSide) UnmarshalCSV(value string) error {
+	if value == "" {
+		return nil
+	}
+
 	return s.Set(value)
 }
 
 // MarshalCSV marshal to csv column
-func (s *OrderSide) MarshalCSV() string {
-	return (*s).String()
+func (s *OrderSide) MarshalCSV() (string, error) {
+	return (*s).String(), nil
 }
 
 // UnmarshalJSON unmarshal from json string
 func (s *OrderSide) UnmarshalJSON(data []byte) error {
-	return s.Set(strings.Trim(string(data), "\""))
+	return s.UnmarshalCSV(strings.Trim(string(data), "\""))
 }
 
 // MarshalJSON marshal to json string
@@ -114,7 +125,7 @@ func (s *OrderSide) Set(value string) error {
 
 // Type get order side type
 func (s *OrderSide) Type() string {
-	return s.String()
+	return "OrderSide"
 }
 
 // Order order table
@@ -157,7 +168,7 @@ type Order struct {
 // IsClosed check if order is closed
 func (ord *Order) IsClosed() bool {
 	switch ord.OrdStatus {
-	case "Filled":
+	case "Filled", "Canceled", "Rejected":
 		return true
 	default:
 		return false
@@ -165,9 +176,14 @@ func (ord *Order) IsClosed() bool {
 }
 
 const (
-	defaultInflightOrders      int     = 5
-	defaultMaxOrderRatePerUser float64 = 1
-	defaultMaxOrderRateTotal   float64 = 200
+	// DefaultInflightOrders default max inflight orders per client
+	DefaultInflightOrders int = 5
+	// DefaultOrderRatePerUser default order rate per client
+	DefaultOrderRatePerUser float64 = 1
+	// DefaultOrderRateTotal default order rate for all clients
+	DefaultOrderRateTotal float64 = 200
+	// MaxOrderRate max order rate per second can be limited by token bucket
+	MaxOrderRate float64 = 10000
 )
 
 type clientCache struct {
@@ -176,7 +192,7 @@ type clientCache struct {
 }
 
 func (cc *clientCache) Queue(ord *Order) {
-	cc.inQueue[ord.OrderID] = ord
+	cc.inQueue[orderKey(ord)] = ord
 }
 
 func (cc *clientCache) Finish(ord *Order) {
@@ -206,11 +222,52 @@ type OrderCache struct {
 	clientOrderCache    map[string]*clientCache
 	clientInflightQueue map[string]chan interface{}
 	maxInflightOrders   int
-	orderRate           float64
-	tokenBucket         chan bool
+	// clientBuckets client identity as key, refilled with orderRate
+	clientBuckets map[string]*TokenBucket
+	orderRate     float64
+	// tokenBucket global token bucket shared by all clients
+	tokenBucket *TokenBucket
+	lock        sync.Mutex
+}
+
+// clientID make client identity readable in console
+func clientID(id string) string {
+	if id == "" {
+		return "<default>"
+	}
+
+	return id
+}
+
+// NewClOrdID generate a random client order id in uuid format
+func NewClOrdID() string {
+	var uuid [16]byte
+
+	rand.Read(uuid[:])
+
+	// version 4, variant 10
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x",
+		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
 }
 
-func (cache *OrderCache) requireToken(timeChan <-chan time.Time) <-chan error {
+func (cache *OrderCache) getTokenBucket(id string) *TokenBucket {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	bucket, exist := cache.clientBuckets[id]
+	if !exist {
+		bucket = NewTokenBucket(cache.orderRate)
+		cache.clientBuckets[id] = bucket
+	}
+
+	return bucket
+}
+
+func (cache *OrderCache) requireToken(
+	id string, timeChan <-chan time.Time) <-chan error {
 	errChan := make(chan error, 1)
 
 	go func() {
@@ -218,17 +275,36 @@ func (cache *OrderCache) requireToken(timeChan <-chan time.Time) <-chan error {
 			close(errChan)
 		}()
 
-		select {
-		case <-cache.tokenBucket:
+		if err := cache.getTokenBucket(id).Take(timeChan); err != nil {
+			errChan <- err
 			return
-		case <-timeChan:
-			errChan <- common.ErrTokenInsufficient
+		}
+
+		cache.lock.Lock()
+		bucket := cache.tokenBucket
+		cache.lock.Unlock()
+
+		if err := bucket.Take(timeChan); err != nil {
+			errChan <- err
 		}
 	}()
 
 	return errChan
 }
 
+func (cache *OrderCache) getInflightQueue(id string) chan interface{} {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	queue, exist := cache.clientInflightQueue[id]
+	if !exist {
+		queue = make(chan interface{}, cache.maxInflightOrders)
+		cache.clientInflightQueue[id] = queue
+	}
+
+	return queue
+}
+
 func (cache *OrderCache) checkInflight(
 	id string, timeChan <-chan time.Time) <-chan error {
 	errChan := make(chan error, 1)
@@ -238,14 +314,8 @@ func (cache *OrderCache) checkInflight(
 			close(errChan)
 		}()
 
-		queue, exist := cache.clientInflightQueue[id]
-		if !exist {
-			queue = make(chan interface{}, cache.maxInflightOrders)
-			cache.clientInflightQueue[id] = queue
-		}
-
 		select {
-		case queue <- nil:
+		case cache.getInflightQueue(id) <- nil:
 			return
 		case <-timeChan:
 			errChan <- common.ErrInflightCheck
@@ -255,12 +325,49 @@ func (cache *OrderCache) checkInflight(
 	return errChan
 }
 
+func (cache *OrderCache) releaseInflight(id string) {
+	cache.lock.Lock()
+	clientQueue := cache.clientInflightQueue[id]
+	cache.lock.Unlock()
+
+	if clientQueue == nil {
+		logger.Warn("Inflight queue missing.",
+			zap.String("client", clientID(id)))
+		return
+	}
+
+	select {
+	case <-clientQueue:
+	default:
+		logger.Warn("Reduce inflight queue failed.",
+			zap.String("client", clientID(id)))
+	}
+}
+
+// orderKey get order's cache key, new order without OrderID
+// will be keyed by ClOrdID until it's result returned
+func orderKey(ord *Order) string {
+	if ord.OrderID != "" {
+		return ord.OrderID
+	}
+
+	return ord.ClOrdID
+}
+
+// putOrder cache new order keyed by a fresh ClOrdID, OrderID in order
+// source(eg: re-fed order exports) is cleared as it will be re-assigned
+// in result, so results can always be bound by ClOrdID.
 func (cache *OrderCache) putOrder(id string, ord *Order) error {
-	cache.inputs <- ord
+	ord.OrderID = ""
+	ord.ClOrdID = NewClOrdID()
 
-	cache.inflightCache[ord.OrderID] = ord
-	cache.orderCache[ord.OrderID] = ord
-	cache.orderClientMap[ord.OrderID] = id
+	key := orderKey(ord)
+
+	cache.lock.Lock()
+
+	cache.inflightCache[key] = ord
+	cache.orderCache[key] = ord
+	cache.orderClientMap[key] = id
 
 	clientCache, exist := cache.clientOrderCache[id]
 	if !exist {
@@ -271,11 +378,55 @@ func (cache *OrderCache) putOrder(id string, ord *Order) error {
 
 	clientCache.Queue(ord)
 
+	cache.lock.Unlock()
+
+	cache.inputs <- ord
+
 	return nil
 }
 
-func (cache *OrderCache) findClientIDByOrder(ord *Order) string {
-	return cache.orderClientMap[ord.OrderID]
+// bindResult find client id by order result, if result is found by ClOrdID,
+// cache will be re-keyed by result's OrderID
+func (cache *OrderCache) bindResult(ord *Order) (string, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if id, exist := cache.orderClientMap[ord.OrderID]; exist {
+		return id, true
+	}
+
+	id, exist := cache.orderClientMap[ord.ClOrdID]
+	if !exist || ord.ClOrdID == "" {
+		return "", false
+	}
+
+	delete(cache.orderClientMap, ord.ClOrdID)
+	delete(cache.inflightCache, ord.ClOrdID)
+	delete(cache.orderCache, ord.ClOrdID)
+
+	cache.orderClientMap[ord.OrderID] = id
+	cache.orderCache[ord.OrderID] = ord
+
+	if clientCache := cache.clientOrderCache[id]; clientCache != nil {
+		delete(clientCache.inQueue, ord.ClOrdID)
+		clientCache.Queue(ord)
+	}
+
+	return id, true
+}
+
+// GetOwner get client id & cached order by OrderID or ClOrdID of
+// orders sent by cache, order is nil if it's not cached.
+func (cache *OrderCache) GetOwner(key string) (string, *Order, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	id, exist := cache.orderClientMap[key]
+	if !exist {
+		return "", nil, false
+	}
+
+	return id, cache.orderCache[key], true
 }
 
 // PutOrder order into order cache, it's go routing safe
@@ -295,13 +446,119 @@ func (cache *OrderCache) PutOrder(
 		return err
 	}
 
-	if err := <-cache.requireToken(timeoutCh); err != nil {
+	if err := <-cache.requireToken(id, timeoutCh); err != nil {
+		cache.releaseInflight(id)
 		return err
 	}
 
 	return cache.putOrder(id, ord)
 }
 
+// SetOrderRate set order rate limit per client & total limit for all
+// clients in orders per second, rate <= 0 means unlimited.
+// It should be called before any order put into cache.
+func (cache *OrderCache) SetOrderRate(perClient, total float64) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	for _, bucket := range cache.clientBuckets {
+		bucket.Stop()
+	}
+	cache.clientBuckets = make(map[string]*TokenBucket)
+	cache.orderRate = perClient
+
+	cache.tokenBucket.Stop()
+	cache.tokenBucket = NewTokenBucket(total)
+}
+
+// Close stop refilling all token buckets in cache.
+func (cache *OrderCache) Close() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	for _, bucket := range cache.clientBuckets {
+		bucket.Stop()
+	}
+	cache.clientBuckets = make(map[string]*TokenBucket)
+
+	cache.tokenBucket.Stop()
+	cache.tokenBucket = nil
+}
+
+// SetMaxInflight set max inflight orders for each client.
+// It should be called before any order put into cache.
+func (cache *OrderCache) SetMaxInflight(max int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if max < 1 {
+		max = 1
+	}
+
+	cache.maxInflightOrders = max
+}
+
+// OrderSender send order to NGE with client identity
+type OrderSender func(id string, ord *Order) (*ngerest.Order, error)
+
+// Dispatch start workers to drain order inputs & send orders with sender,
+// succeeded order result will be put into result channel,
+// callback will be called for each order with sending result,
+// returned WaitGroup will be done after inputs closed & drained.
+func (cache *OrderCache) Dispatch(
+	workers int, sender OrderSender,
+	callback func(ord *Order, err error)) *sync.WaitGroup {
+	waitWorkers := sync.WaitGroup{}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	worker := func() {
+		defer waitWorkers.Done()
+
+		for ord := range cache.inputs {
+			cache.lock.Lock()
+			id := cache.orderClientMap[orderKey(ord)]
+			cache.lock.Unlock()
+
+			result, err := sender(id, ord)
+
+			if err != nil {
+				cache.release(id, ord)
+			}
+
+			if callback != nil {
+				callback(ord, err)
+			}
+
+			if err == nil {
+				cache.PutResult(result)
+				// inflight slot is released after each sending even if
+				// result can not be bound to cached order
+				cache.release(id, ord)
+			}
+		}
+	}
+
+	waitWorkers.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+
+	return &waitWorkers
+}
+
+// release release order's inflight slot of client after order sent
+func (cache *OrderCache) release(id string, ord *Order) {
+	cache.lock.Lock()
+	delete(cache.inflightCache, orderKey(ord))
+	cache.lock.Unlock()
+
+	cache.releaseInflight(id)
+}
+
 // GetInputs to get order cache's input channel
 func (cache *OrderCache) GetInputs() <-chan *Order { return cache.inputs }
 
@@ -310,39 +567,29 @@ func (cache *OrderCache) CloseInputs() { close(cache.inputs) }
 
 // PutResult puts order result into cache
 func (cache *OrderCache) PutResult(ord *ngerest.Order) {
-	converted := ConvertOrder(ord)
-
-	if converted == nil {
-		jsonBytes, _ := json.Marshal(ord)
-		fmt.Println("convert order failed, origin:", string(jsonBytes))
+	converted, err := ConvertOrder(ord)
+	if err != nil {
+		reportConvertError("order", ord, err)
 		return
 	}
 
 	cache.results <- converted
 
-	clientID := cache.findClientIDByOrder(converted)
+	id, exist := cache.bindResult(converted)
 
-	if clientID == "" {
-		// fmt.Println("failed to find client id by order:", ord.OrderID)
+	if !exist {
 		return
 	}
 
 	if converted.IsClosed() {
-		if clientCache := cache.clientOrderCache[clientID]; clientCache != nil {
+		cache.lock.Lock()
+		if clientCache := cache.clientOrderCache[id]; clientCache != nil {
 			clientCache.Finish(converted)
 		} else {
-			fmt.Println("client cache missing for client:", clientID)
-		}
-	}
-
-	if clientQueue := cache.clientInflightQueue[clientID]; clientQueue != nil {
-		select {
-		case <-clientQueue:
-		default:
-			fmt.Println("reduce inflight queue failed for client:", clientID)
+			logger.Warn("Client cache missing.",
+				zap.String("client", clientID(id)))
 		}
-	} else {
-		fmt.Println("inflight queue missing for client:", clientID)
+		cache.lock.Unlock()
 	}
 }
 
@@ -358,35 +605,26 @@ func NewOrderCache() *OrderCache {
 		inputs:              make(chan *Order),
 		results:             make(chan *Order),
 		orderCache:          make(map[string]*Order),
+		orderClientMap:      make(map[string]string),
 		inflightCache:       make(map[string]*Order),
 		clientInflightQueue: make(map[string]chan interface{}),
 		clientOrderCache:    make(map[string]*clientCache),
-		maxInflightOrders:   defaultInflightOrders,
-		orderRate:           defaultMaxOrderRatePerUser,
+		maxInflightOrders:   DefaultInflightOrders,
+		clientBuckets:       make(map[string]*TokenBucket),
+		orderRate:           DefaultOrderRatePerUser,
+		tokenBucket:         NewTokenBucket(DefaultOrderRateTotal),
 	}
 
 	return &cache
 }
 
 // ConvertOrder convert ngerest.Order structure to local Order structure
-func ConvertOrder(ori *ngerest.Order) *Order {
-	var ordBuff bytes.Buffer
-
-	enc := gob.NewEncoder(&ordBuff)
-	dec := gob.NewDecoder(&ordBuff)
-
-	err := enc.Encode(*ori)
-	if err != nil {
-		fmt.Println(err)
-		return nil
-	}
-
+func ConvertOrder(ori *ngerest.Order) (*Order, error) {
 	var converted Order
-	err = dec.Decode(&converted)
-	if err != nil {
-		fmt.Println(err)
-		return nil
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
 	}
 
-	return &converted
+	return &converted, nil
 }
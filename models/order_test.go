@@ -1,7 +1,11 @@
 package models
 
 import (
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/frozenpine/ngerest"
 )
 
 func TestOrderSide(t *testing.T) {
@@ -17,6 +21,12 @@ func TestOrderSide(t *testing.T) {
 		t.Fatal("match side failed.")
 	}
 
+	sell := Sell
+
+	if err := sell.MatchSide(1); err != nil {
+		t.Fatal("absolute quantity should be accepted for Sell:", err)
+	}
+
 	sideValue := OrderSide("")
 
 	if sideValue.MatchSide(-1); sideValue != Sell {
@@ -25,3 +35,114 @@ func TestOrderSide(t *testing.T) {
 		t.Log(sideValue)
 	}
 }
+
+func TestOrderCacheDispatch(t *testing.T) {
+	cache := NewOrderCache()
+	cache.SetOrderRate(0, 0)
+	cache.SetMaxInflight(1)
+
+	sent := make(map[string]int)
+	orders := make([]*Order, 10)
+
+	sender := func(id string, ord *Order) (*ngerest.Order, error) {
+		sent[id]++
+
+		return &ngerest.Order{
+			OrderID: "ord" + ord.Text,
+			ClOrdID: ord.ClOrdID,
+			Symbol:  ord.Symbol,
+		}, nil
+	}
+
+	waitSend := cache.Dispatch(1, sender, nil)
+
+	go func() {
+		for range cache.GetResults() {
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		id := []string{"a", "b"}[i%2]
+		orders[i] = &Order{Symbol: "XBTUSD", Text: strconv.Itoa(i)}
+
+		if err := cache.PutOrder(id, orders[i], time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache.CloseInputs()
+	waitSend.Wait()
+	cache.CloseResults()
+
+	if sent["a"] != 5 || sent["b"] != 5 {
+		t.Fatal("orders dispatched miss-match:", sent)
+	}
+
+	if id, ord, exist := cache.GetOwner("ord3"); !exist || id != "b" ||
+		ord == nil || ord.ClOrdID != orders[3].ClOrdID {
+		t.Error("owner of result miss-match:", id, ord)
+	}
+
+	if _, _, exist := cache.GetOwner(orders[3].ClOrdID); exist {
+		t.Error("result should be re-keyed by OrderID")
+	}
+}
+
+func TestOrderCacheRefeedOrders(t *testing.T) {
+	cache := NewOrderCache()
+	cache.SetOrderRate(0, 0)
+	cache.SetMaxInflight(1)
+
+	orders := make([]*Order, 4)
+
+	sender := func(id string, ord *Order) (*ngerest.Order, error) {
+		if ord.OrderID != "" || ord.ClOrdID == "" {
+			t.Errorf("re-fed order not renewed: %+v", ord)
+		}
+
+		result := ngerest.Order{OrderID: "new" + ord.Text, Symbol: ord.Symbol}
+
+		// result without ClOrdID can not be bound to cached order
+		if ord.Text != "3" {
+			result.ClOrdID = ord.ClOrdID
+		}
+
+		return &result, nil
+	}
+
+	waitSend := cache.Dispatch(1, sender, nil)
+
+	go func() {
+		for range cache.GetResults() {
+		}
+	}()
+
+	for i := range orders {
+		orders[i] = &Order{
+			Symbol:  "XBTUSD",
+			OrderID: "old" + strconv.Itoa(i),
+			ClOrdID: "old",
+			Text:    strconv.Itoa(i),
+		}
+
+		if err := cache.PutOrder("a", orders[i], time.Second); err != nil {
+			t.Fatal("inflight slot leaked:", err)
+		}
+	}
+
+	cache.CloseInputs()
+	waitSend.Wait()
+	cache.CloseResults()
+
+	if id, _, exist := cache.GetOwner("new1"); !exist || id != "a" {
+		t.Error("re-fed order's result not bound:", id)
+	}
+
+	if _, _, exist := cache.GetOwner("old1"); exist {
+		t.Error("re-fed order should not be keyed by old OrderID")
+	}
+
+	if err := <-cache.checkInflight("a", time.After(time.Second)); err != nil {
+		t.Error("inflight slot not released for unbound result:", err)
+	}
+}
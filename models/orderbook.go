@@ -0,0 +1,253 @@
+package models
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// DefaultBookDepth default depth levels for order book
+const DefaultBookDepth = 10
+
+// BookLevel order book level in L2
+type BookLevel struct {
+	ID    int64     `csv:"id" json:"id"`
+	Side  OrderSide `csv:"side" json:"side"`
+	Price float64   `csv:"price" json:"price"`
+	Size  float64   `csv:"size" json:"size"`
+}
+
+// NewBookLevel create book level from websocket row, price & size can be
+// absent for update & delete rows.
+func NewBookLevel(row map[string]interface{}) (*BookLevel, error) {
+	level := BookLevel{}
+
+	id, ok := row["id"].(float64)
+	if !ok {
+		return nil, common.ErrBookLevel
+	}
+
+	level.ID = int64(id)
+
+	side, _ := row["side"].(string)
+	if err := level.Side.Set(side); err != nil {
+		return nil, common.ErrBookLevel
+	}
+
+	level.Price, _ = row["price"].(float64)
+	level.Size, _ = row["size"].(float64)
+
+	return &level, nil
+}
+
+// OrderBook local L2 order book image with levels keyed by ID,
+// initialized by snapshot and maintained by insert, update & delete deltas.
+type OrderBook struct {
+	Symbol string
+
+	levels map[int64]*BookLevel
+	lock   sync.RWMutex
+}
+
+// Snapshot replace all levels with REST snapshot
+func (b *OrderBook) Snapshot(snapshot []ngerest.OrderBookL2) error {
+	levels := make([]*BookLevel, 0, len(snapshot))
+
+	for _, l2 := range snapshot {
+		level := BookLevel{
+			ID:    int64(l2.ID),
+			Price: l2.Price,
+			Size:  float64(l2.Size),
+		}
+
+		if err := level.Side.Set(l2.Side); err != nil {
+			return common.ErrBookLevel
+		}
+
+		levels = append(levels, &level)
+	}
+
+	b.Partial(levels)
+
+	return nil
+}
+
+// Partial replace all levels with websocket partial
+func (b *OrderBook) Partial(levels []*BookLevel) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.levels = make(map[int64]*BookLevel, len(levels))
+
+	for _, level := range levels {
+		l := *level
+		b.levels[l.ID] = &l
+	}
+}
+
+// Insert insert new levels
+func (b *OrderBook) Insert(levels []*BookLevel) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, level := range levels {
+		l := *level
+		b.levels[l.ID] = &l
+	}
+}
+
+// Update update levels' size, common.ErrBookInconsistent will be returned
+// if level not found, book should be re-synced with a new snapshot.
+func (b *OrderBook) Update(levels []*BookLevel) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, level := range levels {
+		origin, exist := b.levels[level.ID]
+		if !exist {
+			return common.ErrBookInconsistent
+		}
+
+		origin.Size = level.Size
+
+		if level.Price > 0 {
+			origin.Price = level.Price
+		}
+	}
+
+	return nil
+}
+
+// Delete delete levels, common.ErrBookInconsistent will be returned
+// if level not found.
+func (b *OrderBook) Delete(levels []*BookLevel) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, level := range levels {
+		if _, exist := b.levels[level.ID]; !exist {
+			return common.ErrBookInconsistent
+		}
+
+		delete(b.levels, level.ID)
+	}
+
+	return nil
+}
+
+// depth get sorted levels in side, bids in price desc, asks in price asc,
+// depth < 1 means all levels.
+func (b *OrderBook) depth(side OrderSide, depth int) []BookLevel {
+	var levels []BookLevel
+
+	for _, level := range b.levels {
+		if level.Side == side {
+			levels = append(levels, *level)
+		}
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if side == Buy {
+			return levels[i].Price > levels[j].Price
+		}
+
+		return levels[i].Price < levels[j].Price
+	})
+
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+
+	return levels
+}
+
+// Depth get top N levels for bids & asks, depth < 1 means all levels.
+func (b *OrderBook) Depth(depth int) (bids, asks []BookLevel) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.depth(Buy, depth), b.depth(Sell, depth)
+}
+
+// BestBid get best bid level, false will be returned if no bids.
+func (b *OrderBook) BestBid() (BookLevel, bool) {
+	bids, _ := b.Depth(1)
+
+	if len(bids) < 1 {
+		return BookLevel{}, false
+	}
+
+	return bids[0], true
+}
+
+// BestAsk get best ask level, false will be returned if no asks.
+func (b *OrderBook) BestAsk() (BookLevel, bool) {
+	_, asks := b.Depth(1)
+
+	if len(asks) < 1 {
+		return BookLevel{}, false
+	}
+
+	return asks[0], true
+}
+
+// Checksum crc32 checksum of top N levels, levels are interleaved
+// bid & ask in format: price:size, IDs are not included, so checksum
+// can be compared between books from different sources.
+func (b *OrderBook) Checksum(depth int) uint32 {
+	bids, asks := b.Depth(depth)
+
+	var values []string
+
+	for idx := 0; idx < len(bids) || idx < len(asks); idx++ {
+		if idx < len(bids) {
+			values = append(values, formatLevel(&bids[idx]))
+		}
+
+		if idx < len(asks) {
+			values = append(values, formatLevel(&asks[idx]))
+		}
+	}
+
+	return crc32.ChecksumIEEE([]byte(strings.Join(values, ":")))
+}
+
+func formatLevel(level *BookLevel) string {
+	return strconv.FormatFloat(level.Price, 'f', -1, 64) + ":" +
+		strconv.FormatFloat(level.Size, 'f', -1, 64)
+}
+
+// Verify check top N levels consistency with a fresh REST snapshot
+func (b *OrderBook) Verify(snapshot []ngerest.OrderBookL2, depth int) (bool, error) {
+	fresh := NewOrderBook(b.Symbol)
+
+	if err := fresh.Snapshot(snapshot); err != nil {
+		return false, err
+	}
+
+	return fresh.Checksum(depth) == b.Checksum(depth), nil
+}
+
+// Len get total levels count in book
+func (b *OrderBook) Len() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return len(b.levels)
+}
+
+// NewOrderBook create empty order book
+func NewOrderBook(symbol string) *OrderBook {
+	book := OrderBook{
+		Symbol: symbol,
+		levels: make(map[int64]*BookLevel),
+	}
+
+	return &book
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestOrderBook(t *testing.T) {
+	book := NewOrderBook("XBTUSD")
+
+	snapshot := []ngerest.OrderBookL2{
+		{Symbol: "XBTUSD", ID: 1, Side: "Sell", Price: 3502, Size: 30},
+		{Symbol: "XBTUSD", ID: 2, Side: "Sell", Price: 3501, Size: 20},
+		{Symbol: "XBTUSD", ID: 3, Side: "Buy", Price: 3500, Size: 10},
+		{Symbol: "XBTUSD", ID: 4, Side: "Buy", Price: 3499.5, Size: 5},
+	}
+
+	if err := book.Snapshot(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if bid, ok := book.BestBid(); !ok || bid.Price != 3500 {
+		t.Error("best bid mismatch:", bid)
+	}
+
+	if ask, ok := book.BestAsk(); !ok || ask.Price != 3501 {
+		t.Error("best ask mismatch:", ask)
+	}
+
+	if ok, _ := book.Verify(snapshot, DefaultBookDepth); !ok {
+		t.Error("book should be consistent with its snapshot")
+	}
+
+	level, err := NewBookLevel(map[string]interface{}{
+		"symbol": "XBTUSD", "id": 5.0, "side": "Buy",
+		"price": 3500.5, "size": 15.0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	book.Insert([]*BookLevel{level})
+
+	if err := book.Update([]*BookLevel{{ID: 2, Side: Sell, Size: 25}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := book.Delete([]*BookLevel{{ID: 4, Side: Buy}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bids, asks := book.Depth(1)
+	if len(bids) != 1 || bids[0].ID != 5 || len(asks) != 1 || asks[0].Size != 25 {
+		t.Error("depth mismatch:", bids, asks)
+	}
+
+	bids, asks = book.Depth(0)
+	if len(bids) != 2 || bids[1].Price != 3500 || len(asks) != 2 {
+		t.Error("full depth mismatch:", bids, asks)
+	}
+
+	if ok, _ := book.Verify(snapshot, DefaultBookDepth); ok {
+		t.Error("book should be inconsistent with stale snapshot")
+	}
+
+	fresh := []ngerest.OrderBookL2{
+		{Symbol: "XBTUSD", ID: 11, Side: "Sell", Price: 3502, Size: 30},
+		{Symbol: "XBTUSD", ID: 12, Side: "Sell", Price: 3501, Size: 25},
+		{Symbol: "XBTUSD", ID: 13, Side: "Buy", Price: 3500.5, Size: 15},
+		{Symbol: "XBTUSD", ID: 14, Side: "Buy", Price: 3500, Size: 10},
+	}
+
+	if ok, _ := book.Verify(fresh, DefaultBookDepth); !ok {
+		t.Error("checksum should not depend on level IDs")
+	}
+
+	if err := book.Update([]*BookLevel{{ID: 99, Size: 1}}); err == nil {
+		t.Error("update unknown level should fail")
+	}
+
+	if err := book.Delete([]*BookLevel{{ID: 99}}); err == nil {
+		t.Error("delete unknown level should fail")
+	}
+
+	if _, err := NewBookLevel(map[string]interface{}{"side": "Buy"}); err == nil {
+		t.Error("level without id should fail")
+	}
+}
@@ -0,0 +1,80 @@
+package models
+
+// DefaultPageSize default row count in each page query
+const DefaultPageSize = 200
+
+// PageQuery query a page of rows from offset start with max count rows
+type PageQuery func(start, count int) ([]interface{}, error)
+
+// Pager fetch table rows page by page in the same query window,
+// rows will be handled in the order server returned,
+// so ordering follows the reverse option in query.
+type Pager struct {
+	// PageSize max row count in each page query
+	PageSize int
+	// Limit total row count limit, 0 means unlimited
+	Limit int
+	// Query page query function
+	Query PageQuery
+	// Key get row's unique key for de-duplication, nil means no de-duplication
+	Key func(row interface{}) string
+}
+
+// Fetch fetch pages until an empty page returned or limit reached,
+// handler will be called for each row, total handled row count returned.
+func (p *Pager) Fetch(handler func(row interface{})) (int, error) {
+	pageSize := p.PageSize
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+
+	var total int
+
+	seen := make(map[string]bool)
+
+	for start := 0; ; {
+		count := pageSize
+		if p.Limit > 0 && p.Limit-total < count {
+			count = p.Limit - total
+		}
+
+		rows, err := p.Query(start, count)
+		if err != nil {
+			return total, err
+		}
+
+		// short page is not the end, as server may cap rows in each page
+		if len(rows) < 1 {
+			return total, nil
+		}
+
+		var handled int
+
+		for _, row := range rows {
+			if p.Key != nil {
+				key := p.Key(row)
+
+				if seen[key] {
+					continue
+				}
+
+				seen[key] = true
+			}
+
+			handler(row)
+			handled++
+			total++
+
+			if p.Limit > 0 && total >= p.Limit {
+				return total, nil
+			}
+		}
+
+		// page with all rows seen means query window is not moving
+		if handled < 1 {
+			return total, nil
+		}
+
+		start += len(rows)
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	var table []interface{}
+
+	for i := 0; i < 95; i++ {
+		table = append(table, strconv.Itoa(i))
+	}
+
+	query := func(start, count int) ([]interface{}, error) {
+		if start >= len(table) {
+			return nil, nil
+		}
+
+		end := start + count
+		if end > len(table) {
+			end = len(table)
+		}
+
+		// overlap one row with previous page to simulate table shifting
+		if start > 0 {
+			start--
+		}
+
+		return table[start:end], nil
+	}
+
+	key := func(row interface{}) string { return row.(string) }
+
+	var rows []string
+
+	pager := Pager{PageSize: 10, Query: query, Key: key}
+
+	total, err := pager.Fetch(func(row interface{}) {
+		rows = append(rows, row.(string))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 95 || len(rows) != 95 {
+		t.Fatalf("total: %d, rows: %d", total, len(rows))
+	}
+
+	for idx, row := range rows {
+		if row != strconv.Itoa(idx) {
+			t.Fatal("row order miss-match:", idx, row)
+		}
+	}
+
+	limited := Pager{PageSize: 10, Limit: 25, Query: query, Key: key}
+
+	if total, _ := limited.Fetch(func(interface{}) {}); total != 25 {
+		t.Fatal("limit miss-match:", total)
+	}
+}
+
+func TestPagerCappedPage(t *testing.T) {
+	var table []interface{}
+
+	for i := 0; i < 30; i++ {
+		table = append(table, strconv.Itoa(i))
+	}
+
+	// server returns at most 7 rows in each page
+	query := func(start, count int) ([]interface{}, error) {
+		if count > 7 {
+			count = 7
+		}
+
+		if start >= len(table) {
+			return nil, nil
+		}
+
+		end := start + count
+		if end > len(table) {
+			end = len(table)
+		}
+
+		return table[start:end], nil
+	}
+
+	pager := Pager{PageSize: 10, Query: query}
+
+	if total, _ := pager.Fetch(func(interface{}) {}); total != 30 {
+		t.Fatal("rows truncated by capped page:", total)
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// MaxLeverage max leverage for isolated margin position,
+// leverage 0 means cross margin.
+const MaxLeverage = 100
+
+// CheckLeverage check if leverage is in range [0, MaxLeverage]
+func CheckLeverage(leverage float64) error {
+	if leverage < 0 || leverage > MaxLeverage {
+		return common.ErrLeverage
+	}
+
+	return nil
+}
+
+// Position position table
+type Position struct {
+	Account              float32   `csv:"account" json:"account"`
+	Symbol               string    `csv:"symbol" json:"symbol"`
+	Currency             string    `csv:"currency" json:"currency"`
+	Underlying           string    `csv:"underlying,omitempty" json:"underlying,omitempty"`
+	QuoteCurrency        string    `csv:"quoteCurrency,omitempty" json:"quoteCurrency,omitempty"`
+	Commission           float64   `csv:"commission,omitempty" json:"commission,omitempty"`
+	InitMarginReq        float64   `csv:"initMarginReq,omitempty" json:"initMarginReq,omitempty"`
+	MaintMarginReq       float64   `csv:"maintMarginReq,omitempty" json:"maintMarginReq,omitempty"`
+	RiskLimit            float32   `csv:"riskLimit,omitempty" json:"riskLimit,omitempty"`
+	Leverage             float64   `csv:"leverage,omitempty" json:"leverage,omitempty"`
+	CrossMargin          bool      `csv:"crossMargin,omitempty" json:"crossMargin,omitempty"`
+	DeleveragePercentile float64   `csv:"deleveragePercentile,omitempty" json:"deleveragePercentile,omitempty"`
+	RebalancedPnl        float32   `csv:"rebalancedPnl,omitempty" json:"rebalancedPnl,omitempty"`
+	PrevRealisedPnl      float32   `csv:"prevRealisedPnl,omitempty" json:"prevRealisedPnl,omitempty"`
+	PrevUnrealisedPnl    float32   `csv:"prevUnrealisedPnl,omitempty" json:"prevUnrealisedPnl,omitempty"`
+	PrevClosePrice       float64   `csv:"prevClosePrice,omitempty" json:"prevClosePrice,omitempty"`
+	OpeningTimestamp     time.Time `csv:"openingTimestamp,omitempty" json:"openingTimestamp,omitempty"`
+	OpeningQty           float32   `csv:"openingQty,omitempty" json:"openingQty,omitempty"`
+	OpeningCost          float32   `csv:"openingCost,omitempty" json:"openingCost,omitempty"`
+	OpeningComm          float32   `csv:"openingComm,omitempty" json:"openingComm,omitempty"`
+	OpenOrderBuyQty      float32   `csv:"openOrderBuyQty,omitempty" json:"openOrderBuyQty,omitempty"`
+	OpenOrderBuyCost     float32   `csv:"openOrderBuyCost,omitempty" json:"openOrderBuyCost,omitempty"`
+	OpenOrderBuyPremium  float32   `csv:"openOrderBuyPremium,omitempty" json:"openOrderBuyPremium,omitempty"`
+	OpenOrderSellQty     float32   `csv:"openOrderSellQty,omitempty" json:"openOrderSellQty,omitempty"`
+	OpenOrderSellCost    float32   `csv:"openOrderSellCost,omitempty" json:"openOrderSellCost,omitempty"`
+	OpenOrderSellPremium float32   `csv:"openOrderSellPremium,omitempty" json:"openOrderSellPremium,omitempty"`
+	ExecBuyQty           float32   `csv:"execBuyQty,omitempty" json:"execBuyQty,omitempty"`
+	ExecBuyCost          float32   `csv:"execBuyCost,omitempty" json:"execBuyCost,omitempty"`
+	ExecSellQty          float32   `csv:"execSellQty,omitempty" json:"execSellQty,omitempty"`
+	ExecSellCost         float32   `csv:"execSellCost,omitempty" json:"execSellCost,omitempty"`
+	ExecQty              float32   `csv:"execQty,omitempty" json:"execQty,omitempty"`
+	ExecCost             float32   `csv:"execCost,omitempty" json:"execCost,omitempty"`
+	ExecComm             float32   `csv:"execComm,omitempty" json:"execComm,omitempty"`
+	CurrentTimestamp     time.Time `csv:"currentTimestamp,omitempty" json:"currentTimestamp,omitempty"`
+	CurrentQty           float32   `csv:"currentQty,omitempty" json:"currentQty,omitempty"`
+	CurrentCost          float32   `csv:"currentCost,omitempty" json:"currentCost,omitempty"`
+	CurrentComm          float32   `csv:"currentComm,omitempty" json:"currentComm,omitempty"`
+	RealisedCost         float32   `csv:"realisedCost,omitempty" json:"realisedCost,omitempty"`
+	UnrealisedCost       float32   `csv:"unrealisedCost,omitempty" json:"unrealisedCost,omitempty"`
+	GrossOpenCost        float32   `csv:"grossOpenCost,omitempty" json:"grossOpenCost,omitempty"`
+	GrossOpenPremium     float32   `csv:"grossOpenPremium,omitempty" json:"grossOpenPremium,omitempty"`
+	GrossExecCost        float32   `csv:"grossExecCost,omitempty" json:"grossExecCost,omitempty"`
+	IsOpen               bool      `csv:"isOpen,omitempty" json:"isOpen,omitempty"`
+	MarkPrice            float64   `csv:"markPrice,omitempty" json:"markPrice,omitempty"`
+	MarkValue            float32   `csv:"markValue,omitempty" json:"markValue,omitempty"`
+	RiskValue            float32   `csv:"riskValue,omitempty" json:"riskValue,omitempty"`
+	HomeNotional         float64   `csv:"homeNotional,omitempty" json:"homeNotional,omitempty"`
+	ForeignNotional      float64   `csv:"foreignNotional,omitempty" json:"foreignNotional,omitempty"`
+	PosState             string    `csv:"posState,omitempty" json:"posState,omitempty"`
+	PosCost              float32   `csv:"posCost,omitempty" json:"posCost,omitempty"`
+	PosCost2             float32   `csv:"posCost2,omitempty" json:"posCost2,omitempty"`
+	PosCross             float32   `csv:"posCross,omitempty" json:"posCross,omitempty"`
+	PosInit              float32   `csv:"posInit,omitempty" json:"posInit,omitempty"`
+	PosComm              float32   `csv:"posComm,omitempty" json:"posComm,omitempty"`
+	PosLoss              float32   `csv:"posLoss,omitempty" json:"posLoss,omitempty"`
+	PosMargin            float32   `csv:"posMargin,omitempty" json:"posMargin,omitempty"`
+	PosMaint             float32   `csv:"posMaint,omitempty" json:"posMaint,omitempty"`
+	PosAllowance         float32   `csv:"posAllowance,omitempty" json:"posAllowance,omitempty"`
+	TaxableMargin        float32   `csv:"taxableMargin,omitempty" json:"taxableMargin,omitempty"`
+	InitMargin           float32   `csv:"initMargin,omitempty" json:"initMargin,omitempty"`
+	MaintMargin          float32   `csv:"maintMargin,omitempty" json:"maintMargin,omitempty"`
+	SessionMargin        float32   `csv:"sessionMargin,omitempty" json:"sessionMargin,omitempty"`
+	TargetExcessMargin   float32   `csv:"targetExcessMargin,omitempty" json:"targetExcessMargin,omitempty"`
+	VarMargin            float32   `csv:"varMargin,omitempty" json:"varMargin,omitempty"`
+	RealisedGrossPnl     float32   `csv:"realisedGrossPnl,omitempty" json:"realisedGrossPnl,omitempty"`
+	RealisedTax          float32   `csv:"realisedTax,omitempty" json:"realisedTax,omitempty"`
+	RealisedPnl          float32   `csv:"realisedPnl,omitempty" json:"realisedPnl,omitempty"`
+	UnrealisedGrossPnl   float32   `csv:"unrealisedGrossPnl,omitempty" json:"unrealisedGrossPnl,omitempty"`
+	LongBankrupt         float32   `csv:"longBankrupt,omitempty" json:"longBankrupt,omitempty"`
+	ShortBankrupt        float32   `csv:"shortBankrupt,omitempty" json:"shortBankrupt,omitempty"`
+	TaxBase              float32   `csv:"taxBase,omitempty" json:"taxBase,omitempty"`
+	IndicativeTaxRate    float64   `csv:"indicativeTaxRate,omitempty" json:"indicativeTaxRate,omitempty"`
+	IndicativeTax        float32   `csv:"indicativeTax,omitempty" json:"indicativeTax,omitempty"`
+	UnrealisedTax        float32   `csv:"unrealisedTax,omitempty" json:"unrealisedTax,omitempty"`
+	UnrealisedPnl        float32   `csv:"unrealisedPnl,omitempty" json:"unrealisedPnl,omitempty"`
+	UnrealisedPnlPcnt    float64   `csv:"unrealisedPnlPcnt,omitempty" json:"unrealisedPnlPcnt,omitempty"`
+	UnrealisedRoePcnt    float64   `csv:"unrealisedRoePcnt,omitempty" json:"unrealisedRoePcnt,omitempty"`
+	SimpleQty            float64   `csv:"simpleQty,omitempty" json:"simpleQty,omitempty"`
+	SimpleCost           float64   `csv:"simpleCost,omitempty" json:"simpleCost,omitempty"`
+	SimpleValue          float64   `csv:"simpleValue,omitempty" json:"simpleValue,omitempty"`
+	SimplePnl            float64   `csv:"simplePnl,omitempty" json:"simplePnl,omitempty"`
+	SimplePnlPcnt        float64   `csv:"simplePnlPcnt,omitempty" json:"simplePnlPcnt,omitempty"`
+	AvgCostPrice         float64   `csv:"avgCostPrice,omitempty" json:"avgCostPrice,omitempty"`
+	AvgEntryPrice        float64   `csv:"avgEntryPrice,omitempty" json:"avgEntryPrice,omitempty"`
+	BreakEvenPrice       float64   `csv:"breakEvenPrice,omitempty" json:"breakEvenPrice,omitempty"`
+	MarginCallPrice      float64   `csv:"marginCallPrice,omitempty" json:"marginCallPrice,omitempty"`
+	LiquidationPrice     float64   `csv:"liquidationPrice,omitempty" json:"liquidationPrice,omitempty"`
+	BankruptPrice        float64   `csv:"bankruptPrice,omitempty" json:"bankruptPrice,omitempty"`
+	Timestamp            time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+	LastPrice            float64   `csv:"lastPrice,omitempty" json:"lastPrice,omitempty"`
+	LastValue            float32   `csv:"lastValue,omitempty" json:"lastValue,omitempty"`
+}
+
+// ConvertPosition convert ngerest.Position structure to local Position structure
+func ConvertPosition(ori *ngerest.Position) (*Position, error) {
+	var converted Position
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestConvertPosition(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	ori := ngerest.Position{
+		Account:       1,
+		Symbol:        "XBTUSD",
+		Currency:      "XBt",
+		Leverage:      10,
+		CurrentQty:    100,
+		AvgEntryPrice: 3500.5,
+		Timestamp:     now,
+	}
+
+	pos, err := ConvertPosition(&ori)
+	if err != nil {
+		t.Fatal("convert position failed:", err)
+	}
+
+	if pos.Symbol != ori.Symbol || pos.Leverage != ori.Leverage ||
+		pos.CurrentQty != ori.CurrentQty || !pos.Timestamp.Equal(now) {
+		t.Error("converted position mismatch:", pos)
+	}
+}
+
+func TestCheckLeverage(t *testing.T) {
+	for _, leverage := range []float64{0, 1, 12.5, MaxLeverage} {
+		if err := CheckLeverage(leverage); err != nil {
+			t.Error(leverage, err)
+		}
+	}
+
+	for _, leverage := range []float64{-1, MaxLeverage + 1} {
+		if err := CheckLeverage(leverage); err == nil {
+			t.Error("leverage should be invalid:", leverage)
+		}
+	}
+}
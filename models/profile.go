@@ -0,0 +1,462 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultProfileName profile name used if default profile not set
+	DefaultProfileName = "default"
+
+	// profiles are saved as list, so removed profiles & accounts will
+	// not be merged back from config file by viper.
+	savedProfilesKey  = "profiles"
+	defaultProfileKey = "default-profile"
+)
+
+// Account account saved in profile, either identity & password or
+// api key & secret should be set, password & secret are sealed by keystore.
+type Account struct {
+	Host     string `mapstructure:"host"`
+	Identity string `mapstructure:"identity"`
+	Password string `mapstructure:"password"`
+	Key      string `mapstructure:"key"`
+	Secret   string `mapstructure:"secret"`
+}
+
+// ID get account's identity, api key will be used if identity is empty
+func (acc *Account) ID() string {
+	if acc.Identity != "" {
+		return acc.Identity
+	}
+
+	return acc.Key
+}
+
+// Type get account's auth type, either "apikey" or "password"
+func (acc *Account) Type() string {
+	if acc.Key != "" {
+		return "apikey"
+	}
+
+	return "password"
+}
+
+// Authentication open account's sealed secrets as auth info,
+// api key should be retrieved by login if account has no api key.
+func (acc *Account) Authentication() (*Authentication, error) {
+	authInfo := Authentication{Identity: acc.Identity}
+
+	if acc.Password != "" {
+		if err := authInfo.Password.ShadowSet(acc.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	if acc.Key == "" {
+		return &authInfo, nil
+	}
+
+	store, err := DefaultKeystore()
+	if err != nil {
+		return nil, err
+	}
+
+	authInfo.Key = acc.Key
+
+	if authInfo.Secret, err = store.Open(acc.Secret); err != nil {
+		return nil, err
+	}
+
+	return &authInfo, nil
+}
+
+func (acc *Account) settings() map[string]interface{} {
+	settings := map[string]interface{}{"host": acc.Host}
+
+	for name, value := range map[string]string{
+		"identity": acc.Identity,
+		"password": acc.Password,
+		"key":      acc.Key,
+		"secret":   acc.Secret,
+	} {
+		if value != "" {
+			settings[name] = value
+		}
+	}
+
+	return settings
+}
+
+// NewAccount create account in host from auth info, api secret is sealed
+// by default keystore.
+func NewAccount(host string, authInfo *Authentication) (*Account, error) {
+	if !authInfo.Validate() {
+		return nil, common.ErrAccount
+	}
+
+	acc := Account{
+		Host:     host,
+		Identity: authInfo.Identity,
+		Password: authInfo.Password.String(),
+		Key:      authInfo.Key,
+	}
+
+	if authInfo.Secret != "" {
+		store, err := DefaultKeystore()
+		if err != nil {
+			return nil, err
+		}
+
+		if acc.Secret, err = store.Seal(authInfo.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return &acc, nil
+}
+
+// Profile named account list saved in "auths.yaml"
+type Profile struct {
+	Name     string     `mapstructure:"name"`
+	Accounts []*Account `mapstructure:"accounts"`
+}
+
+// HostAccounts get profile's accounts in host
+func (p *Profile) HostAccounts(host string) []*Account {
+	var accounts []*Account
+
+	for _, acc := range p.Accounts {
+		if acc.Host == host {
+			accounts = append(accounts, acc)
+		}
+	}
+
+	return accounts
+}
+
+// ProfileAccount profile account row for output, secrets are not included
+type ProfileAccount struct {
+	Profile string `csv:"profile" json:"profile"`
+	Default bool   `csv:"default" json:"default"`
+	Host    string `csv:"host" json:"host"`
+	ID      string `csv:"id" json:"id"`
+	Type    string `csv:"type" json:"type"`
+}
+
+// Profiles get all saved profiles
+func (cache *AuthCache) Profiles() ([]*Profile, error) {
+	var profiles []*Profile
+
+	err := cache.savedAuths.UnmarshalKey(savedProfilesKey, &profiles)
+
+	return profiles, err
+}
+
+// GetProfile get saved profile by name, nil will be returned if not found
+func (cache *AuthCache) GetProfile(name string) (*Profile, error) {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (cache *AuthCache) setProfiles(profiles []*Profile) {
+	settings := make([]map[string]interface{}, 0, len(profiles))
+
+	for _, profile := range profiles {
+		accounts := make([]map[string]interface{}, 0, len(profile.Accounts))
+
+		for _, acc := range profile.Accounts {
+			accounts = append(accounts, acc.settings())
+		}
+
+		settings = append(settings, map[string]interface{}{
+			"name":     profile.Name,
+			"accounts": accounts,
+		})
+	}
+
+	cache.savedAuths.Set(savedProfilesKey, settings)
+}
+
+// DefaultProfile get default profile name
+func (cache *AuthCache) DefaultProfile() string {
+	if name := cache.savedAuths.GetString(defaultProfileKey); name != "" {
+		return name
+	}
+
+	return DefaultProfileName
+}
+
+// SetDefaultProfile set default profile name, profile should be saved
+func (cache *AuthCache) SetDefaultProfile(name string) error {
+	profile, err := cache.GetProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if profile == nil {
+		return common.ErrProfileNotFound
+	}
+
+	cache.savedAuths.Set(defaultProfileKey, name)
+
+	return nil
+}
+
+// ProfileName get profile name in use, specified by Profile or default
+func (cache *AuthCache) ProfileName() string {
+	if cache.Profile != "" {
+		return cache.Profile
+	}
+
+	return cache.DefaultProfile()
+}
+
+// AddAccount add account to profile, account with the same host & ID
+// will be replaced, profile will be created if not exist.
+func (cache *AuthCache) AddAccount(name string, account *Account) error {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return err
+	}
+
+	var profile *Profile
+
+	for _, p := range profiles {
+		if p.Name == name {
+			profile = p
+			break
+		}
+	}
+
+	if profile == nil {
+		profile = &Profile{Name: name}
+		profiles = append(profiles, profile)
+	}
+
+	replaced := false
+
+	for idx, acc := range profile.Accounts {
+		if acc.Host == account.Host && acc.ID() == account.ID() {
+			profile.Accounts[idx] = account
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		profile.Accounts = append(profile.Accounts, account)
+	}
+
+	cache.setProfiles(profiles)
+
+	return nil
+}
+
+// RemoveAccounts remove accounts in host from profile by ID,
+// count of removed accounts will be returned.
+func (cache *AuthCache) RemoveAccounts(
+	name, host string, ids []string) (int, error) {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return 0, err
+	}
+
+	targets := make(map[string]bool)
+	for _, id := range ids {
+		targets[id] = true
+	}
+
+	for _, profile := range profiles {
+		if profile.Name != name {
+			continue
+		}
+
+		var remains []*Account
+
+		for _, acc := range profile.Accounts {
+			if acc.Host != host || !targets[acc.ID()] {
+				remains = append(remains, acc)
+			}
+		}
+
+		count := len(profile.Accounts) - len(remains)
+		profile.Accounts = remains
+
+		cache.setProfiles(profiles)
+
+		return count, nil
+	}
+
+	return 0, common.ErrProfileNotFound
+}
+
+// RemoveProfile remove profile with all its accounts
+func (cache *AuthCache) RemoveProfile(name string) error {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return err
+	}
+
+	var remains []*Profile
+
+	for _, profile := range profiles {
+		if profile.Name != name {
+			remains = append(remains, profile)
+		}
+	}
+
+	if len(remains) == len(profiles) {
+		return common.ErrProfileNotFound
+	}
+
+	cache.setProfiles(remains)
+
+	if cache.savedAuths.GetString(defaultProfileKey) == name {
+		cache.savedAuths.Set(defaultProfileKey, "")
+	}
+
+	return nil
+}
+
+// ProfileAccounts get account rows of all profiles for output
+func (cache *AuthCache) ProfileAccounts() ([]*ProfileAccount, error) {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return nil, err
+	}
+
+	defaultName := cache.DefaultProfile()
+
+	var rows []*ProfileAccount
+
+	for _, profile := range profiles {
+		for _, acc := range profile.Accounts {
+			rows = append(rows, &ProfileAccount{
+				Profile: profile.Name,
+				Default: profile.Name == defaultName,
+				Host:    acc.Host,
+				ID:      acc.ID(),
+				Type:    acc.Type(),
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+// hasProfileAccounts to judge if profile has accounts in host
+func (cache *AuthCache) hasProfileAccounts(name, host string) bool {
+	profile, err := cache.GetProfile(name)
+
+	return err == nil && profile != nil && len(profile.HostAccounts(host)) > 0
+}
+
+// loadProfile load all profile's accounts in host, api key will be
+// retrieved by login for accounts with password only.
+func (cache *AuthCache) loadProfile(name, host string) error {
+	profile, err := cache.GetProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if profile == nil {
+		return common.ErrProfileNotFound
+	}
+
+	for _, acc := range profile.HostAccounts(host) {
+		authInfo, err := acc.Authentication()
+		if err != nil {
+			logger.Warn("Open account failed.",
+				zap.String("id", acc.ID()), zap.Error(err))
+			common.SetExitError(err)
+
+			continue
+		}
+
+		if authInfo.Key == "" {
+			key, err := cache.loginKey(authInfo.Identity, &authInfo.Password)
+			if err != nil {
+				logger.Warn("Login account failed.",
+					zap.String("id", acc.ID()), zap.Error(err))
+				common.SetExitError(err)
+
+				continue
+			}
+
+			authInfo.APIKey = *key
+		}
+
+		cache.addAuth(authInfo)
+	}
+
+	if len(cache.authList) < 1 {
+		return fmt.Errorf("no valid account in profile %s for host: %s",
+			name, host)
+	}
+
+	return nil
+}
+
+// migrateProfiles re-seal legacy passwords & plain secrets in profiles
+func (cache *AuthCache) migrateProfiles(template *Password) (int, error) {
+	profiles, err := cache.Profiles()
+	if err != nil {
+		return 0, err
+	}
+
+	store, err := DefaultKeystore()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	for _, profile := range profiles {
+		for _, acc := range profile.Accounts {
+			if acc.Password != "" && !IsSealed(acc.Password) {
+				pass := *template
+
+				if err = pass.ShadowSet(acc.Password); err != nil {
+					return count, fmt.Errorf("%s: %s", acc.ID(), err.Error())
+				}
+
+				if err = pass.Migrate(); err != nil {
+					return count, err
+				}
+
+				acc.Password = pass.String()
+				count++
+			}
+
+			if acc.Secret != "" && !IsSealed(acc.Secret) {
+				if acc.Secret, err = store.Seal(acc.Secret); err != nil {
+					return count, err
+				}
+
+				count++
+			}
+		}
+	}
+
+	if count > 0 {
+		cache.setProfiles(profiles)
+	}
+
+	return count, nil
+}
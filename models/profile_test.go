@@ -0,0 +1,113 @@
+package models
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+func TestProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	authFile := filepath.Join(dir, "auths.yaml")
+
+	cache := NewAuthCache(context.Background(), &ClientHub{})
+	cache.SetConfigFile(authFile)
+
+	for _, authInfo := range []*Authentication{
+		{APIKey: APIKey{Key: "K1", Secret: "S1"}},
+		{APIKey: APIKey{Key: "K2", Secret: "S2"}},
+		{APIKey: APIKey{Key: "K2", Secret: "S2-NEW"}},
+	} {
+		acc, err := NewAccount("trade", authInfo)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = cache.AddAccount("test", acc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	other, err := NewAccount("other", &Authentication{
+		APIKey: APIKey{Key: "K3", Secret: "S3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.AddAccount("test", other)
+	cache.AddAccount("prod", other)
+
+	if _, err = NewAccount("trade", &Authentication{Identity: "a@b.com"}); err != common.ErrAccount {
+		t.Error("account without password or key should fail:", err)
+	}
+
+	if err = cache.SetDefaultProfile("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = cache.SetDefaultProfile("missing"); err != common.ErrProfileNotFound {
+		t.Error("set missing default profile should fail:", err)
+	}
+
+	if err = cache.WriteConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewAuthCache(context.Background(), &ClientHub{})
+	reloaded.SetConfigFile(authFile)
+
+	if name := reloaded.ProfileName(); name != "test" {
+		t.Error("default profile mismatch:", name)
+	}
+
+	rows, err := reloaded.ProfileAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 4 || !rows[0].Default || rows[3].Default {
+		t.Error("profile accounts mismatch:", rows)
+	}
+
+	if err = reloaded.loadProfile("test", "trade"); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids, _ := reloaded.AllAuthIDs(); len(ids) != 2 || ids[0] != "K1" || ids[1] != "K2" {
+		t.Error("profile auth ids mismatch:", ids)
+	}
+
+	if reloaded.authMap["K2"].Secret != "S2-NEW" {
+		t.Error("account with same ID should be replaced")
+	}
+
+	count, err := reloaded.RemoveAccounts("test", "trade", []string{"K1", "K3"})
+	if err != nil || count != 1 {
+		t.Error("only account in host should be removed:", count, err)
+	}
+
+	if !reloaded.hasProfileAccounts("test", "other") {
+		t.Error("account in other host should be kept")
+	}
+
+	if err = reloaded.RemoveProfile("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if name := reloaded.DefaultProfile(); name != DefaultProfileName {
+		t.Error("default profile should be reset after removed:", name)
+	}
+
+	if profiles, _ := reloaded.Profiles(); len(profiles) != 1 || profiles[0].Name != "prod" {
+		t.Error("remained profiles mismatch:", profiles)
+	}
+}
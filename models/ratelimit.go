@@ -0,0 +1,275 @@
+package models
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+
+	"github.com/frozenpine/viper"
+)
+
+const (
+	// DefaultConnectTimeout default timeout for dialing & tls handshake
+	DefaultConnectTimeout = 10 * time.Second
+	// DefaultReadTimeout default timeout waiting for response header
+	DefaultReadTimeout = 30 * time.Second
+	// DefaultMaxRetries default retry times for idempotent requests
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff default base delay before first retry
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// DefaultLimitReserve default remaining requests count under which
+	// requests will be slowed down
+	DefaultLimitReserve = 5
+)
+
+// TransportConfig settings for http transport of api clients
+type TransportConfig struct {
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	MaxRetries     int
+	RetryBackoff   time.Duration
+	LimitReserve   int
+}
+
+// LoadTransportConfig load transport settings from viper,
+// defaults are used for missing or invalid values.
+func LoadTransportConfig() TransportConfig {
+	cfg := TransportConfig{
+		ConnectTimeout: viper.GetDuration("connect-timeout"),
+		ReadTimeout:    viper.GetDuration("read-timeout"),
+		MaxRetries:     viper.GetInt("max-retries"),
+		RetryBackoff:   viper.GetDuration("retry-backoff"),
+		LimitReserve:   viper.GetInt("ratelimit-reserve"),
+	}
+
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
+
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = DefaultReadTimeout
+	}
+
+	if !viper.IsSet("max-retries") || cfg.MaxRetries < 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	if !viper.IsSet("ratelimit-reserve") || cfg.LimitReserve < 0 {
+		cfg.LimitReserve = DefaultLimitReserve
+	}
+
+	return cfg
+}
+
+// rateLimit rate limit state of an api key reported by server
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// delay get wait duration before next request, requests are paused until
+// reset if limit exhausted, or spread evenly until reset if remaining
+// requests are less than reserve.
+func (l *rateLimit) delay(now time.Time, reserve int) time.Duration {
+	if l == nil || !l.reset.After(now) {
+		return 0
+	}
+
+	wait := l.reset.Sub(now)
+
+	if l.remaining <= 0 {
+		return wait
+	}
+
+	if l.remaining < reserve {
+		return wait / time.Duration(l.remaining+1)
+	}
+
+	return 0
+}
+
+// rateLimitTransport http transport tracking x-ratelimit-* headers per
+// api key, idempotent requests rejected by 429/503 are retried with
+// jittered exponential backoff.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	config TransportConfig
+
+	limits    map[string]*rateLimit
+	limitLock sync.Mutex
+}
+
+func newRateLimitTransport(cfg TransportConfig) *rateLimitTransport {
+	dialer := net.Dialer{
+		Timeout:   cfg.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &rateLimitTransport{
+		base: &logTransport{base: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   cfg.ConnectTimeout,
+			ResponseHeaderTimeout: cfg.ReadTimeout,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		}},
+		config: cfg,
+		limits: make(map[string]*rateLimit),
+	}
+}
+
+var (
+	sharedTransport     *rateLimitTransport
+	sharedTransportFlag sync.Once
+)
+
+// apiTransport get rate limit transport shared by all api requests,
+// so that rate limit state of the same api key is shared across clients.
+func apiTransport() *rateLimitTransport {
+	sharedTransportFlag.Do(func() {
+		sharedTransport = newRateLimitTransport(LoadTransportConfig())
+	})
+
+	return sharedTransport
+}
+
+// wait block until request allowed by rate limit of its api key,
+// remaining count is consumed in advance for concurrent requests.
+func (t *rateLimitTransport) wait(req *http.Request) error {
+	key := req.Header.Get("api-key")
+
+	t.limitLock.Lock()
+	limit := t.limits[key]
+	delay := limit.delay(time.Now(), t.config.LimitReserve)
+	if limit != nil && limit.remaining > 0 {
+		limit.remaining--
+	}
+	t.limitLock.Unlock()
+
+	return sleepContext(req.Context(), delay)
+}
+
+// update rate limit state of api key from response headers
+func (t *rateLimitTransport) update(key string, rsp *http.Response) {
+	remaining, err := strconv.Atoi(rsp.Header.Get("x-ratelimit-remaining"))
+	if err != nil {
+		return
+	}
+
+	limit := rateLimit{remaining: remaining}
+
+	limit.limit, _ = strconv.Atoi(rsp.Header.Get("x-ratelimit-limit"))
+
+	if reset, err := strconv.ParseInt(
+		rsp.Header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+		limit.reset = time.Unix(reset, 0)
+	}
+
+	t.limitLock.Lock()
+	t.limits[key] = &limit
+	t.limitLock.Unlock()
+}
+
+// retryDelay get delay before next retry, Retry-After or rate limit reset
+// header is respected if server specified.
+func (t *rateLimitTransport) retryDelay(
+	rsp *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(
+		rsp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if rsp.StatusCode == http.StatusTooManyRequests {
+		if reset, err := strconv.ParseInt(
+			rsp.Header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	backoff := t.config.RetryBackoff << uint(attempt)
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func isIdempotent(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+func isRetryable(rsp *http.Response) bool {
+	return rsp.StatusCode == http.StatusTooManyRequests ||
+		rsp.StatusCode == http.StatusServiceUnavailable
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (
+	*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(req); err != nil {
+			return nil, err
+		}
+
+		rsp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		key := req.Header.Get("api-key")
+		t.update(key, rsp)
+
+		if !isRetryable(rsp) || !isIdempotent(req) ||
+			attempt >= t.config.MaxRetries {
+			return rsp, nil
+		}
+
+		delay := t.retryDelay(rsp, attempt)
+
+		io.Copy(ioutil.Discard, rsp.Body)
+		rsp.Body.Close()
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		// signature expires in seconds, so retry request is signed again
+		if apiKey, ok := req.Context().Value(
+			ngerest.ContextAPIKey).(ngerest.APIKey); ok &&
+			key != "" && apiKey.Key == key {
+			if req, err = resignRequest(
+				req, &APIKey{Key: apiKey.Key, Secret: apiKey.Secret}); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Now()
+
+	var empty *rateLimit
+	if empty.delay(now, 5) != 0 {
+		t.Error("unknown rate limit should not delay")
+	}
+
+	exhausted := rateLimit{remaining: 0, reset: now.Add(time.Second)}
+	if exhausted.delay(now, 5) != time.Second {
+		t.Error("exhausted rate limit should wait until reset")
+	}
+
+	near := rateLimit{remaining: 3, reset: now.Add(time.Second)}
+	if near.delay(now, 5) != time.Second/4 {
+		t.Error("requests near rate limit should be spread:", near.delay(now, 5))
+	}
+
+	plenty := rateLimit{remaining: 10, reset: now.Add(time.Second)}
+	if plenty.delay(now, 5) != 0 {
+		t.Error("requests with plenty remaining should not delay")
+	}
+
+	expired := rateLimit{remaining: 0, reset: now.Add(-time.Second)}
+	if expired.delay(now, 5) != 0 {
+		t.Error("rate limit should be reset")
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			count := atomic.AddInt32(&requests, 1)
+
+			expires, _ := strconv.ParseInt(r.Header.Get("api-expires"), 10, 64)
+			if r.Header.Get("api-signature") != common.Signature(
+				"secret", r.Method, r.URL.RequestURI(), expires, "") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("x-ratelimit-limit", "60")
+			w.Header().Set("x-ratelimit-remaining", "42")
+			w.Header().Set("x-ratelimit-reset", strconv.FormatInt(
+				time.Now().Add(time.Minute).Unix(), 10))
+
+			switch {
+			case r.Method == http.MethodGet && count == 1:
+				w.WriteHeader(http.StatusTooManyRequests)
+			case r.Method == http.MethodGet && count == 2:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case r.Method == http.MethodPost:
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				w.Write([]byte("[]"))
+			}
+		}))
+	defer server.Close()
+
+	cfg := TransportConfig{
+		ConnectTimeout: time.Second,
+		ReadTimeout:    time.Second,
+		MaxRetries:     2,
+		RetryBackoff:   time.Millisecond,
+	}
+	transport := newRateLimitTransport(cfg)
+
+	ctx := context.WithValue(context.Background(), ngerest.ContextAPIKey,
+		ngerest.APIKey{Key: "key", Secret: "secret"})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/position", nil)
+	req = req.WithContext(ctx)
+	req.Header.Set("api-key", "key")
+	expires := time.Now().Unix() + 5
+	req.Header.Set("api-expires", strconv.FormatInt(expires, 10))
+	req.Header.Set("api-signature", common.Signature(
+		"secret", "GET", "/position", expires, ""))
+
+	transport.config.MaxRetries = 0
+	rsp, err := transport.RoundTrip(req)
+	if err != nil || rsp.StatusCode != http.StatusTooManyRequests {
+		t.Fatal("request should not be retried without retries:", err)
+	}
+	rsp.Body.Close()
+
+	limit := transport.limits["key"]
+	if limit == nil || limit.limit != 60 || limit.remaining != 42 {
+		t.Fatal("rate limit should be tracked by api key:", limit)
+	}
+
+	transport.config.MaxRetries = cfg.MaxRetries
+	rsp, err = transport.RoundTrip(req)
+	if err != nil || rsp.StatusCode != http.StatusOK {
+		t.Fatal("get request should be retried:", err)
+	}
+	rsp.Body.Close()
+
+	if atomic.LoadInt32(&requests) != 3 {
+		t.Error("request count mismatch:", requests)
+	}
+
+	post, _ := http.NewRequest(http.MethodPost, server.URL+"/order", nil)
+	post = post.WithContext(ctx)
+	post.Header.Set("api-key", "key")
+	post.Header.Set("api-expires", strconv.FormatInt(expires, 10))
+	post.Header.Set("api-signature", common.Signature(
+		"secret", "POST", "/order", expires, ""))
+
+	rsp, err = transport.RoundTrip(post)
+	if err != nil || rsp.StatusCode != http.StatusTooManyRequests {
+		t.Fatal("post request should not be retried:", err)
+	}
+	rsp.Body.Close()
+
+	if atomic.LoadInt32(&requests) != 4 {
+		t.Error("post request should be sent only once:", requests)
+	}
+
+	// exhausted rate limit should pause until reset or ctx cancelled
+	transport.limits["key"].remaining = 0
+
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	if _, err = transport.RoundTrip(
+		req.WithContext(cancelCtx)); err != context.DeadlineExceeded {
+		t.Error("request should be paused when rate limit exhausted:", err)
+	}
+}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// DefaultSessionTTL max duration of cached login session, sessions expire
+// earlier if any login cookie expires before.
+const DefaultSessionTTL = 12 * time.Hour
+
+// Session login session of identity in host, including quant token &
+// cookies from login and user's default api key, secret is sealed by keystore.
+type Session struct {
+	Host     string         `json:"host"`
+	Identity string         `json:"identity"`
+	Token    string         `json:"token"`
+	Cookies  []*http.Cookie `json:"cookies,omitempty"`
+	Key      string         `json:"key"`
+	Secret   string         `json:"secret"`
+	Expires  time.Time      `json:"expires"`
+}
+
+// IsExpired to judge if session is expired
+func (s *Session) IsExpired() bool {
+	return !time.Now().Before(s.Expires)
+}
+
+// Context make login context with session's quant token & cookies
+func (s *Session) Context(parent context.Context) context.Context {
+	return context.WithValue(parent, ngerest.ContextQuantToken,
+		ngerest.QuantToken{Token: s.Token, Cookies: s.Cookies})
+}
+
+// APIKey open session's default api key
+func (s *Session) APIKey() (*APIKey, error) {
+	store, err := DefaultKeystore()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := store.Open(s.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &APIKey{Key: s.Key, Secret: secret}, nil
+}
+
+// NewSession create session from login context & default api key,
+// api secret is sealed by default keystore.
+func NewSession(host, identity string,
+	login context.Context, key *APIKey, ttl time.Duration) (*Session, error) {
+	token, ok := login.Value(ngerest.ContextQuantToken).(ngerest.QuantToken)
+	if !ok {
+		return nil, common.ErrNoSession
+	}
+
+	store, err := DefaultKeystore()
+	if err != nil {
+		return nil, err
+	}
+
+	session := Session{
+		Host:     host,
+		Identity: identity,
+		Token:    token.Token,
+		Cookies:  token.Cookies,
+		Key:      key.Key,
+		Expires:  time.Now().Add(ttl),
+	}
+
+	if session.Secret, err = store.Seal(key.Secret); err != nil {
+		return nil, err
+	}
+
+	for _, cookie := range token.Cookies {
+		expires := cookie.Expires
+
+		if cookie.MaxAge > 0 {
+			expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+
+		if !expires.IsZero() && expires.Before(session.Expires) {
+			session.Expires = expires
+		}
+	}
+
+	return &session, nil
+}
+
+// SessionCache login sessions cached in file, file should
+// only be accessed by owner as sessions contain login token.
+type SessionCache struct {
+	path string
+	lock sync.Mutex
+}
+
+// NewSessionCache create session cache with file path
+func NewSessionCache(path string) *SessionCache {
+	return &SessionCache{path: path}
+}
+
+func sessionKey(host, identity string) string {
+	return savedAuthKey(host, identity)
+}
+
+func (sc *SessionCache) read() (map[string]*Session, error) {
+	sessions := make(map[string]*Session)
+
+	data, err := ioutil.ReadFile(sc.path)
+	if os.IsNotExist(err) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (sc *SessionCache) write(sessions map[string]*Session) error {
+	for key, session := range sessions {
+		if session.IsExpired() {
+			delete(sessions, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(sc.path, data, 0600)
+}
+
+// Get get valid session of identity in host, nil will be returned
+// if session not cached or expired.
+func (sc *SessionCache) Get(host, identity string) *Session {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	sessions, err := sc.read()
+	if err != nil {
+		return nil
+	}
+
+	session, exist := sessions[sessionKey(host, identity)]
+	if !exist || session.IsExpired() {
+		return nil
+	}
+
+	return session
+}
+
+// Save save session to cache file, expired sessions are purged.
+func (sc *SessionCache) Save(session *Session) error {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	sessions, err := sc.read()
+	if err != nil {
+		return err
+	}
+
+	sessions[sessionKey(session.Host, session.Identity)] = session
+
+	return sc.write(sessions)
+}
+
+// Remove remove identity's session in host from cache
+func (sc *SessionCache) Remove(host, identity string) error {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	sessions, err := sc.read()
+	if err != nil {
+		return err
+	}
+
+	delete(sessions, sessionKey(host, identity))
+
+	return sc.write(sessions)
+}
+
+// HostSessions get valid sessions in host sorted by identity
+func (sc *SessionCache) HostSessions(host string) ([]*Session, error) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	sessions, err := sc.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*Session
+
+	for _, session := range sessions {
+		if session.Host == host && !session.IsExpired() {
+			results = append(results, session)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Identity < results[j].Identity
+	})
+
+	return results, nil
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestSessionCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sessions")
+	sessions := NewSessionCache(path)
+
+	login := context.WithValue(context.Background(),
+		ngerest.ContextQuantToken, ngerest.QuantToken{
+			Token:   "TOKEN",
+			Cookies: []*http.Cookie{{Name: "sid", Value: "SID", MaxAge: 60}},
+		})
+
+	session, err := NewSession("trade", "a@b.com", login,
+		&APIKey{Key: "KEY", Secret: "SECRET"}, DefaultSessionTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsSealed(session.Secret) {
+		t.Error("session secret should be sealed:", session.Secret)
+	}
+
+	if session.Expires.After(time.Now().Add(time.Minute)) {
+		t.Error("session should expire with cookie:", session.Expires)
+	}
+
+	if err = sessions.Save(session); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Error("session file should only be accessed by owner:", info.Mode())
+	}
+
+	cached := sessions.Get("trade", "a@b.com")
+	if cached == nil {
+		t.Fatal("session should be cached")
+	}
+
+	if key, err := cached.APIKey(); err != nil || key.Secret != "SECRET" {
+		t.Error("cached api key mismatch:", key, err)
+	}
+
+	token, _ := cached.Context(context.Background()).Value(
+		ngerest.ContextQuantToken).(ngerest.QuantToken)
+	if token.Token != "TOKEN" || len(token.Cookies) != 1 {
+		t.Error("session context mismatch:", token)
+	}
+
+	expired := *session
+	expired.Identity = "c@d.com"
+	expired.Expires = time.Now().Add(-time.Second)
+
+	sessions.Save(&expired)
+
+	if sessions.Get("trade", "c@d.com") != nil {
+		t.Error("expired session should not be returned")
+	}
+
+	if hosts, _ := sessions.HostSessions("trade"); len(hosts) != 1 {
+		t.Error("expired session should be purged:", hosts)
+	}
+
+	if err = sessions.Remove("trade", "a@b.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if sessions.Get("trade", "a@b.com") != nil {
+		t.Error("removed session should not be returned")
+	}
+}
+
+func TestAuthTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+
+			if r.URL.Path == "/logout" {
+				cookie, err := r.Cookie("sid")
+				if r.Header.Get("x-auth-token") != "TOKEN" ||
+					err != nil || cookie.Value != "SID" {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+				return
+			}
+
+			if r.Header.Get("api-key") != "NEW" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			expires, _ := strconv.ParseInt(r.Header.Get("api-expires"), 10, 64)
+
+			if r.Header.Get("api-signature") != common.Signature(
+				"NEW-SECRET", r.Method, r.URL.RequestURI(),
+				expires, string(body)) {
+				w.WriteHeader(http.StatusForbidden)
+			}
+		}))
+	defer server.Close()
+
+	hub := &ClientHub{}
+	client := http.Client{
+		Transport: &authTransport{base: http.DefaultTransport, hub: hub},
+	}
+
+	send := func() *http.Response {
+		req, _ := http.NewRequest(
+			"POST", server.URL+"/order?symbol=XBTUSD", strings.NewReader("qty=1"))
+		req.Header.Set("api-key", "OLD")
+
+		rsp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+
+		return rsp
+	}
+
+	if rsp := send(); rsp.StatusCode != http.StatusUnauthorized {
+		t.Error("request should not be retried without refresher:", rsp.Status)
+	}
+
+	refreshCount := 0
+
+	hub.SetAuthRefresher(func(key string) (*APIKey, error) {
+		refreshCount++
+
+		if key != "OLD" {
+			t.Error("refreshed key mismatch:", key)
+		}
+
+		return &APIKey{Key: "NEW", Secret: "NEW-SECRET"}, nil
+	})
+
+	if rsp := send(); rsp.StatusCode != http.StatusOK || refreshCount != 1 {
+		t.Error("request should be retried with new key:", rsp.Status)
+	}
+
+	login := context.WithValue(context.Background(),
+		ngerest.ContextQuantToken, ngerest.QuantToken{
+			Token:   "TOKEN",
+			Cookies: []*http.Cookie{{Name: "sid", Value: "SID"}},
+		})
+
+	req, _ := http.NewRequest("POST", server.URL+"/logout", nil)
+
+	rsp, err := client.Do(req.WithContext(login))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		t.Error("login token & cookies should be attached:", rsp.Status)
+	}
+}
+
+func TestRefreshKey(t *testing.T) {
+	cache := NewAuthCache(context.Background(), &ClientHub{})
+
+	cache.addAuth(&Authentication{APIKey: APIKey{Key: "KEY", Secret: "SECRET"}})
+
+	if _, err := cache.refreshKey("KEY"); err != common.ErrRelogin {
+		t.Error("api key auth should not be refreshed:", err)
+	}
+
+	if _, err := cache.refreshKey("MISSING"); err != common.ErrRelogin {
+		t.Error("unknown api key should not be refreshed:", err)
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/gocarina/gocsv"
+)
+
+// sourceHandler handle record value parsed from source file with line number
+type sourceHandler func(line int, value interface{}, err error)
+
+func readSourceCSV(src io.Reader, sample interface{}, handler sourceHandler) {
+	reader := csv.NewReader(src)
+
+	unmarshaller, err := gocsv.NewUnmarshaller(reader, sample)
+	if err != nil {
+		handler(1, nil, err)
+		return
+	}
+
+	// line 1 is csv header
+	for line := 2; ; line++ {
+		row, err := unmarshaller.Read()
+
+		if err == io.EOF {
+			return
+		}
+
+		handler(line, row, err)
+	}
+}
+
+func readSourceJSON(src io.Reader, sample interface{}, handler sourceHandler) {
+	scanner := bufio.NewScanner(src)
+
+	valueType := reflect.TypeOf(sample).Elem()
+
+	for line := 1; scanner.Scan(); line++ {
+		content := strings.TrimSpace(scanner.Text())
+
+		if content == "" {
+			continue
+		}
+
+		value := reflect.New(valueType).Interface()
+
+		if err := json.Unmarshal([]byte(content), value); err != nil {
+			handler(line, nil, err)
+			continue
+		}
+
+		handler(line, value, nil)
+	}
+
+	if err := scanner.Err(); err != nil {
+		handler(0, nil, err)
+	}
+}
+
+// readSourceFile read records from source file in stream,
+// sample must be a struct pointer with csv & json tags,
+// csv file must has a header line with sample's csv tag names,
+// json file must contains one json object per line.
+func readSourceFile(
+	path string, sample interface{}, handler sourceHandler,
+	done func()) error {
+	var reader func(io.Reader, interface{}, sourceHandler)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		reader = readSourceCSV
+	case ".json", ".jsonl":
+		reader = readSourceJSON
+	default:
+		return common.ErrSourceFormat
+	}
+
+	srcFile, err := os.OpenFile(path, os.O_RDONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer func() {
+			srcFile.Close()
+			done()
+		}()
+
+		reader(srcFile, sample, handler)
+	}()
+
+	return nil
+}
+
+// OrderRecord order record read from order source file
+type OrderRecord struct {
+	Line  int
+	Order *Order
+	Err   error
+}
+
+// ReadOrderFile read orders from source file in stream,
+// csv file must has a header line with Order's csv tag names,
+// json file must contains one json order object per line.
+func ReadOrderFile(path string) (<-chan *OrderRecord, error) {
+	records := make(chan *OrderRecord)
+
+	handler := func(line int, value interface{}, err error) {
+		record := OrderRecord{Line: line, Err: err}
+
+		if err == nil {
+			record.Order = value.(*Order)
+		}
+
+		records <- &record
+	}
+
+	if err := readSourceFile(
+		path, &Order{}, handler, func() { close(records) }); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOrderFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	csvContent := `symbol,side,orderQty,price
+XBTUSD,Buy,10,5000
+,,-3,5001
+,Buy,abc,5002
+`
+
+	csvPath := filepath.Join(tmpDir, "orders.csv")
+	if err := ioutil.WriteFile(csvPath, []byte(csvContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadOrderFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var valid, invalid []*OrderRecord
+
+	for record := range records {
+		if record.Err != nil {
+			invalid = append(invalid, record)
+		} else {
+			valid = append(valid, record)
+		}
+	}
+
+	if len(valid) != 2 || len(invalid) != 1 {
+		t.Fatalf("valid: %d, invalid: %d", len(valid), len(invalid))
+	}
+
+	if invalid[0].Line != 4 {
+		t.Fatal("invalid record line miss-match:", invalid[0].Line)
+	}
+
+	if valid[1].Order.Side != "" {
+		t.Fatal("empty side should be left unset.")
+	}
+
+	if _, err := ReadOrderFile(filepath.Join(tmpDir, "orders.xml")); err == nil {
+		t.Fatal("unsupported format should be rejected.")
+	}
+}
+
+func TestReadExportedSellOrder(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "ngecli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// sell orders are exported with positive quantity
+	csvContent := `orderID,symbol,side,orderQty,price
+ord1,XBTUSD,Sell,10,5000
+`
+
+	csvPath := filepath.Join(tmpDir, "orders.csv")
+	if err := ioutil.WriteFile(csvPath, []byte(csvContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := ReadOrderFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for record := range records {
+		if record.Err != nil {
+			t.Fatal(record.Err)
+		}
+
+		ord := record.Order
+
+		if err := ord.Side.MatchSide(int64(ord.OrderQty)); err != nil {
+			t.Fatal("exported sell order rejected:", err)
+		}
+
+		if ord.Side != Sell {
+			t.Fatal("side miss-match:", ord.Side)
+		}
+	}
+}
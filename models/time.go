@@ -1,24 +1,218 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
-// FlagTime time format used in flag parse.
-type FlagTime time.Time
+	"github.com/frozenpine/ngecli/common"
+)
 
-// String get time string.
+const (
+	// DefaultTimeZone time zone used if not specified
+	DefaultTimeZone = "UTC"
+
+	javaTimeCSVLayout  = "2006-01-02 15:04:05.000 Z0700 MST"
+	javaTimeJSONLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+var (
+	// layouts without zone, parsed in time zone
+	localLayouts = []string{
+		"2006-01-02 15:04:05.000",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05.000",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+
+	// clock layouts following today/yesterday
+	clockLayouts = []string{"15:04:05", "15:04"}
+
+	relativePattern = regexp.MustCompile(`^([+-])(\d+)([dw])$`)
+	offsetPattern   = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+	timeZone     = time.UTC
+	timeZoneLock sync.RWMutex
+)
+
+// LoadTimeZone load time zone by IANA name, "Local" or offset like +08:00
+func LoadTimeZone(name string) (*time.Location, error) {
+	if match := offsetPattern.FindStringSubmatch(name); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ := strconv.Atoi(match[3])
+
+		offset := hours*3600 + minutes*60
+		if match[1] == "-" {
+			offset = -offset
+		}
+
+		return time.FixedZone(name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, common.ErrTimeZone
+	}
+
+	return loc, nil
+}
+
+// SetTimeZone set time zone used in parsing & formatting times
+func SetTimeZone(name string) error {
+	loc, err := LoadTimeZone(name)
+	if err != nil {
+		return err
+	}
+
+	timeZoneLock.Lock()
+	timeZone = loc
+	timeZoneLock.Unlock()
+
+	return nil
+}
+
+// TimeZone get time zone used in parsing & formatting times
+func TimeZone() *time.Location {
+	timeZoneLock.RLock()
+	defer timeZoneLock.RUnlock()
+
+	return timeZone
+}
+
+// midnight get start of day of t in its location
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// parseDay parse today/yesterday with optional clock time
+func parseDay(value string, now time.Time) (time.Time, bool) {
+	parts := strings.Fields(value)
+
+	var day time.Time
+
+	switch strings.ToLower(parts[0]) {
+	case "today":
+		day = midnight(now)
+	case "yesterday":
+		day = midnight(now).AddDate(0, 0, -1)
+	default:
+		return day, false
+	}
+
+	switch len(parts) {
+	case 1:
+		return day, true
+	case 2:
+		for _, layout := range clockLayouts {
+			if clock, err := time.Parse(layout, parts[1]); err == nil {
+				return day.Add(clock.Sub(midnight(clock))), true
+			}
+		}
+	}
+
+	return day, false
+}
+
+// parseRelative parse duration relative to now, such as -2h, +30m, -1d, -1w
+func parseRelative(value string, now time.Time) (time.Time, bool) {
+	if match := relativePattern.FindStringSubmatch(value); match != nil {
+		count, _ := strconv.Atoi(match[2])
+
+		if match[3] == "w" {
+			count *= 7
+		}
+
+		if match[1] == "-" {
+			count = -count
+		}
+
+		return now.AddDate(0, 0, count), true
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(duration), true
+	}
+
+	return now, false
+}
+
+// ParseTime parse time expression in location, supported:
+// RFC3339, date with optional time, epoch milliseconds,
+// duration relative to now like -2h or -1d, now,
+// today or yesterday with optional clock time like "yesterday 09:00".
+func ParseTime(value string, loc *time.Location, now time.Time) (
+	time.Time, error) {
+	value = strings.TrimSpace(value)
+	now = now.In(loc)
+
+	if value == "" {
+		return time.Time{}, common.ErrTimeFormat
+	}
+
+	if strings.EqualFold(value, "now") {
+		return now, nil
+	}
+
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		if parsed, ok := parseRelative(value, now); ok {
+			return parsed, nil
+		}
+
+		return time.Time{}, common.ErrTimeFormat
+	}
+
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond)).In(loc), nil
+	}
+
+	if parsed, ok := parseDay(value, now); ok {
+		return parsed, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, javaTimeCSVLayout} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.In(loc), nil
+		}
+	}
+
+	for _, layout := range localLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, common.ErrTimeFormat
+}
+
+// FlagTime time flag, expression is validated when set & resolved in
+// time zone when first used, so that relative expressions & time zone
+// flag are applied regardless of flag order, and the resolved time is
+// reused in following calls, such as queries of each page.
+type FlagTime struct {
+	expr     string
+	resolved time.Time
+}
+
+// String get time expression.
 func (t *FlagTime) String() string {
-	return time.Time(*t).Format("2006-01-02 15:04:05")
+	return t.expr
 }
 
-// Set set by time string.
+// Set set by time expression.
 func (t *FlagTime) Set(value string) error {
-	parsed, err := time.Parse("2006-01-02 15:04:05", value)
-
-	if err == nil {
-		*t = FlagTime(parsed)
+	if _, err := ParseTime(value, TimeZone(), time.Now()); err != nil {
+		return err
 	}
 
-	return err
+	t.expr, t.resolved = value, time.Time{}
+
+	return nil
 }
 
 // Type get format type string.
@@ -26,37 +220,99 @@ func (t *FlagTime) Type() string {
 	return "FlagTime"
 }
 
-// GetTime get Time format
-func (t *FlagTime) GetTime() time.Time {
-	return time.Time(*t)
+// Reset clear time expression.
+func (t *FlagTime) Reset() {
+	t.expr, t.resolved = "", time.Time{}
 }
 
-func genEmptyTime() FlagTime {
-	var emptyTime, _ = time.Parse("2006-01-02 15:04:05", "0001-01-01 00:00:00")
-
-	return FlagTime(emptyTime)
+// IsSet to judge if time is set
+func (t *FlagTime) IsSet() bool {
+	return t.expr != ""
 }
 
-// EmptyTime 0001-01-01 00:00:00
-var EmptyTime = genEmptyTime()
+// GetTime get time in UTC, expression is resolved in the first call,
+// zero time will be returned if not set
+func (t *FlagTime) GetTime() time.Time {
+	if !t.IsSet() {
+		return time.Time{}
+	}
+
+	if t.resolved.IsZero() {
+		parsed, _ := ParseTime(t.expr, TimeZone(), time.Now())
+
+		t.resolved = parsed.UTC()
+	}
+
+	return t.resolved
+}
 
-// JavaTime java timestamp format
+// JavaTime java timestamp format, milliseconds since epoch
 type JavaTime int64
 
+// NewJavaTime convert time to java time
+func NewJavaTime(t time.Time) JavaTime {
+	return JavaTime(t.UnixNano() / int64(time.Millisecond))
+}
+
+// Time get time in time zone
+func (t JavaTime) Time() time.Time {
+	return time.Unix(0, int64(t)*int64(time.Millisecond)).In(TimeZone())
+}
+
 // MarshalCSV marshal java time to csv string.
-func (t JavaTime) MarshalCSV() string {
-	time := time.Unix(0, int64(t)*int64(time.Millisecond))
-	return time.Format("2006-01-02 15:04:05.000 Z0700 MST")
+func (t JavaTime) MarshalCSV() (string, error) {
+	if t == 0 {
+		return "", nil
+	}
+
+	return t.Time().Format(javaTimeCSVLayout), nil
 }
 
 // UnmarshalCSV unmarshal csv string to java time
-func (t JavaTime) UnmarshalCSV(value string) error {
-	time, err := time.Parse("2006-01-02 15:04:05.000 Z0700 MST", value)
+func (t *JavaTime) UnmarshalCSV(value string) error {
+	if value == "" {
+		*t = 0
+		return nil
+	}
 
+	parsed, err := ParseTime(value, TimeZone(), time.Now())
 	if err != nil {
 		return err
 	}
 
-	t = JavaTime(time.UnixNano() / 1000)
+	*t = NewJavaTime(parsed)
+
+	return nil
+}
+
+// MarshalJSON marshal java time to RFC3339 string with milliseconds.
+func (t JavaTime) MarshalJSON() ([]byte, error) {
+	if t == 0 {
+		return []byte("null"), nil
+	}
+
+	return []byte(strconv.Quote(t.Time().Format(javaTimeJSONLayout))), nil
+}
+
+// UnmarshalJSON unmarshal java time from epoch milliseconds or time string.
+func (t *JavaTime) UnmarshalJSON(data []byte) error {
+	value := string(data)
+
+	if value == "null" {
+		*t = 0
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return t.UnmarshalCSV(unquoted)
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return common.ErrTimeFormat
+	}
+
+	*t = JavaTime(ms)
+
 	return nil
 }
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+func TestParseTime(t *testing.T) {
+	loc, err := LoadTimeZone("+08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2019, 5, 20, 3, 30, 0, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"now":                               now,
+		"-2h":                               now.Add(-2 * time.Hour),
+		"+30m":                              now.Add(30 * time.Minute),
+		"-1d":                               now.AddDate(0, 0, -1),
+		"-1w":                               now.AddDate(0, 0, -7),
+		"1558323000000":                     now,
+		"2019-05-20T03:30:00Z":              now,
+		"2019-05-20T11:30:00.000+08:00":     now,
+		"2019-05-20 11:30:00":               now,
+		"2019-05-20 11:30":                  now,
+		"2019-05-20":                        time.Date(2019, 5, 19, 16, 0, 0, 0, time.UTC),
+		"today":                             time.Date(2019, 5, 19, 16, 0, 0, 0, time.UTC),
+		"yesterday 09:00":                   time.Date(2019, 5, 19, 1, 0, 0, 0, time.UTC),
+		"2019-05-20 11:30:00.000 +0800 CST": now,
+	}
+
+	for expr, expect := range cases {
+		parsed, err := ParseTime(expr, loc, now)
+		if err != nil {
+			t.Error(expr, "parse failed:", err)
+			continue
+		}
+
+		if !parsed.Equal(expect) {
+			t.Error(expr, "parsed mismatch:", parsed, "expect:", expect)
+		}
+	}
+
+	for _, expr := range []string{"", "-2x", "tomorrow", "today 25:00", "2019/05/20"} {
+		if _, err := ParseTime(expr, loc, now); err != common.ErrTimeFormat {
+			t.Error(expr, "should be invalid")
+		}
+	}
+
+	if _, err := LoadTimeZone("Mars/Olympus"); err != common.ErrTimeZone {
+		t.Error("unknown time zone should be invalid")
+	}
+}
+
+func TestFlagTime(t *testing.T) {
+	var flag FlagTime
+
+	if flag.IsSet() || !flag.GetTime().IsZero() {
+		t.Error("empty flag should not be set")
+	}
+
+	if err := flag.Set("yesterday"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !flag.IsSet() || flag.GetTime().Location() != time.UTC {
+		t.Error("flag time should be set in UTC")
+	}
+
+	if err := flag.Set("now"); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved := flag.GetTime()
+	time.Sleep(time.Millisecond)
+
+	if !flag.GetTime().Equal(resolved) {
+		t.Error("flag time should be resolved only once")
+	}
+
+	if err := flag.Set("invalid"); err == nil || flag.String() != "now" {
+		t.Error("invalid time should not be set")
+	}
+
+	flag.Reset()
+
+	if flag.IsSet() {
+		t.Error("flag should be reset")
+	}
+}
+
+func TestJavaTime(t *testing.T) {
+	if err := SetTimeZone("Asia/Shanghai"); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTimeZone(DefaultTimeZone)
+
+	origin := JavaTime(1558323000123)
+
+	value, err := origin.MarshalCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value != "2019-05-20 11:30:00.123 +0800 CST" {
+		t.Error("csv value mismatch:", value)
+	}
+
+	var parsed JavaTime
+
+	if err = parsed.UnmarshalCSV(value); err != nil || parsed != origin {
+		t.Error("csv round trip mismatch:", parsed, err)
+	}
+
+	type record struct {
+		Timestamp JavaTime `json:"timestamp"`
+	}
+
+	data, err := json.Marshal(record{Timestamp: origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"timestamp":"2019-05-20T11:30:00.123+08:00"}` {
+		t.Error("json value mismatch:", string(data))
+	}
+
+	for _, content := range []string{
+		string(data), `{"timestamp":1558323000123}`,
+	} {
+		var result record
+
+		if err = json.Unmarshal([]byte(content), &result); err != nil ||
+			result.Timestamp != origin {
+			t.Error("json round trip mismatch:", content, result, err)
+		}
+	}
+
+	var result record
+
+	if err = json.Unmarshal([]byte(`{"timestamp":null}`), &result); err != nil ||
+		result.Timestamp != 0 {
+		t.Error("null should be zero time:", result, err)
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngerest"
+
+	"go.uber.org/zap"
+)
+
+// Trade trade table
+type Trade struct {
+	Timestamp       time.Time `csv:"timestamp" json:"timestamp"`
+	Symbol          string    `csv:"symbol" json:"symbol"`
+	Side            OrderSide `csv:"side,omitempty" json:"side,omitempty"`
+	Size            float32   `csv:"size,omitempty" json:"size,omitempty"`
+	Price           float64   `csv:"price,omitempty" json:"price,omitempty"`
+	TickDirection   string    `csv:"tickDirection,omitempty" json:"tickDirection,omitempty"`
+	TrdMatchID      string    `csv:"trdMatchID,omitempty" json:"trdMatchID,omitempty"`
+	GrossValue      float32   `csv:"grossValue,omitempty" json:"grossValue,omitempty"`
+	HomeNotional    float64   `csv:"homeNotional,omitempty" json:"homeNotional,omitempty"`
+	ForeignNotional float64   `csv:"foreignNotional,omitempty" json:"foreignNotional,omitempty"`
+}
+
+// TradeBin trade bucketed in bin size
+type TradeBin struct {
+	Timestamp       time.Time `csv:"timestamp" json:"timestamp"`
+	Symbol          string    `csv:"symbol" json:"symbol"`
+	Open            float64   `csv:"open,omitempty" json:"open,omitempty"`
+	High            float64   `csv:"high,omitempty" json:"high,omitempty"`
+	Low             float64   `csv:"low,omitempty" json:"low,omitempty"`
+	Close           float64   `csv:"close,omitempty" json:"close,omitempty"`
+	Trades          float32   `csv:"trades,omitempty" json:"trades,omitempty"`
+	Volume          float32   `csv:"volume,omitempty" json:"volume,omitempty"`
+	Vwap            float64   `csv:"vwap,omitempty" json:"vwap,omitempty"`
+	LastSize        float32   `csv:"lastSize,omitempty" json:"lastSize,omitempty"`
+	Turnover        float32   `csv:"turnover,omitempty" json:"turnover,omitempty"`
+	HomeNotional    float64   `csv:"homeNotional,omitempty" json:"homeNotional,omitempty"`
+	ForeignNotional float64   `csv:"foreignNotional,omitempty" json:"foreignNotional,omitempty"`
+}
+
+// TradeBinSize valid bin size for trade bucket
+var TradeBinSize = []string{"1m", "5m", "1h", "1d"}
+
+// CheckBinSize check if bin size is valid
+func CheckBinSize(binSize string) bool {
+	for _, size := range TradeBinSize {
+		if binSize == size {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TradeCache is a trade & trade bucket output channel
+type TradeCache struct {
+	results chan *Trade
+	buckets chan *TradeBin
+}
+
+// PutResult puts trade result into cache
+func (cache *TradeCache) PutResult(td *ngerest.Trade) {
+	converted, err := ConvertTrade(td)
+	if err != nil {
+		reportConvertError("trade", td, err)
+		return
+	}
+
+	cache.results <- converted
+}
+
+// GetResults to get trade results channel
+func (cache *TradeCache) GetResults() <-chan *Trade { return cache.results }
+
+// CloseResults to close trade results channel
+func (cache *TradeCache) CloseResults() { close(cache.results) }
+
+// PutBucket puts trade bucket result into cache
+func (cache *TradeCache) PutBucket(bin *ngerest.TradeBin) {
+	converted, err := ConvertTradeBin(bin)
+	if err != nil {
+		reportConvertError("trade bucket", bin, err)
+		return
+	}
+
+	cache.buckets <- converted
+}
+
+// GetBuckets to get trade bucket results channel
+func (cache *TradeCache) GetBuckets() <-chan *TradeBin { return cache.buckets }
+
+// CloseBuckets to close trade bucket results channel
+func (cache *TradeCache) CloseBuckets() { close(cache.buckets) }
+
+// NewTradeCache to make new trade cache
+func NewTradeCache() *TradeCache {
+	cache := TradeCache{
+		results: make(chan *Trade),
+		buckets: make(chan *TradeBin),
+	}
+
+	return &cache
+}
+
+// convertModel convert ngerest model to local model with same field names
+func convertModel(ori, converted interface{}) error {
+	var buff bytes.Buffer
+
+	enc := gob.NewEncoder(&buff)
+	dec := gob.NewDecoder(&buff)
+
+	if err := enc.Encode(ori); err != nil {
+		return err
+	}
+
+	return dec.Decode(converted)
+}
+
+// reportConvertError log failed conversion with origin model,
+// and report it as command's exit error.
+func reportConvertError(name string, ori interface{}, err error) {
+	jsonBytes, _ := json.Marshal(ori)
+
+	logger.Error("Convert "+name+" failed.",
+		zap.Error(err), zap.ByteString("origin", jsonBytes))
+
+	common.SetExitError(err)
+}
+
+// ConvertTrade convert ngerest.Trade structure to local Trade structure
+func ConvertTrade(ori *ngerest.Trade) (*Trade, error) {
+	var converted Trade
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// ConvertTradeBin convert ngerest.TradeBin structure to local TradeBin structure
+func ConvertTradeBin(ori *ngerest.TradeBin) (*TradeBin, error) {
+	var converted TradeBin
+
+	if err := convertModel(ori, &converted); err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestConvertTrade(t *testing.T) {
+	now := time.Now()
+
+	converted, err := ConvertTrade(&ngerest.Trade{
+		Timestamp: now,
+		Symbol:    "XBTUSD",
+		Side:      "Sell",
+		Size:      10,
+		Price:     5000.5,
+	})
+
+	if err != nil {
+		t.Fatal("convert trade failed:", err)
+	}
+
+	if !converted.Timestamp.Equal(now) || converted.Side != Sell ||
+		converted.Size != 10 || converted.Price != 5000.5 {
+		t.Fatal("converted trade miss-match:", converted)
+	}
+
+	if !CheckBinSize("5m") || CheckBinSize("2m") {
+		t.Fatal("check bin size failed.")
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// AuthRefresher refresh expired api key, new api key will be returned
+type AuthRefresher func(key string) (*APIKey, error)
+
+// authTransport http transport used by ngerest clients,
+// quant token & cookies in request context are attached to request,
+// requests rejected with 401/403 are retried once after api key refreshed.
+type authTransport struct {
+	base http.RoundTripper
+	hub  *ClientHub
+}
+
+// cloneRequest shallow copy request with deep copied header,
+// as RoundTripper should not modify original request.
+func cloneRequest(req *http.Request) *http.Request {
+	clone := req.WithContext(req.Context())
+
+	clone.Header = make(http.Header, len(req.Header))
+	for name, values := range req.Header {
+		clone.Header[name] = append([]string(nil), values...)
+	}
+
+	return clone
+}
+
+func isAuthExpired(rsp *http.Response) bool {
+	return rsp.StatusCode == http.StatusUnauthorized ||
+		rsp.StatusCode == http.StatusForbidden
+}
+
+// resignRequest make a copy of request signed by new api key
+func resignRequest(req *http.Request, key *APIKey) (*http.Request, error) {
+	// context carries new api key, so that signature can be renewed
+	retry := cloneRequest(req.WithContext(context.WithValue(
+		req.Context(), ngerest.ContextAPIKey,
+		ngerest.APIKey{Key: key.Key, Secret: key.Secret})))
+
+	var body []byte
+
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return nil, err
+		}
+
+		retry.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	// signed path is the same as ngerest client
+	path := req.URL.Path
+	if req.URL.RawQuery != "" {
+		path = path + "?" + req.URL.RawQuery
+	}
+
+	expires := time.Now().Unix() + 5
+
+	retry.Header.Set("api-key", key.Key)
+	retry.Header.Set("api-expires", strconv.FormatInt(expires, 10))
+	retry.Header.Set("api-signature", common.Signature(
+		key.Secret, req.Method, path, expires, string(body)))
+
+	return retry, nil
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token, ok := req.Context().Value(
+		ngerest.ContextQuantToken).(ngerest.QuantToken); ok {
+		req = cloneRequest(req)
+
+		if req.Header.Get("x-auth-token") == "" {
+			req.Header.Set("x-auth-token", token.Token)
+		}
+
+		for _, cookie := range token.Cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	rsp, err := t.base.RoundTrip(req)
+	if err != nil || !isAuthExpired(rsp) {
+		return rsp, err
+	}
+
+	key := req.Header.Get("api-key")
+	refresh := t.hub.refresher()
+
+	// request body can not be replayed without GetBody
+	replayable := req.Body == nil || req.Body == http.NoBody ||
+		req.GetBody != nil
+
+	if key == "" || refresh == nil || !replayable {
+		return rsp, nil
+	}
+
+	newKey, err := refresh(key)
+	if err != nil {
+		return rsp, nil
+	}
+
+	retry, err := resignRequest(req, newKey)
+	if err != nil {
+		return rsp, nil
+	}
+
+	io.Copy(ioutil.Discard, rsp.Body)
+	rsp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
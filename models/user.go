@@ -0,0 +1,248 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+// Account models below are converted from ngerest models with an extra
+// Identity column, so rows of multiple accounts can be output in one table.
+
+// User user info
+type User struct {
+	Identity     string    `csv:"identity" json:"identity"`
+	ID           float32   `csv:"id,omitempty" json:"id,omitempty"`
+	OwnerID      float32   `csv:"ownerId,omitempty" json:"ownerId,omitempty"`
+	Firstname    string    `csv:"firstname,omitempty" json:"firstname,omitempty"`
+	Lastname     string    `csv:"lastname,omitempty" json:"lastname,omitempty"`
+	Username     string    `csv:"username" json:"username"`
+	Email        string    `csv:"email" json:"email"`
+	Phone        string    `csv:"phone,omitempty" json:"phone,omitempty"`
+	Created      time.Time `csv:"created,omitempty" json:"created,omitempty"`
+	LastUpdated  time.Time `csv:"lastUpdated,omitempty" json:"lastUpdated,omitempty"`
+	TFAEnabled   string    `csv:"TFAEnabled,omitempty" json:"TFAEnabled,omitempty"`
+	AffiliateID  string    `csv:"affiliateID,omitempty" json:"affiliateID,omitempty"`
+	PgpPubKey    string    `csv:"pgpPubKey,omitempty" json:"pgpPubKey,omitempty"`
+	Country      string    `csv:"country,omitempty" json:"country,omitempty"`
+	GeoipCountry string    `csv:"geoipCountry,omitempty" json:"geoipCountry,omitempty"`
+	GeoipRegion  string    `csv:"geoipRegion,omitempty" json:"geoipRegion,omitempty"`
+	Typ          string    `csv:"typ,omitempty" json:"typ,omitempty"`
+}
+
+// Margin margin status
+type Margin struct {
+	Identity           string    `csv:"identity" json:"identity"`
+	Account            float32   `csv:"account" json:"account"`
+	Currency           string    `csv:"currency" json:"currency"`
+	RiskLimit          float32   `csv:"riskLimit,omitempty" json:"riskLimit,omitempty"`
+	PrevState          string    `csv:"prevState,omitempty" json:"prevState,omitempty"`
+	State              string    `csv:"state,omitempty" json:"state,omitempty"`
+	Action             string    `csv:"action,omitempty" json:"action,omitempty"`
+	Amount             float32   `csv:"amount,omitempty" json:"amount,omitempty"`
+	PendingCredit      float32   `csv:"pendingCredit,omitempty" json:"pendingCredit,omitempty"`
+	PendingDebit       float32   `csv:"pendingDebit,omitempty" json:"pendingDebit,omitempty"`
+	ConfirmedDebit     float32   `csv:"confirmedDebit,omitempty" json:"confirmedDebit,omitempty"`
+	PrevRealisedPnl    float32   `csv:"prevRealisedPnl,omitempty" json:"prevRealisedPnl,omitempty"`
+	PrevUnrealisedPnl  float32   `csv:"prevUnrealisedPnl,omitempty" json:"prevUnrealisedPnl,omitempty"`
+	GrossComm          float32   `csv:"grossComm,omitempty" json:"grossComm,omitempty"`
+	GrossOpenCost      float32   `csv:"grossOpenCost,omitempty" json:"grossOpenCost,omitempty"`
+	GrossOpenPremium   float32   `csv:"grossOpenPremium,omitempty" json:"grossOpenPremium,omitempty"`
+	GrossExecCost      float32   `csv:"grossExecCost,omitempty" json:"grossExecCost,omitempty"`
+	GrossMarkValue     float32   `csv:"grossMarkValue,omitempty" json:"grossMarkValue,omitempty"`
+	RiskValue          float32   `csv:"riskValue,omitempty" json:"riskValue,omitempty"`
+	TaxableMargin      float32   `csv:"taxableMargin,omitempty" json:"taxableMargin,omitempty"`
+	InitMargin         float32   `csv:"initMargin,omitempty" json:"initMargin,omitempty"`
+	MaintMargin        float32   `csv:"maintMargin,omitempty" json:"maintMargin,omitempty"`
+	SessionMargin      float32   `csv:"sessionMargin,omitempty" json:"sessionMargin,omitempty"`
+	TargetExcessMargin float32   `csv:"targetExcessMargin,omitempty" json:"targetExcessMargin,omitempty"`
+	VarMargin          float32   `csv:"varMargin,omitempty" json:"varMargin,omitempty"`
+	RealisedPnl        float32   `csv:"realisedPnl,omitempty" json:"realisedPnl,omitempty"`
+	UnrealisedPnl      float32   `csv:"unrealisedPnl,omitempty" json:"unrealisedPnl,omitempty"`
+	IndicativeTax      float32   `csv:"indicativeTax,omitempty" json:"indicativeTax,omitempty"`
+	UnrealisedProfit   float32   `csv:"unrealisedProfit,omitempty" json:"unrealisedProfit,omitempty"`
+	SyntheticMargin    float32   `csv:"syntheticMargin,omitempty" json:"syntheticMargin,omitempty"`
+	WalletBalance      float32   `csv:"walletBalance,omitempty" json:"walletBalance,omitempty"`
+	MarginBalance      float32   `csv:"marginBalance,omitempty" json:"marginBalance,omitempty"`
+	MarginBalancePcnt  float64   `csv:"marginBalancePcnt,omitempty" json:"marginBalancePcnt,omitempty"`
+	MarginLeverage     float64   `csv:"marginLeverage,omitempty" json:"marginLeverage,omitempty"`
+	MarginUsedPcnt     float64   `csv:"marginUsedPcnt,omitempty" json:"marginUsedPcnt,omitempty"`
+	ExcessMargin       float32   `csv:"excessMargin,omitempty" json:"excessMargin,omitempty"`
+	ExcessMarginPcnt   float64   `csv:"excessMarginPcnt,omitempty" json:"excessMarginPcnt,omitempty"`
+	AvailableMargin    float32   `csv:"availableMargin,omitempty" json:"availableMargin,omitempty"`
+	WithdrawableMargin float32   `csv:"withdrawableMargin,omitempty" json:"withdrawableMargin,omitempty"`
+	Timestamp          time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+	GrossLastValue     float32   `csv:"grossLastValue,omitempty" json:"grossLastValue,omitempty"`
+	Commission         float64   `csv:"commission,omitempty" json:"commission,omitempty"`
+}
+
+// Wallet wallet info
+type Wallet struct {
+	Identity         string    `csv:"identity" json:"identity"`
+	Account          float32   `csv:"account" json:"account"`
+	Currency         string    `csv:"currency" json:"currency"`
+	PrevDeposited    float32   `csv:"prevDeposited,omitempty" json:"prevDeposited,omitempty"`
+	PrevWithdrawn    float32   `csv:"prevWithdrawn,omitempty" json:"prevWithdrawn,omitempty"`
+	PrevTransferIn   float32   `csv:"prevTransferIn,omitempty" json:"prevTransferIn,omitempty"`
+	PrevTransferOut  float32   `csv:"prevTransferOut,omitempty" json:"prevTransferOut,omitempty"`
+	PrevAmount       float32   `csv:"prevAmount,omitempty" json:"prevAmount,omitempty"`
+	PrevTimestamp    time.Time `csv:"prevTimestamp,omitempty" json:"prevTimestamp,omitempty"`
+	DeltaDeposited   float32   `csv:"deltaDeposited,omitempty" json:"deltaDeposited,omitempty"`
+	DeltaWithdrawn   float32   `csv:"deltaWithdrawn,omitempty" json:"deltaWithdrawn,omitempty"`
+	DeltaTransferIn  float32   `csv:"deltaTransferIn,omitempty" json:"deltaTransferIn,omitempty"`
+	DeltaTransferOut float32   `csv:"deltaTransferOut,omitempty" json:"deltaTransferOut,omitempty"`
+	DeltaAmount      float32   `csv:"deltaAmount,omitempty" json:"deltaAmount,omitempty"`
+	Deposited        float32   `csv:"deposited,omitempty" json:"deposited,omitempty"`
+	Withdrawn        float32   `csv:"withdrawn,omitempty" json:"withdrawn,omitempty"`
+	TransferIn       float32   `csv:"transferIn,omitempty" json:"transferIn,omitempty"`
+	TransferOut      float32   `csv:"transferOut,omitempty" json:"transferOut,omitempty"`
+	Amount           float32   `csv:"amount,omitempty" json:"amount,omitempty"`
+	PendingCredit    float32   `csv:"pendingCredit,omitempty" json:"pendingCredit,omitempty"`
+	PendingDebit     float32   `csv:"pendingDebit,omitempty" json:"pendingDebit,omitempty"`
+	ConfirmedDebit   float32   `csv:"confirmedDebit,omitempty" json:"confirmedDebit,omitempty"`
+	Timestamp        time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+	Addr             string    `csv:"addr,omitempty" json:"addr,omitempty"`
+	Script           string    `csv:"script,omitempty" json:"script,omitempty"`
+}
+
+// Transaction wallet transaction
+type Transaction struct {
+	Identity       string    `csv:"identity" json:"identity"`
+	TransactID     string    `csv:"transactID" json:"transactID"`
+	Account        float32   `csv:"account,omitempty" json:"account,omitempty"`
+	Currency       string    `csv:"currency,omitempty" json:"currency,omitempty"`
+	TransactType   string    `csv:"transactType,omitempty" json:"transactType,omitempty"`
+	Amount         float32   `csv:"amount,omitempty" json:"amount,omitempty"`
+	Fee            float32   `csv:"fee,omitempty" json:"fee,omitempty"`
+	TransactStatus string    `csv:"transactStatus,omitempty" json:"transactStatus,omitempty"`
+	Address        string    `csv:"address,omitempty" json:"address,omitempty"`
+	Tx             string    `csv:"tx,omitempty" json:"tx,omitempty"`
+	Text           string    `csv:"text,omitempty" json:"text,omitempty"`
+	TransactTime   time.Time `csv:"transactTime,omitempty" json:"transactTime,omitempty"`
+	Timestamp      time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+}
+
+// Commission commission rates
+type Commission struct {
+	Identity      string  `csv:"identity" json:"identity"`
+	MakerFee      float64 `csv:"makerFee,omitempty" json:"makerFee,omitempty"`
+	TakerFee      float64 `csv:"takerFee,omitempty" json:"takerFee,omitempty"`
+	SettlementFee float64 `csv:"settlementFee,omitempty" json:"settlementFee,omitempty"`
+	MaxFee        float64 `csv:"maxFee,omitempty" json:"maxFee,omitempty"`
+}
+
+// Affiliate affiliate status
+type Affiliate struct {
+	Identity        string    `csv:"identity" json:"identity"`
+	Account         float32   `csv:"account" json:"account"`
+	Currency        string    `csv:"currency" json:"currency"`
+	PrevPayout      float32   `csv:"prevPayout,omitempty" json:"prevPayout,omitempty"`
+	PrevTurnover    float32   `csv:"prevTurnover,omitempty" json:"prevTurnover,omitempty"`
+	PrevComm        float32   `csv:"prevComm,omitempty" json:"prevComm,omitempty"`
+	PrevTimestamp   time.Time `csv:"prevTimestamp,omitempty" json:"prevTimestamp,omitempty"`
+	ExecTurnover    float32   `csv:"execTurnover,omitempty" json:"execTurnover,omitempty"`
+	ExecComm        float32   `csv:"execComm,omitempty" json:"execComm,omitempty"`
+	TotalReferrals  float32   `csv:"totalReferrals,omitempty" json:"totalReferrals,omitempty"`
+	TotalTurnover   float32   `csv:"totalTurnover,omitempty" json:"totalTurnover,omitempty"`
+	TotalComm       float32   `csv:"totalComm,omitempty" json:"totalComm,omitempty"`
+	PayoutPcnt      float64   `csv:"payoutPcnt,omitempty" json:"payoutPcnt,omitempty"`
+	PendingPayout   float32   `csv:"pendingPayout,omitempty" json:"pendingPayout,omitempty"`
+	Timestamp       time.Time `csv:"timestamp,omitempty" json:"timestamp,omitempty"`
+	ReferrerAccount float64   `csv:"referrerAccount,omitempty" json:"referrerAccount,omitempty"`
+}
+
+// ConvertAccountModel convert ngerest account model to local model with
+// same field names, and set identity which the row belongs to.
+func ConvertAccountModel(id string, ori interface{}, converted interface{}) error {
+	if err := convertModel(ori, converted); err != nil {
+		return err
+	}
+
+	switch row := converted.(type) {
+	case *User:
+		row.Identity = id
+	case *Margin:
+		row.Identity = id
+	case *Wallet:
+		row.Identity = id
+	case *Transaction:
+		row.Identity = id
+	case *Commission:
+		row.Identity = id
+	case *Affiliate:
+		row.Identity = id
+	}
+
+	return nil
+}
+
+// signedGet request api path directly with api key signature in ctx,
+// used for endpoints which generated client can't handle properly.
+func signedGet(ctx context.Context, basePath, path string,
+	query url.Values, result interface{}) error {
+	location, err := url.Parse(basePath + path + "?" + query.Encode())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", location.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	if key, ok := ctx.Value(ngerest.ContextAPIKey).(ngerest.APIKey); ok {
+		expires := time.Now().Unix() + 5
+
+		req.Header.Set("api-key", key.Key)
+		req.Header.Set("api-expires", strconv.FormatInt(expires, 10))
+		req.Header.Set("api-signature", common.Signature(
+			key.Secret, "GET", location.RequestURI(), expires, ""))
+	}
+
+	rsp, err := (&http.Client{Transport: apiTransport()}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+
+	if rsp.StatusCode >= 300 {
+		return common.NewResponseError("", rsp, body)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// GetWalletHistory get a page of wallet history, it's requested directly
+// as generated client has no paging params for wallet history.
+func GetWalletHistory(ctx context.Context, basePath, currency string,
+	start, count int) ([]ngerest.Transaction, error) {
+	query := url.Values{}
+	query.Set("count", strconv.Itoa(count))
+	query.Set("start", strconv.Itoa(start))
+
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+
+	var history []ngerest.Transaction
+
+	err := signedGet(ctx, basePath, "/user/walletHistory", query, &history)
+
+	return history, err
+}
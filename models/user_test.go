@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"github.com/frozenpine/ngerest"
+)
+
+func TestGetWalletHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			expires, _ := strconv.ParseInt(r.Header.Get("api-expires"), 10, 64)
+
+			if r.Header.Get("api-key") != "key" ||
+				r.Header.Get("api-signature") != common.Signature(
+					"secret", "GET", r.URL.RequestURI(), expires, "") {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"message":"Signature not valid.","name":"HTTPError"}}`))
+				return
+			}
+
+			query := r.URL.Query()
+
+			if r.URL.Path != "/api/v1/user/walletHistory" ||
+				query.Get("currency") != "XBt" || query.Get("count") != "2" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			start, _ := strconv.Atoi(query.Get("start"))
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]ngerest.Transaction{
+				{TransactID: strconv.Itoa(start)},
+				{TransactID: strconv.Itoa(start + 1)},
+			})
+		}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(),
+		ngerest.ContextAPIKey, ngerest.APIKey{Key: "key", Secret: "secret"})
+
+	history, err := GetWalletHistory(ctx, server.URL+"/api/v1", "XBt", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 2 || history[0].TransactID != "2" {
+		t.Error("wallet history mismatch:", history)
+	}
+
+	ctx = context.WithValue(context.Background(),
+		ngerest.ContextAPIKey, ngerest.APIKey{Key: "key", Secret: "invalid"})
+
+	if _, err = GetWalletHistory(ctx, server.URL+"/api/v1", "XBt", 0, 2); err == nil {
+		t.Error("invalid signature should fail")
+	}
+}
+
+func TestConvertAccountModel(t *testing.T) {
+	var margin Margin
+
+	if err := ConvertAccountModel("a@b.com", &ngerest.Margin{
+		Account: 1, Currency: "XBt", WalletBalance: 1000}, &margin); err != nil {
+		t.Fatal(err)
+	}
+
+	if margin.Identity != "a@b.com" || margin.WalletBalance != 1000 {
+		t.Error("convert margin failed:", margin)
+	}
+
+	header, _, err := marshalCSVRecord(&margin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header[0] != "identity" || header[1] != "account" {
+		t.Error("identity should be the first column:", header)
+	}
+}
@@ -0,0 +1,33 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+func TestSplitArgs(t *testing.T) {
+	cases := map[string][]string{
+		"order get  --symbol XBTUSD":     {"order", "get", "--symbol", "XBTUSD"},
+		`order get --filter '{"a": 1}'`:  {"order", "get", "--filter", `{"a": 1}`},
+		`order new --text "hello world"`: {"order", "new", "--text", "hello world"},
+		`use account a\ b ""`:            {"use", "account", "a b", ""},
+		"   ":                            nil,
+	}
+
+	for line, expected := range cases {
+		args, err := SplitArgs(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("line %q, expected %q, got %q", line, expected, args)
+		}
+	}
+
+	if _, err := SplitArgs(`order get --filter '{"a": 1}`); err != common.ErrQuote {
+		t.Error("unterminated quote should fail:", err)
+	}
+}
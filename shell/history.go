@@ -0,0 +1,102 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// DefaultHistorySize default max history lines kept
+const DefaultHistorySize = 1000
+
+// History command line history, persisted in file if path specified
+type History struct {
+	lines []string
+	max   int
+	path  string
+}
+
+// Add append line to history, empty line or line same as last one
+// will be ignored.
+func (h *History) Add(line string) error {
+	line = strings.TrimSpace(line)
+
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return nil
+	}
+
+	h.lines = append(h.lines, line)
+
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+
+	if h.path == "" {
+		return nil
+	}
+
+	histFile, err := os.OpenFile(
+		h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer histFile.Close()
+
+	_, err = histFile.WriteString(line + "\n")
+
+	return err
+}
+
+// Len get history line count
+func (h *History) Len() int {
+	return len(h.lines)
+}
+
+// Line get history line by index, 0 is the oldest one
+func (h *History) Line(idx int) string {
+	if idx < 0 || idx >= len(h.lines) {
+		return ""
+	}
+
+	return h.lines[idx]
+}
+
+func (h *History) load() error {
+	histFile, err := os.OpenFile(h.path, os.O_RDONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer histFile.Close()
+
+	scanner := bufio.NewScanner(histFile)
+
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+
+	return scanner.Err()
+}
+
+// NewHistory create history with max lines, history will be loaded from
+// & persisted in path, empty path means history only kept in memory.
+func NewHistory(path string, max int) *History {
+	if max < 1 {
+		max = DefaultHistorySize
+	}
+
+	history := History{max: max, path: path}
+
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			history.load()
+		}
+	}
+
+	return &history
+}
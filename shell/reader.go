@@ -0,0 +1,349 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Completer get completion candidates for the last word in line,
+// line is the content before cursor.
+type Completer func(line string) []string
+
+// Reader read line from terminal with line editing, history and completion,
+// if input is not a terminal, lines will be read without editing.
+type Reader struct {
+	Completer Completer
+
+	history  *History
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	terminal bool
+
+	buf    []rune
+	cursor int
+	prompt string
+}
+
+// ReadLine read a line with prompt, io.EOF will be returned if input
+// closed or Ctrl-D pressed with empty line, common.ErrInterrupt will
+// be returned if Ctrl-C pressed.
+func (r *Reader) ReadLine(prompt string) (string, error) {
+	r.prompt, r.buf, r.cursor = prompt, nil, 0
+
+	if !r.terminal {
+		return r.readPlain()
+	}
+
+	state, err := makeRaw(r.fd)
+	if err != nil {
+		return r.readPlain()
+	}
+	defer restore(r.fd, state)
+
+	return r.readEdit()
+}
+
+func (r *Reader) readPlain() (string, error) {
+	fmt.Fprint(r.out, r.prompt)
+
+	line, err := r.in.ReadString('\n')
+
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (r *Reader) readEdit() (string, error) {
+	histIdx := r.history.Len()
+	var pending []rune
+
+	r.refresh()
+
+	for {
+		ch, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch ch {
+		case keyCR, keyLF:
+			fmt.Fprint(r.out, "\r\n")
+			return string(r.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(r.out, "^C\r\n")
+			return "", common.ErrInterrupt
+		case keyCtrlD:
+			if len(r.buf) == 0 {
+				fmt.Fprint(r.out, "\r\n")
+				return "", io.EOF
+			}
+			r.deleteRune()
+		case keyBackspace, keyDelete:
+			if r.cursor > 0 {
+				r.cursor--
+				r.deleteRune()
+			}
+		case keyCtrlA:
+			r.cursor = 0
+		case keyCtrlE:
+			r.cursor = len(r.buf)
+		case keyCtrlB:
+			r.moveCursor(-1)
+		case keyCtrlF:
+			r.moveCursor(1)
+		case keyCtrlK:
+			r.buf = r.buf[:r.cursor]
+		case keyCtrlU:
+			r.buf = append([]rune{}, r.buf[r.cursor:]...)
+			r.cursor = 0
+		case keyCtrlW:
+			r.deleteWord()
+		case keyCtrlL:
+			fmt.Fprint(r.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			histIdx, pending = r.switchHistory(histIdx, histIdx-1, pending)
+		case keyCtrlN:
+			histIdx, pending = r.switchHistory(histIdx, histIdx+1, pending)
+		case keyTab:
+			r.complete()
+		case keyEscape:
+			switch r.readEscape() {
+			case 'A':
+				histIdx, pending = r.switchHistory(histIdx, histIdx-1, pending)
+			case 'B':
+				histIdx, pending = r.switchHistory(histIdx, histIdx+1, pending)
+			case 'C':
+				r.moveCursor(1)
+			case 'D':
+				r.moveCursor(-1)
+			case 'H':
+				r.cursor = 0
+			case 'F':
+				r.cursor = len(r.buf)
+			case '~':
+				r.deleteRune()
+			}
+		default:
+			if unicode.IsPrint(ch) {
+				r.insert([]rune{ch})
+			}
+		}
+
+		r.refresh()
+	}
+}
+
+// readEscape read escape sequence for arrow, home, end & delete key,
+// final byte of sequence will be returned: A/B/C/D for arrows,
+// H for home, F for end, ~ for delete, 0 for unknown sequence.
+func (r *Reader) readEscape() rune {
+	ch, _, err := r.in.ReadRune()
+	if err != nil || (ch != '[' && ch != 'O') {
+		return 0
+	}
+
+	var code []rune
+
+	for {
+		ch, _, err = r.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+
+		if ch >= '0' && ch <= '9' || ch == ';' {
+			code = append(code, ch)
+			continue
+		}
+
+		break
+	}
+
+	if ch != '~' {
+		return ch
+	}
+
+	switch string(code) {
+	case "1", "7":
+		return 'H'
+	case "4", "8":
+		return 'F'
+	case "3":
+		return '~'
+	}
+
+	return 0
+}
+
+func (r *Reader) refresh() {
+	fmt.Fprintf(r.out, "\r%s%s\x1b[K", r.prompt, string(r.buf))
+
+	if back := len(r.buf) - r.cursor; back > 0 {
+		fmt.Fprintf(r.out, "\x1b[%dD", back)
+	}
+}
+
+func (r *Reader) moveCursor(offset int) {
+	r.cursor += offset
+
+	if r.cursor < 0 {
+		r.cursor = 0
+	} else if r.cursor > len(r.buf) {
+		r.cursor = len(r.buf)
+	}
+}
+
+func (r *Reader) insert(runes []rune) {
+	buf := make([]rune, 0, len(r.buf)+len(runes))
+	buf = append(buf, r.buf[:r.cursor]...)
+	buf = append(buf, runes...)
+	r.buf = append(buf, r.buf[r.cursor:]...)
+
+	r.cursor += len(runes)
+}
+
+func (r *Reader) deleteRune() {
+	if r.cursor < len(r.buf) {
+		r.buf = append(r.buf[:r.cursor], r.buf[r.cursor+1:]...)
+	}
+}
+
+func (r *Reader) deleteWord() {
+	start := r.cursor
+
+	for start > 0 && r.buf[start-1] == ' ' {
+		start--
+	}
+
+	for start > 0 && r.buf[start-1] != ' ' {
+		start--
+	}
+
+	r.buf = append(r.buf[:start], r.buf[r.cursor:]...)
+	r.cursor = start
+}
+
+// switchHistory replace line with history line, pending is the line
+// being edited before history navigation.
+func (r *Reader) switchHistory(from, to int, pending []rune) (int, []rune) {
+	if to < 0 || to > r.history.Len() {
+		return from, pending
+	}
+
+	if from == r.history.Len() {
+		pending = append([]rune{}, r.buf...)
+	}
+
+	if to == r.history.Len() {
+		r.buf = pending
+	} else {
+		r.buf = []rune(r.history.Line(to))
+	}
+
+	r.cursor = len(r.buf)
+
+	return to, pending
+}
+
+func (r *Reader) complete() {
+	if r.Completer == nil {
+		return
+	}
+
+	line := string(r.buf[:r.cursor])
+
+	word := line
+	if idx := strings.LastIndexAny(line, " \t"); idx >= 0 {
+		word = line[idx+1:]
+	}
+
+	var candidates []string
+	for _, c := range r.Completer(line) {
+		if strings.HasPrefix(c, word) {
+			candidates = append(candidates, c)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		fmt.Fprint(r.out, "\a")
+	case 1:
+		r.insert([]rune(candidates[0][len(word):] + " "))
+	default:
+		prefix := commonPrefix(candidates)
+
+		if len(prefix) > len(word) {
+			r.insert([]rune(prefix[len(word):]))
+			return
+		}
+
+		sort.Strings(candidates)
+		fmt.Fprintf(r.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	}
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
+
+// NewReader create line reader on stdin & stdout, history can be nil
+func NewReader(history *History) *Reader {
+	if history == nil {
+		history = NewHistory("", DefaultHistorySize)
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	reader := Reader{
+		history:  history,
+		in:       bufio.NewReader(os.Stdin),
+		out:      os.Stdout,
+		fd:       fd,
+		terminal: isTerminal(fd),
+	}
+
+	return &reader
+}
+
+// IsTerminal check if file descriptor is a terminal
+func IsTerminal(fd int) bool {
+	return isTerminal(fd)
+}
@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+func newTestReader(input string, history *History) *Reader {
+	if history == nil {
+		history = NewHistory("", DefaultHistorySize)
+	}
+
+	return &Reader{
+		history: history,
+		in:      bufio.NewReader(strings.NewReader(input)),
+		out:     ioutil.Discard,
+	}
+}
+
+func TestReaderEdit(t *testing.T) {
+	history := NewHistory("", DefaultHistorySize)
+	history.Add("order get")
+	history.Add("trade get")
+
+	cases := map[string]string{
+		"ordr\x7f\x7fder get\r":                 "order get",
+		"get\x01order \r":                       "order get",
+		"order gte\x1b[D\x1b[D\x1b[3~\x1b[Ct\r": "order get",
+		"abc\x15order\r":                        "order",
+		"order get\x17del\r":                    "order del",
+		"\x1b[A\x1b[A\r":                        "order get",
+		"xx\x1b[A\x1b[B\r":                      "xx",
+		"\x10\x10\x0e\r":                        "trade get",
+	}
+
+	for input, expected := range cases {
+		line, err := newTestReader(input, history).readEdit()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != expected {
+			t.Errorf("input %q, expected %q, got %q", input, expected, line)
+		}
+	}
+
+	if _, err := newTestReader("abc\x03", nil).readEdit(); err != common.ErrInterrupt {
+		t.Error("Ctrl-C should interrupt line:", err)
+	}
+
+	if _, err := newTestReader("\x04", nil).readEdit(); err != io.EOF {
+		t.Error("Ctrl-D on empty line should be EOF:", err)
+	}
+}
+
+func TestReaderComplete(t *testing.T) {
+	completer := func(line string) []string {
+		if strings.HasPrefix(line, "order ") {
+			return []string{"amend", "del", "get", "new"}
+		}
+
+		return []string{"order", "orderbook", "trade"}
+	}
+
+	cases := map[string]string{
+		"tr\tget\r":   "trade get",
+		"or\t\r":      "order",
+		"order g\t\r": "order get ",
+		"order x\t\r": "order x",
+	}
+
+	for input, expected := range cases {
+		reader := newTestReader(input, nil)
+		reader.Completer = completer
+
+		line, err := reader.readEdit()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != expected {
+			t.Errorf("input %q, expected %q, got %q", input, expected, line)
+		}
+	}
+}
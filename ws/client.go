@@ -0,0 +1,435 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/frozenpine/ngecli/logger"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// DefaultHeartbeat idle duration before sending ping
+	DefaultHeartbeat = 5 * time.Second
+	// DefaultTimeout timeout for handshake & pong after ping sent
+	DefaultTimeout = 10 * time.Second
+	// DefaultReconnectWait wait duration before reconnect
+	DefaultReconnectWait = 3 * time.Second
+)
+
+// Config websocket client config
+type Config struct {
+	URL    string
+	Key    string
+	Secret string
+
+	Heartbeat     time.Duration
+	Timeout       time.Duration
+	ReconnectWait time.Duration
+}
+
+// Client websocket client with local table images, it will reconnect
+// and resubscribe all topics when connection lost or heartbeat timeout.
+type Client struct {
+	cfg Config
+
+	conn     *websocket.Conn
+	connLock sync.Mutex
+	lastRecv int64
+
+	topics    map[string]bool
+	tables    map[string]*Table
+	cacheLock sync.Mutex
+
+	messages  chan *Message
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *Client) dial() (*websocket.Conn, error) {
+	location, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := strings.Replace(location.Scheme, "ws", "http", 1) +
+		"://" + location.Host
+
+	config, err := websocket.NewConfig(c.cfg.URL, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Dialer = &net.Dialer{Timeout: c.cfg.Timeout}
+
+	if c.cfg.Key != "" {
+		expires := time.Now().Add(c.cfg.Timeout).Unix()
+
+		path := location.Path
+		if location.RawQuery != "" {
+			path = path + "?" + location.RawQuery
+		}
+
+		config.Header.Set("api-key", c.cfg.Key)
+		config.Header.Set("api-expires", strconv.FormatInt(expires, 10))
+		config.Header.Set("api-signature", common.Signature(
+			c.cfg.Secret, "GET", path, expires, ""))
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// first message should be welcome info or auth error
+	var welcome Response
+
+	conn.SetReadDeadline(time.Now().Add(c.cfg.Timeout))
+
+	if err = websocket.JSON.Receive(conn, &welcome); err == nil &&
+		welcome.Error != "" {
+		err = fmt.Errorf("websocket handshake failed: %s", welcome.Error)
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	logger.Info(welcome.Info, zap.String("url", c.cfg.URL))
+
+	atomic.StoreInt64(&c.lastRecv, time.Now().UnixNano())
+
+	return conn, nil
+}
+
+func (c *Client) setConn(conn *websocket.Conn) {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	c.conn = conn
+}
+
+// dropConn close current connection to trigger reconnect
+func (c *Client) dropConn() {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+func (c *Client) sendText(content string) error {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.conn == nil {
+		return common.ErrWsClosed
+	}
+
+	return websocket.Message.Send(c.conn, content)
+}
+
+func (c *Client) send(op string, topics []string) error {
+	req := Request{Op: op}
+
+	for _, topic := range topics {
+		req.Args = append(req.Args, topic)
+	}
+
+	content, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	return c.sendText(string(content))
+}
+
+func (c *Client) getTable(name string) *Table {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	table, exist := c.tables[name]
+	if !exist {
+		table = NewTable(name)
+		c.tables[name] = table
+	}
+
+	return table
+}
+
+func (c *Client) subscribedTopics() []string {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+
+	return topics
+}
+
+func (c *Client) handle(rsp *Response) {
+	switch {
+	case rsp.Table != "":
+		rows, err := c.getTable(rsp.Table).Apply(rsp.Action, rsp.Keys, rsp.Data)
+		if err != nil {
+			logger.Warn(err.Error(), zap.String("table", rsp.Table))
+			return
+		}
+
+		if rows == nil {
+			return
+		}
+
+		select {
+		case c.messages <- &Message{
+			Table: rsp.Table, Action: rsp.Action, Rows: rows}:
+		case <-c.closed:
+		}
+	case rsp.Error != "":
+		logger.Error("Websocket request failed.",
+			zap.Int("status", rsp.Status), zap.String("error", rsp.Error))
+	case rsp.Subscribe != "":
+		logger.Info("Topic subscribed.", zap.String("topic", rsp.Subscribe))
+	case rsp.Unsubscribe != "":
+		logger.Info("Topic unsubscribed.", zap.String("topic", rsp.Unsubscribe))
+	case rsp.Info != "":
+		logger.Info(rsp.Info)
+	}
+}
+
+func (c *Client) receive(conn *websocket.Conn) {
+	for {
+		var content string
+
+		if err := websocket.Message.Receive(conn, &content); err != nil {
+			return
+		}
+
+		atomic.StoreInt64(&c.lastRecv, time.Now().UnixNano())
+
+		if content == pongMessage {
+			continue
+		}
+
+		var rsp Response
+
+		if err := json.Unmarshal([]byte(content), &rsp); err != nil {
+			logger.Warn(err.Error(), zap.String("message", content))
+			continue
+		}
+
+		c.handle(&rsp)
+	}
+}
+
+func (c *Client) reconnect() *websocket.Conn {
+	for {
+		select {
+		case <-c.closed:
+			return nil
+		case <-time.After(c.cfg.ReconnectWait):
+		}
+
+		conn, err := c.dial()
+		if err != nil {
+			logger.Warn("Websocket reconnect failed.", zap.Error(err))
+			continue
+		}
+
+		select {
+		case <-c.closed:
+			conn.Close()
+			return nil
+		default:
+		}
+
+		c.cacheLock.Lock()
+		for _, table := range c.tables {
+			table.Reset()
+		}
+		c.cacheLock.Unlock()
+
+		c.setConn(conn)
+
+		if topics := c.subscribedTopics(); len(topics) > 0 {
+			if err := c.send(OpSubscribe, topics); err != nil {
+				conn.Close()
+				continue
+			}
+		}
+
+		return conn
+	}
+}
+
+func (c *Client) run(conn *websocket.Conn) {
+	defer close(c.messages)
+
+	for {
+		c.receive(conn)
+
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+
+		logger.Warn("Websocket disconnected, reconnecting...",
+			zap.String("url", c.cfg.URL))
+
+		if conn = c.reconnect(); conn == nil {
+			return
+		}
+	}
+}
+
+func (c *Client) heartbeat() {
+	ticker := time.NewTicker(c.cfg.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+		}
+
+		idle := time.Since(time.Unix(0, atomic.LoadInt64(&c.lastRecv)))
+
+		if idle >= c.cfg.Heartbeat+c.cfg.Timeout {
+			logger.Warn("Websocket heartbeat timeout.",
+				zap.Duration("idle", idle))
+			c.dropConn()
+		} else if idle >= c.cfg.Heartbeat {
+			c.sendText(pingMessage)
+		}
+	}
+}
+
+// Connect connect to server and receive messages in background
+func (c *Client) Connect() error {
+	select {
+	case <-c.closed:
+		return common.ErrWsClosed
+	default:
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	c.setConn(conn)
+
+	go c.run(conn)
+	go c.heartbeat()
+
+	return nil
+}
+
+// Subscribe subscribe topics in format: table[:symbol],
+// topics will be resubscribed after reconnect.
+func (c *Client) Subscribe(topics ...string) error {
+	for _, topic := range topics {
+		if _, _, err := ParseTopic(topic); err != nil {
+			return err
+		}
+
+		if IsPrivate(topic) && c.cfg.Key == "" {
+			return common.ErrWsAuth
+		}
+	}
+
+	c.cacheLock.Lock()
+	for _, topic := range topics {
+		c.topics[topic] = true
+	}
+	c.cacheLock.Unlock()
+
+	return c.send(OpSubscribe, topics)
+}
+
+// Unsubscribe unsubscribe topics
+func (c *Client) Unsubscribe(topics ...string) error {
+	c.cacheLock.Lock()
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+	c.cacheLock.Unlock()
+
+	return c.send(OpUnsubscribe, topics)
+}
+
+// Messages get table data messages channel,
+// it will be closed after client closed.
+func (c *Client) Messages() <-chan *Message {
+	return c.messages
+}
+
+// Table get local table image by name
+func (c *Client) Table(name string) *Table {
+	return c.getTable(name)
+}
+
+// Close close connection and stop reconnecting
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+
+	return err
+}
+
+// NewClient create websocket client, zero durations in config will be
+// set to default values.
+func NewClient(cfg Config) *Client {
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = DefaultHeartbeat
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	if cfg.ReconnectWait <= 0 {
+		cfg.ReconnectWait = DefaultReconnectWait
+	}
+
+	client := Client{
+		cfg:      cfg,
+		topics:   make(map[string]bool),
+		tables:   make(map[string]*Table),
+		messages: make(chan *Message),
+		closed:   make(chan struct{}),
+	}
+
+	return &client
+}
@@ -0,0 +1,193 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testKey    = "testKey"
+	testSecret = "testSecret"
+)
+
+func newTestServer() (*Server, *httptest.Server, string) {
+	server := NewServer(map[string]string{testKey: testSecret})
+	http := httptest.NewServer(server)
+
+	url := "ws://" + strings.TrimPrefix(http.URL, "http://") + "/realtime"
+
+	return server, http, url
+}
+
+func waitMessage(t *testing.T, client *Client) *Message {
+	select {
+	case msg, ok := <-client.Messages():
+		if !ok {
+			t.Fatal("messages channel closed")
+		}
+
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait message timeout")
+	}
+
+	return nil
+}
+
+func TestClientSubscribe(t *testing.T) {
+	server, http, url := newTestServer()
+	defer http.Close()
+
+	keys := []string{"symbol", "id", "side"}
+
+	server.Publish(TableOrderBookL2, ActionInsert, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1.0, "side": "Buy", "size": 10.0},
+		{"symbol": "ETHUSD", "id": 2.0, "side": "Sell", "size": 20.0},
+	})
+
+	client := NewClient(Config{URL: url})
+	defer client.Close()
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Subscribe("order"); err == nil {
+		t.Fatal("private topic without key should fail")
+	}
+
+	if err := client.Subscribe("orderBookL2:XBTUSD"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := waitMessage(t, client)
+	if msg.Action != ActionPartial || len(msg.Rows) != 1 ||
+		msg.Rows[0]["symbol"] != "XBTUSD" {
+		t.Fatal("partial mismatch:", msg)
+	}
+
+	server.Publish(TableOrderBookL2, ActionUpdate, keys, []Row{
+		{"symbol": "ETHUSD", "id": 2.0, "side": "Sell", "size": 25.0},
+	})
+	server.Publish(TableOrderBookL2, ActionUpdate, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1.0, "side": "Buy", "size": 15.0},
+	})
+
+	msg = waitMessage(t, client)
+	if msg.Action != ActionUpdate || msg.Rows[0]["size"] != 15.0 {
+		t.Fatal("update mismatch:", msg)
+	}
+
+	server.Publish(TableOrderBookL2, ActionDelete, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1.0, "side": "Buy"},
+	})
+
+	msg = waitMessage(t, client)
+	if msg.Action != ActionDelete {
+		t.Fatal("delete mismatch:", msg)
+	}
+
+	if rows := client.Table(TableOrderBookL2).Rows(); len(rows) != 0 {
+		t.Fatal("local table mismatch:", rows)
+	}
+
+	client.Close()
+
+	if _, ok := <-client.Messages(); ok {
+		t.Fatal("messages channel should be closed")
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	server, http, url := newTestServer()
+	defer http.Close()
+
+	invalid := NewClient(Config{URL: url, Key: testKey, Secret: "invalid"})
+	defer invalid.Close()
+
+	if err := invalid.Connect(); err == nil ||
+		!strings.Contains(err.Error(), "Signature not valid.") {
+		t.Fatal("invalid signature should fail:", err)
+	}
+
+	client := NewClient(Config{URL: url, Key: testKey, Secret: testSecret})
+	defer client.Close()
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Subscribe("order"); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := waitMessage(t, client); msg.Action != ActionPartial {
+		t.Fatal("partial mismatch:", msg)
+	}
+
+	server.Publish(TableOrder, ActionInsert, []string{"orderID"}, []Row{
+		{"orderID": "1", "symbol": "XBTUSD"},
+	})
+
+	if msg := waitMessage(t, client); msg.Rows[0]["orderID"] != "1" {
+		t.Fatal("insert mismatch:", msg)
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	server, http, url := newTestServer()
+	defer http.Close()
+
+	client := NewClient(Config{
+		URL: url, ReconnectWait: 100 * time.Millisecond})
+	defer client.Close()
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Subscribe("trade")
+	waitMessage(t, client)
+
+	server.DropConnections()
+
+	// resubscribed after reconnect with a new partial
+	if msg := waitMessage(t, client); msg.Action != ActionPartial {
+		t.Fatal("resubscribe mismatch:", msg)
+	}
+
+	server.Publish(TableTrade, ActionInsert, nil, []Row{{"price": 1.0}})
+
+	if msg := waitMessage(t, client); msg.Rows[0]["price"] != 1.0 {
+		t.Fatal("insert mismatch:", msg)
+	}
+}
+
+func TestClientHeartbeat(t *testing.T) {
+	server, http, url := newTestServer()
+	defer http.Close()
+
+	server.IgnorePing = true
+
+	client := NewClient(Config{
+		URL:           url,
+		Heartbeat:     50 * time.Millisecond,
+		Timeout:       100 * time.Millisecond,
+		ReconnectWait: 50 * time.Millisecond,
+	})
+	defer client.Close()
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Subscribe("trade")
+	waitMessage(t, client)
+
+	// pong missing, connection should be dropped and resubscribed
+	if msg := waitMessage(t, client); msg.Action != ActionPartial {
+		t.Fatal("resubscribe mismatch:", msg)
+	}
+}
@@ -0,0 +1,104 @@
+package ws
+
+import (
+	"strings"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+// Realtime tables
+const (
+	TableOrder       = "order"
+	TableExecution   = "execution"
+	TablePosition    = "position"
+	TableTrade       = "trade"
+	TableOrderBookL2 = "orderBookL2"
+	TableQuote       = "quote"
+	TableInstrument  = "instrument"
+)
+
+// Table actions
+const (
+	ActionPartial = "partial"
+	ActionInsert  = "insert"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+)
+
+// Operations
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+)
+
+const (
+	pingMessage = "ping"
+	pongMessage = "pong"
+)
+
+// tables all realtime tables, value is true for private table
+var tables = map[string]bool{
+	TableOrder:       true,
+	TableExecution:   true,
+	TablePosition:    true,
+	TableTrade:       false,
+	TableOrderBookL2: false,
+	TableQuote:       false,
+	TableInstrument:  false,
+}
+
+// ParseTopic parse topic in format: table[:symbol]
+func ParseTopic(topic string) (table, symbol string, err error) {
+	parts := strings.SplitN(topic, ":", 2)
+
+	table = parts[0]
+	if len(parts) > 1 {
+		symbol = parts[1]
+	}
+
+	if _, exist := tables[table]; !exist {
+		err = common.ErrWsTopic
+	}
+
+	return
+}
+
+// IsPrivate check if topic is a private table which requires authentication
+func IsPrivate(topic string) bool {
+	table, _, _ := ParseTopic(topic)
+
+	return tables[table]
+}
+
+// Request operation request sent to server
+type Request struct {
+	Op   string        `json:"op"`
+	Args []interface{} `json:"args,omitempty"`
+}
+
+// Response message received from server
+type Response struct {
+	Info    string `json:"info,omitempty"`
+	Version string `json:"version,omitempty"`
+
+	Success     *bool    `json:"success,omitempty"`
+	Subscribe   string   `json:"subscribe,omitempty"`
+	Unsubscribe string   `json:"unsubscribe,omitempty"`
+	Request     *Request `json:"request,omitempty"`
+
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	Table  string   `json:"table,omitempty"`
+	Action string   `json:"action,omitempty"`
+	Keys   []string `json:"keys,omitempty"`
+	Data   []Row    `json:"data,omitempty"`
+}
+
+// Message table data message applied to local table image
+type Message struct {
+	Table  string
+	Action string
+	// Rows affected rows, updated rows are merged full rows
+	Rows []Row
+}
@@ -0,0 +1,284 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frozenpine/ngecli/common"
+
+	"golang.org/x/net/websocket"
+)
+
+type session struct {
+	conn   *websocket.Conn
+	authed bool
+	topics map[string]bool
+	lock   sync.Mutex
+}
+
+func (s *session) send(v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return websocket.Message.Send(s.conn, string(content))
+}
+
+func (s *session) filter(table string, rows []Row) []Row {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.topics[table] {
+		return rows
+	}
+
+	var filtered []Row
+
+	for _, row := range rows {
+		if symbol, ok := row["symbol"].(string); ok &&
+			s.topics[table+":"+symbol] {
+			filtered = append(filtered, row)
+		}
+	}
+
+	return filtered
+}
+
+// Server realtime server stand-in implemented the same protocol as NGE
+// realtime api with in memory tables, it's used for testing.
+type Server struct {
+	// IgnorePing server will not response pong for ping if true
+	IgnorePing bool
+
+	secrets  map[string]string
+	tables   map[string]*Table
+	sessions map[*session]bool
+	lock     sync.Mutex
+}
+
+func (s *Server) verify(req *http.Request) error {
+	key := req.Header.Get("api-key")
+
+	expires, err := strconv.ParseInt(req.Header.Get("api-expires"), 10, 64)
+	if err != nil {
+		return errors.New("Invalid api-expires.")
+	}
+
+	if expires < time.Now().Unix() {
+		return errors.New("This request has expired.")
+	}
+
+	secret, exist := s.secrets[key]
+	if !exist {
+		return errors.New("Invalid API Key.")
+	}
+
+	path := req.URL.Path
+	if req.URL.RawQuery != "" {
+		path = path + "?" + req.URL.RawQuery
+	}
+
+	if common.Signature(secret, "GET", path, expires, "") !=
+		req.Header.Get("api-signature") {
+		return errors.New("Signature not valid.")
+	}
+
+	return nil
+}
+
+func (s *Server) getTable(name string) *Table {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	table, exist := s.tables[name]
+	if !exist {
+		table = NewTable(name)
+		s.tables[name] = table
+	}
+
+	return table
+}
+
+func (s *Server) subscribe(sess *session, req *Request) {
+	for _, arg := range req.Args {
+		topic, _ := arg.(string)
+
+		name, _, err := ParseTopic(topic)
+		if err != nil {
+			sess.send(Response{Status: 400, Error: err.Error(), Request: req})
+			continue
+		}
+
+		if tables[name] && !sess.authed {
+			sess.send(Response{
+				Status: 401, Error: common.ErrWsAuth.Error(), Request: req})
+			continue
+		}
+
+		sess.lock.Lock()
+		sess.topics[topic] = true
+		sess.lock.Unlock()
+
+		success := true
+		sess.send(Response{Success: &success, Subscribe: topic, Request: req})
+
+		table := s.getTable(name)
+
+		sess.send(Response{
+			Table:  name,
+			Action: ActionPartial,
+			Keys:   table.Keys(),
+			Data:   sess.filter(name, table.Rows()),
+		})
+	}
+}
+
+func (s *Server) unsubscribe(sess *session, req *Request) {
+	for _, arg := range req.Args {
+		topic, _ := arg.(string)
+
+		sess.lock.Lock()
+		delete(sess.topics, topic)
+		sess.lock.Unlock()
+
+		success := true
+		sess.send(Response{Success: &success, Unsubscribe: topic, Request: req})
+	}
+}
+
+func (s *Server) serve(conn *websocket.Conn) {
+	defer conn.Close()
+
+	sess := session{conn: conn, topics: make(map[string]bool)}
+
+	if conn.Request().Header.Get("api-key") != "" {
+		if err := s.verify(conn.Request()); err != nil {
+			sess.send(Response{Status: 401, Error: err.Error()})
+			return
+		}
+
+		sess.authed = true
+	}
+
+	s.lock.Lock()
+	s.sessions[&sess] = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.sessions, &sess)
+		s.lock.Unlock()
+	}()
+
+	sess.send(Response{
+		Info: "Welcome to the NGE Realtime API.", Version: "stand-in"})
+
+	for {
+		var content string
+
+		if err := websocket.Message.Receive(conn, &content); err != nil {
+			return
+		}
+
+		if content == pingMessage {
+			if !s.IgnorePing {
+				sess.send(pongMessage)
+			}
+
+			continue
+		}
+
+		var req Request
+
+		if err := json.Unmarshal([]byte(content), &req); err != nil {
+			sess.send(Response{Status: 400, Error: "Unable to parse request."})
+			continue
+		}
+
+		switch req.Op {
+		case OpSubscribe:
+			s.subscribe(&sess, &req)
+		case OpUnsubscribe:
+			s.unsubscribe(&sess, &req)
+		default:
+			sess.send(Response{
+				Status: 400, Error: "Unknown or unsupported command.",
+				Request: &req})
+		}
+	}
+}
+
+// ServeHTTP serve websocket connection
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	websocket.Server{Handler: s.serve}.ServeHTTP(w, req)
+}
+
+// Publish apply action data to server table and publish to subscribers,
+// table's keys will be set by the first publish.
+func (s *Server) Publish(table, action string, keys []string, data []Row) error {
+	image := s.getTable(table)
+
+	if !image.Ready() {
+		image.Apply(ActionPartial, keys, nil)
+	}
+
+	if _, err := image.Apply(action, keys, data); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.lock.Unlock()
+
+	for _, sess := range sessions {
+		rows := sess.filter(table, data)
+		if len(rows) < 1 {
+			continue
+		}
+
+		sess.send(Response{Table: table, Action: action, Data: rows})
+	}
+
+	return nil
+}
+
+// Sessions get connected session count
+func (s *Server) Sessions() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.sessions)
+}
+
+// DropConnections close all connected sessions
+func (s *Server) DropConnections() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for sess := range s.sessions {
+		sess.conn.Close()
+	}
+}
+
+// NewServer create realtime server stand-in, secrets is api secrets
+// with api key as map key.
+func NewServer(secrets map[string]string) *Server {
+	server := Server{
+		secrets:  secrets,
+		tables:   make(map[string]*Table),
+		sessions: make(map[*session]bool),
+	}
+
+	return &server
+}
@@ -0,0 +1,208 @@
+package ws
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/frozenpine/ngecli/common"
+)
+
+// DefaultMaxRows max rows kept in table without keys, e.g. trade
+const DefaultMaxRows = 1000
+
+// Row table row in json object
+type Row map[string]interface{}
+
+func (r Row) key(keys []string) string {
+	values := make([]string, len(keys))
+
+	for idx, k := range keys {
+		values[idx] = fmt.Sprint(r[k])
+	}
+
+	return strings.Join(values, ":")
+}
+
+func (r Row) copy() Row {
+	row := make(Row, len(r))
+
+	for k, v := range r {
+		row[k] = v
+	}
+
+	return row
+}
+
+// Table local table image maintained by partial, insert, update & delete
+// actions, all actions before partial will be ignored.
+type Table struct {
+	Name string
+
+	keys    []string
+	rows    []Row
+	index   map[string]int
+	ready   bool
+	maxRows int
+	lock    sync.RWMutex
+}
+
+func (t *Table) reindex() {
+	t.index = make(map[string]int)
+
+	if len(t.keys) < 1 {
+		return
+	}
+
+	for idx, row := range t.rows {
+		t.index[row.key(t.keys)] = idx
+	}
+}
+
+func (t *Table) upsert(data []Row) []Row {
+	result := make([]Row, 0, len(data))
+
+	for _, d := range data {
+		row := d.copy()
+		result = append(result, d.copy())
+
+		if len(t.keys) > 0 {
+			k := row.key(t.keys)
+
+			if idx, exist := t.index[k]; exist {
+				t.rows[idx] = row
+				continue
+			}
+
+			t.index[k] = len(t.rows)
+		}
+
+		t.rows = append(t.rows, row)
+	}
+
+	if len(t.keys) < 1 && len(t.rows) > t.maxRows {
+		t.rows = t.rows[len(t.rows)-t.maxRows:]
+	}
+
+	return result
+}
+
+func (t *Table) update(data []Row) []Row {
+	var result []Row
+
+	for _, d := range data {
+		idx, exist := t.index[d.key(t.keys)]
+		if !exist {
+			continue
+		}
+
+		for k, v := range d {
+			t.rows[idx][k] = v
+		}
+
+		result = append(result, t.rows[idx].copy())
+	}
+
+	return result
+}
+
+func (t *Table) delete(data []Row) []Row {
+	var result []Row
+
+	for _, d := range data {
+		idx, exist := t.index[d.key(t.keys)]
+		if !exist {
+			continue
+		}
+
+		result = append(result, t.rows[idx])
+
+		t.rows = append(t.rows[:idx], t.rows[idx+1:]...)
+		t.reindex()
+	}
+
+	return result
+}
+
+// Apply apply action data to table, affected rows will be returned,
+// partial on a ready table will be merged into table, nil rows will be
+// returned if table is not ready for incremental actions.
+func (t *Table) Apply(action string, keys []string, data []Row) ([]Row, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	switch action {
+	case ActionPartial:
+		if !t.ready {
+			t.keys, t.rows, t.ready = keys, nil, true
+			t.reindex()
+		}
+
+		return t.upsert(data), nil
+	case ActionInsert, ActionUpdate, ActionDelete:
+		if !t.ready {
+			return nil, nil
+		}
+	default:
+		return nil, common.ErrWsAction
+	}
+
+	switch action {
+	case ActionInsert:
+		return t.upsert(data), nil
+	case ActionUpdate:
+		return t.update(data), nil
+	default:
+		return t.delete(data), nil
+	}
+}
+
+// Reset clear table image, table will be ready after next partial
+func (t *Table) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.keys, t.rows, t.ready = nil, nil, false
+	t.reindex()
+}
+
+// Ready check if table received partial
+func (t *Table) Ready() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.ready
+}
+
+// Keys get table's key columns
+func (t *Table) Keys() []string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return append([]string{}, t.keys...)
+}
+
+// Rows get copy of all rows in table
+func (t *Table) Rows() []Row {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	rows := make([]Row, len(t.rows))
+
+	for idx, row := range t.rows {
+		rows[idx] = row.copy()
+	}
+
+	return rows
+}
+
+// NewTable create empty table image
+func NewTable(name string) *Table {
+	table := Table{
+		Name:    name,
+		index:   make(map[string]int),
+		maxRows: DefaultMaxRows,
+	}
+
+	return &table
+}
@@ -0,0 +1,97 @@
+package ws
+
+import (
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	table := NewTable(TableOrderBookL2)
+	keys := []string{"symbol", "id", "side"}
+
+	if rows, err := table.Apply(ActionInsert, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1, "side": "Buy", "size": 10},
+	}); err != nil || rows != nil {
+		t.Fatal("insert before partial should be ignored:", rows, err)
+	}
+
+	if _, err := table.Apply("unknown", keys, nil); err == nil {
+		t.Fatal("unknown action should fail")
+	}
+
+	table.Apply(ActionPartial, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1, "side": "Buy", "size": 10},
+		{"symbol": "XBTUSD", "id": 2, "side": "Sell", "size": 20},
+	})
+
+	if !table.Ready() || len(table.Rows()) != 2 {
+		t.Fatal("partial failed:", table.Rows())
+	}
+
+	table.Apply(ActionInsert, keys, []Row{
+		{"symbol": "XBTUSD", "id": 3, "side": "Sell", "size": 30},
+	})
+
+	rows, _ := table.Apply(ActionUpdate, keys, []Row{
+		{"symbol": "XBTUSD", "id": 2, "side": "Sell", "size": 25},
+		{"symbol": "XBTUSD", "id": 9, "side": "Sell", "size": 1},
+	})
+	if len(rows) != 1 || rows[0]["size"] != 25 || rows[0]["symbol"] != "XBTUSD" {
+		t.Fatal("update should return merged full rows:", rows)
+	}
+
+	rows, _ = table.Apply(ActionDelete, keys, []Row{
+		{"symbol": "XBTUSD", "id": 1, "side": "Buy"},
+	})
+	if len(rows) != 1 {
+		t.Fatal("delete failed:", rows)
+	}
+
+	result := table.Rows()
+	if len(result) != 2 || result[0]["id"] != 2 || result[1]["id"] != 3 {
+		t.Fatal("table content mismatch:", result)
+	}
+
+	// index should be rebuilt after delete
+	table.Apply(ActionUpdate, keys, []Row{
+		{"symbol": "XBTUSD", "id": 3, "side": "Sell", "size": 35},
+	})
+	if table.Rows()[1]["size"] != 35 {
+		t.Fatal("update after delete failed:", table.Rows())
+	}
+
+	table.Reset()
+	if table.Ready() || len(table.Rows()) != 0 {
+		t.Fatal("reset failed")
+	}
+}
+
+func TestTableWithoutKeys(t *testing.T) {
+	table := NewTable(TableTrade)
+	table.maxRows = 2
+
+	table.Apply(ActionPartial, nil, nil)
+
+	for idx := 0; idx < 3; idx++ {
+		table.Apply(ActionInsert, nil, []Row{{"price": idx}})
+	}
+
+	rows := table.Rows()
+	if len(rows) != 2 || rows[0]["price"] != 1 {
+		t.Fatal("rows should be trimmed to max rows:", rows)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	table, symbol, err := ParseTopic("trade:XBTUSD")
+	if err != nil || table != TableTrade || symbol != "XBTUSD" {
+		t.Fatal("parse topic failed:", table, symbol, err)
+	}
+
+	if _, _, err = ParseTopic("unknown"); err == nil {
+		t.Fatal("unknown table should fail")
+	}
+
+	if !IsPrivate("order:XBTUSD") || IsPrivate("trade") {
+		t.Fatal("private check failed")
+	}
+}